@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"fmt"
+	"os"
 
 	"github.com/spf13/cobra"
 	"github.com/tuanpep/oplusflow/internal/ops"
@@ -18,14 +19,47 @@ individual tasks with dependencies. This enables:
 - Progress tracking
 - Parallel work where dependencies allow
 
+Tasks can also be loaded from a structured YAML or JSON file with --from
+instead of markdown steps. The schema is:
+
+  version: 1
+  plan: plan-01-auth.md        # optional when plan-file is given
+  tasks:
+    - id: task-1               # required, unique
+      title: Add login handler # required
+      description: ...
+      files: [cmd/login.go]
+      dependencies: []         # ids of other tasks
+      verification: ["go test ./cmd/..."]
+      actions: [Create]
+      status: pending          # optional, defaults to pending
+
+Export an existing queue to the same schema with 'opusflow tasks export'.
+
 Examples:
   opusflow decompose plan-01-auth.md
-  opusflow decompose opusflow-planning/plans/plan-2024-01-01-feature.md`,
-	Args: cobra.ExactArgs(1),
+  opusflow decompose opusflow-planning/plans/plan-2024-01-01-feature.md
+  opusflow decompose --from tasks.yaml
+  opusflow decompose plan-01-auth.md --from tasks.json`,
+	Args: cobra.RangeArgs(0, 1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		planPath := args[0]
+		from, _ := cmd.Flags().GetString("from")
+
+		planPath := ""
+		if len(args) > 0 {
+			planPath = args[0]
+		}
 
-		tq, err := ops.QuickDecomposeFromFile(planPath)
+		var tq *ops.TaskQueue
+		var err error
+		if from != "" {
+			tq, err = ops.DecomposeFromTaskFile(from, planPath)
+		} else {
+			if planPath == "" {
+				return fmt.Errorf("plan-file required (or use --from to load a task file)")
+			}
+			tq, err = ops.QuickDecomposeFromFile(planPath)
+		}
 		if err != nil {
 			return fmt.Errorf("failed to decompose plan: %w", err)
 		}
@@ -154,6 +188,13 @@ var tasksStartCmd = &cobra.Command{
 			return err
 		}
 
+		agent, _ := cmd.Flags().GetString("agent")
+		if agent != "" {
+			if err := tq.AssignTask(taskID, agent); err != nil {
+				return err
+			}
+		}
+
 		if err := tq.Save(); err != nil {
 			return fmt.Errorf("failed to save: %w", err)
 		}
@@ -164,6 +205,175 @@ var tasksStartCmd = &cobra.Command{
 	},
 }
 
+var tasksBoardCmd = &cobra.Command{
+	Use:   "board",
+	Short: "Show all tasks across plans grouped by status",
+	Long: `Load every task queue under .opusflow/ and show a kanban-style board
+grouped by status.
+
+Examples:
+  opusflow tasks board
+  opusflow tasks board --status failed
+  opusflow tasks board --file cmd/root.go
+  opusflow tasks board --plan "plan-0*" --agent aider
+  opusflow tasks board --json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		status, _ := cmd.Flags().GetString("status")
+		file, _ := cmd.Flags().GetString("file")
+		plan, _ := cmd.Flags().GetString("plan")
+		agent, _ := cmd.Flags().GetString("agent")
+		asJSON, _ := cmd.Flags().GetBool("json")
+
+		if status != "" {
+			if err := ops.ValidateTaskStatus(status); err != nil {
+				return err
+			}
+		}
+
+		queues, err := ops.LoadAllTaskQueues()
+		if err != nil {
+			return fmt.Errorf("failed to load task queues: %w", err)
+		}
+
+		board := ops.BuildTaskBoard(queues, ops.TaskBoardFilter{
+			Status: status,
+			File:   file,
+			Plan:   plan,
+			Agent:  agent,
+		})
+
+		if asJSON {
+			out, err := board.FormatJSON()
+			if err != nil {
+				return fmt.Errorf("failed to format JSON: %w", err)
+			}
+			fmt.Println(out)
+			return nil
+		}
+
+		fmt.Println(board.FormatMarkdown())
+		return nil
+	},
+}
+
+var tasksExportCmd = &cobra.Command{
+	Use:   "export [plan-ref]",
+	Short: "Export a task queue to a task file or issue-tracker format",
+	Long: `Export a task queue as a structured task file (the schema accepted by
+'decompose --from') or as issues for an external tracker.
+
+Formats:
+  yaml, json           Structured task file
+  github-json          One GitHub issue per task; dependencies become labels
+  jira-csv             Jira CSV import; dependencies go to the "Blocked By" column
+  markdown-checklist   A markdown checklist of tasks
+
+Examples:
+  opusflow tasks export plan-01-auth.md                 # YAML to stdout
+  opusflow tasks export plan-01-auth.md --format json
+  opusflow tasks export plan-01-auth.md -o tasks.yaml
+  opusflow tasks export plan-01-auth.md -f github-json -o issues.json
+  opusflow tasks export plan-01-auth.md -o issues.csv   # jira-csv`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		planRef := args[0]
+		format, _ := cmd.Flags().GetString("format")
+		output, _ := cmd.Flags().GetString("output")
+
+		tq, err := ops.LoadTaskQueue(planRef)
+		if err != nil {
+			return fmt.Errorf("failed to load task queue: %w", err)
+		}
+
+		if output != "" && !cmd.Flags().Changed("format") {
+			if format, err = ops.ExportFormatFromPath(output); err != nil {
+				return err
+			}
+		}
+
+		data, err := ops.ExportTasks(tq, format)
+		if err != nil {
+			return err
+		}
+
+		if output == "" {
+			fmt.Print(string(data))
+			return nil
+		}
+
+		if err := os.WriteFile(output, data, 0644); err != nil {
+			return fmt.Errorf("failed to write output: %w", err)
+		}
+		fmt.Printf("Exported %d tasks to: %s\n", len(tq.Tasks), output)
+		return nil
+	},
+}
+
+var tasksImportCmd = &cobra.Command{
+	Use:   "import [plan-ref] [file]",
+	Short: "Import task status changes from an exported issue file",
+	Long: `Read back a file produced by 'tasks export' (after it was updated in the
+issue tracker) and apply status changes to the task queue. Closing an issue
+or checking a checklist item marks the task done; closing it as not planned
+(or a "Won't Do" Jira status) marks the task skipped.
+
+Exports record the plan revision. Adding or moving plan steps renumbers the
+tasks, so files exported before that are rejected; export again instead.
+
+Examples:
+  gh issue list --label opusflow --state all --json title,labels,state,stateReason > issues.json
+  opusflow tasks import plan-01-auth.md issues.json -f github-json
+  opusflow tasks import plan-01-auth.md jira-export.csv
+  opusflow tasks import plan-01-auth.md checklist.md`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		planRef := args[0]
+		file := args[1]
+		format, _ := cmd.Flags().GetString("format")
+
+		if format == "" {
+			var err error
+			if format, err = ops.ExportFormatFromPath(file); err != nil {
+				return err
+			}
+			if format == ops.TaskFileFormatJSON {
+				format = ops.IssueFormatGitHubJSON
+			}
+		}
+
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", file, err)
+		}
+
+		tq, err := ops.LoadTaskQueue(planRef)
+		if err != nil {
+			return fmt.Errorf("failed to load task queue: %w", err)
+		}
+
+		changes, err := ops.ImportIssueStatuses(tq, data, format)
+		if err != nil {
+			return err
+		}
+
+		if len(changes) == 0 {
+			fmt.Println("No status changes found.")
+			return nil
+		}
+
+		if err := tq.Save(); err != nil {
+			return fmt.Errorf("failed to save: %w", err)
+		}
+
+		for _, c := range changes {
+			fmt.Printf("- %s: %s → %s\n", c.TaskID, c.From, c.To)
+		}
+		fmt.Println(tq.GetProgress())
+		return nil
+	},
+}
+
 func init() {
 	rootCmd.AddCommand(decomposeCmd)
 	rootCmd.AddCommand(tasksCmd)
@@ -172,6 +382,23 @@ func init() {
 	tasksCmd.AddCommand(tasksNextCmd)
 	tasksCmd.AddCommand(tasksCompleteCmd)
 	tasksCmd.AddCommand(tasksStartCmd)
+	tasksCmd.AddCommand(tasksBoardCmd)
+	tasksCmd.AddCommand(tasksExportCmd)
+	tasksCmd.AddCommand(tasksImportCmd)
+
+	decomposeCmd.Flags().String("from", "", "Load tasks from a structured YAML/JSON task file")
 
 	tasksNextCmd.Flags().Bool("prompt", false, "Generate an AI prompt for the task")
+	tasksStartCmd.Flags().String("agent", "", "Agent working on the task")
+
+	tasksBoardCmd.Flags().String("status", "", "Only show tasks with this status")
+	tasksBoardCmd.Flags().String("file", "", "Only show tasks touching this file (path, suffix or glob)")
+	tasksBoardCmd.Flags().String("plan", "", "Only show plans matching this glob")
+	tasksBoardCmd.Flags().String("agent", "", "Only show tasks assigned to this agent")
+	tasksBoardCmd.Flags().Bool("json", false, "Output as JSON")
+
+	tasksExportCmd.Flags().StringP("format", "f", ops.TaskFileFormatYAML, "Output format: yaml, json, github-json, jira-csv, markdown-checklist")
+	tasksExportCmd.Flags().StringP("output", "o", "", "Output file (default: stdout)")
+
+	tasksImportCmd.Flags().StringP("format", "f", "", "Input format: github-json, jira-csv, markdown-checklist (default: from extension)")
 }
@@ -92,9 +92,23 @@ Examples:
 			return nil
 		}
 
+		// Record which agent picked up the task
+		if tq != nil {
+			if err := tq.AssignTask(task.ID, string(agentType)); err == nil {
+				tq.Save()
+			}
+		}
+
+		// Snapshot the plan the agent works from, and again afterwards in case it edits it
+		reason := fmt.Sprintf("%s %s", ops.RevisionReasonExec, task.ID)
+		if rev, err := ops.SnapshotPlan(tq.PlanPath, reason); err == nil && tq.PlanRevision > 0 && rev.Number != tq.PlanRevision {
+			fmt.Printf("⚠️  Plan changed since it was decomposed (revision %d, now %d); see 'opusflow plan diff %s'\n", tq.PlanRevision, rev.Number, tq.PlanRef)
+		}
+
 		// Execute with agent
 		fmt.Println("Executing task...")
 		result, err := ops.ExecuteWithAgent(task, config, tq.PlanPath)
+		_, _ = ops.SnapshotPlan(tq.PlanPath, reason)
 		if err != nil {
 			return fmt.Errorf("execution failed: %w", err)
 		}
@@ -9,6 +9,7 @@ import (
 	"github.com/mark3labs/mcp-go/server"
 	"github.com/spf13/cobra"
 	"github.com/tuanpep/oplusflow/internal/ops"
+	"github.com/tuanpep/oplusflow/internal/plan"
 )
 
 var mcpCmd = &cobra.Command{
@@ -19,6 +20,16 @@ var mcpCmd = &cobra.Command{
 		// The agent/client might see this in logs.
 		fmt.Fprintf(cmd.ErrOrStderr(), "Starting OpusFlow MCP Server %s\n", Version)
 		fmt.Fprintf(cmd.ErrOrStderr(), "  - create_plan\n")
+		fmt.Fprintf(cmd.ErrOrStderr(), "  - create_plan_from_spec\n")
+		fmt.Fprintf(cmd.ErrOrStderr(), "  - get_task_board\n")
+		fmt.Fprintf(cmd.ErrOrStderr(), "  - list_plans\n")
+		fmt.Fprintf(cmd.ErrOrStderr(), "  - lint_plan\n")
+		fmt.Fprintf(cmd.ErrOrStderr(), "  - check_spec\n")
+		fmt.Fprintf(cmd.ErrOrStderr(), "  - review_spec\n")
+		fmt.Fprintf(cmd.ErrOrStderr(), "  - trace_spec\n")
+		fmt.Fprintf(cmd.ErrOrStderr(), "  - generate_spec_tests\n")
+		fmt.Fprintf(cmd.ErrOrStderr(), "  - ingest_document\n")
+		fmt.Fprintf(cmd.ErrOrStderr(), "  - update_plan_section\n")
 		fmt.Fprintf(cmd.ErrOrStderr(), "  - generate_prompt\n")
 		fmt.Fprintf(cmd.ErrOrStderr(), "  - get_codebase_map (new)\n")
 		fmt.Fprintf(cmd.ErrOrStderr(), "  - list_files\n")
@@ -43,6 +54,9 @@ var mcpCmd = &cobra.Command{
 				mcp.Required(),
 				mcp.Description("The refined goal or query that this plan addresses"),
 			),
+			mcp.WithString("template",
+				mcp.Description("Plan template: feature (default), bugfix, refactor or a project template from .opusflow/templates"),
+			),
 		), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			args, ok := request.Params.Arguments.(map[string]interface{})
 			if !ok {
@@ -58,7 +72,47 @@ var mcpCmd = &cobra.Command{
 				return mcp.NewToolResultError("goal must be a string"), nil
 			}
 
-			result, err := ops.CreatePlan(title, goal)
+			templateName, _ := args["template"].(string)
+
+			result, err := ops.CreatePlan(title, goal, ops.PlanOptions{Template: templateName})
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("failed to create plan: %v", err)), nil
+			}
+
+			return mcp.NewToolResultText(fmt.Sprintf("Created plan: %s\nFilename: %s", result.FullPath, result.Filename)), nil
+		})
+
+		// Tool: create_plan_from_spec
+		s.AddTool(mcp.NewTool("create_plan_from_spec",
+			mcp.WithDescription("Create a plan pre-populated from an approved spec: goal, one step per functional requirement, architecture constraints and success criteria"),
+			mcp.WithString("spec_path",
+				mcp.Required(),
+				mcp.Description("Path or filename of the spec"),
+			),
+			mcp.WithString("title",
+				mcp.Description("Plan title (defaults to the spec title)"),
+			),
+			mcp.WithString("template",
+				mcp.Description("Plan template: feature (default), bugfix, refactor or a project template"),
+			),
+			mcp.WithBoolean("force",
+				mcp.Description("Plan even if the spec has not been approved"),
+			),
+		), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			args, ok := request.Params.Arguments.(map[string]interface{})
+			if !ok {
+				return mcp.NewToolResultError("invalid arguments"), nil
+			}
+
+			specPath, ok := args["spec_path"].(string)
+			if !ok {
+				return mcp.NewToolResultError("spec_path must be a string"), nil
+			}
+			title, _ := args["title"].(string)
+			templateName, _ := args["template"].(string)
+			force, _ := args["force"].(bool)
+
+			result, err := ops.CreatePlanFromSpec(specPath, title, ops.PlanOptions{Template: templateName}, force)
 			if err != nil {
 				return mcp.NewToolResultError(fmt.Sprintf("failed to create plan: %v", err)), nil
 			}
@@ -77,6 +131,9 @@ var mcpCmd = &cobra.Command{
 				mcp.Required(),
 				mcp.Description("The user's feature request or problem description"),
 			),
+			mcp.WithString("type",
+				mcp.Description("Spec template: feature (default), api, cli, library, ui, bugfix, migration or a project template"),
+			),
 		), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			args, ok := request.Params.Arguments.(map[string]interface{})
 			if !ok {
@@ -92,7 +149,9 @@ var mcpCmd = &cobra.Command{
 				return mcp.NewToolResultError("query must be a string"), nil
 			}
 
-			result, err := ops.CreateSpec(title, query, nil)
+			specType, _ := args["type"].(string)
+
+			result, err := ops.CreateSpec(title, query, ops.SpecOptions{Type: specType})
 			if err != nil {
 				return mcp.NewToolResultError(fmt.Sprintf("failed to create spec: %v", err)), nil
 			}
@@ -196,6 +255,408 @@ var mcpCmd = &cobra.Command{
 			return mcp.NewToolResultText(fmt.Sprintf("✅ Completed %s\n%s", taskID, tq.GetProgress())), nil
 		})
 
+		// Tool: get_task_board
+		s.AddTool(mcp.NewTool("get_task_board",
+			mcp.WithDescription("Show all tasks across every decomposed plan grouped by status (kanban view). Supports filters."),
+			mcp.WithString("status",
+				mcp.Description("Only include tasks with this status (pending, in_progress, done, failed, skipped)"),
+			),
+			mcp.WithString("file",
+				mcp.Description("Only include tasks touching this file (path, suffix or glob)"),
+			),
+			mcp.WithString("plan",
+				mcp.Description("Only include plans whose reference matches this glob"),
+			),
+			mcp.WithString("agent",
+				mcp.Description("Only include tasks assigned to this agent"),
+			),
+			mcp.WithString("format",
+				mcp.Description("Output format: 'markdown' (default) or 'json'"),
+			),
+		), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			args, _ := request.Params.Arguments.(map[string]interface{})
+
+			var filter ops.TaskBoardFilter
+			format := "markdown"
+			if args != nil {
+				filter.Status, _ = args["status"].(string)
+				filter.File, _ = args["file"].(string)
+				filter.Plan, _ = args["plan"].(string)
+				filter.Agent, _ = args["agent"].(string)
+				if f, ok := args["format"].(string); ok && f != "" {
+					format = f
+				}
+			}
+
+			if filter.Status != "" {
+				if err := ops.ValidateTaskStatus(filter.Status); err != nil {
+					return mcp.NewToolResultError(err.Error()), nil
+				}
+			}
+
+			queues, err := ops.LoadAllTaskQueues()
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("failed to load task queues: %v", err)), nil
+			}
+
+			board := ops.BuildTaskBoard(queues, filter)
+			if format == "json" {
+				output, err := board.FormatJSON()
+				if err != nil {
+					return mcp.NewToolResultError(fmt.Sprintf("failed to format: %v", err)), nil
+				}
+				return mcp.NewToolResultText(output), nil
+			}
+
+			return mcp.NewToolResultText(board.FormatMarkdown()), nil
+		})
+
+		// Tool: update_plan_section
+		s.AddTool(mcp.NewTool("update_plan_section",
+			mcp.WithDescription("Replace or append to one section of a plan (e.g. 'Observations', 'Proposed Changes' or 'Step 3') without rewriting the whole document. The result is validated with the plan parser."),
+			mcp.WithString("plan_path",
+				mcp.Required(),
+				mcp.Description("Path or filename of the plan"),
+			),
+			mcp.WithString("section",
+				mcp.Required(),
+				mcp.Description("Section title (e.g. 'Observations') or step ('Step 3')"),
+			),
+			mcp.WithString("content",
+				mcp.Required(),
+				mcp.Description("New markdown content for the section body, without its heading"),
+			),
+			mcp.WithString("mode",
+				mcp.Description("'replace' (default) or 'append'"),
+			),
+		), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			args, ok := request.Params.Arguments.(map[string]interface{})
+			if !ok {
+				return mcp.NewToolResultError("invalid arguments"), nil
+			}
+
+			planPath, ok := args["plan_path"].(string)
+			if !ok {
+				return mcp.NewToolResultError("plan_path must be a string"), nil
+			}
+			section, ok := args["section"].(string)
+			if !ok {
+				return mcp.NewToolResultError("section must be a string"), nil
+			}
+			content, ok := args["content"].(string)
+			if !ok {
+				return mcp.NewToolResultError("content must be a string"), nil
+			}
+			modeName, _ := args["mode"].(string)
+
+			mode, err := plan.ParseEditMode(modeName)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			result, err := ops.UpdatePlanSection(planPath, section, content, mode)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("failed to update section: %v", err)), nil
+			}
+
+			return mcp.NewToolResultText(fmt.Sprintf("Updated %q in %s (%s, revision %d)", result.Target, result.PlanPath, result.Mode, result.Revision)), nil
+		})
+
+		// Tool: list_plans
+		s.AddTool(mcp.NewTool("list_plans",
+			mcp.WithDescription("List plans with their index, title, status, task progress and last verification result"),
+			mcp.WithBoolean("include_archived",
+				mcp.Description("Include archived plans"),
+			),
+			mcp.WithString("format",
+				mcp.Description("Output format: 'text' (default) or 'json'"),
+			),
+		), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			args, _ := request.Params.Arguments.(map[string]interface{})
+
+			includeArchived := false
+			format := "text"
+			if args != nil {
+				includeArchived, _ = args["include_archived"].(bool)
+				if f, ok := args["format"].(string); ok && f != "" {
+					format = f
+				}
+			}
+
+			plans, err := ops.ListPlans(includeArchived)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("failed to list plans: %v", err)), nil
+			}
+
+			if format == "json" {
+				output, err := ops.FormatPlansJSON(plans)
+				if err != nil {
+					return mcp.NewToolResultError(fmt.Sprintf("failed to format: %v", err)), nil
+				}
+				return mcp.NewToolResultText(output), nil
+			}
+
+			return mcp.NewToolResultText(ops.FormatPlanTable(plans)), nil
+		})
+
+		// Tool: lint_plan
+		s.AddTool(mcp.NewTool("lint_plan",
+			mcp.WithDescription("Lint a plan for unfilled template placeholders, incomplete steps, missing MODIFY targets, duplicate step numbers and files shared between steps"),
+			mcp.WithString("plan_path",
+				mcp.Required(),
+				mcp.Description("Path or filename of the plan to lint"),
+			),
+			mcp.WithString("format",
+				mcp.Description("Output format: 'text' (default) or 'json'"),
+			),
+		), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			args, ok := request.Params.Arguments.(map[string]interface{})
+			if !ok {
+				return mcp.NewToolResultError("invalid arguments"), nil
+			}
+			planPath, ok := args["plan_path"].(string)
+			if !ok {
+				return mcp.NewToolResultError("plan_path must be a string"), nil
+			}
+			format, _ := args["format"].(string)
+
+			result, err := ops.LintPlanFile(planPath)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("failed to lint plan: %v", err)), nil
+			}
+
+			if format == "json" {
+				output, err := result.FormatJSON()
+				if err != nil {
+					return mcp.NewToolResultError(fmt.Sprintf("failed to format: %v", err)), nil
+				}
+				return mcp.NewToolResultText(output), nil
+			}
+
+			return mcp.NewToolResultText(result.FormatText()), nil
+		})
+
+		// Tool: check_spec
+		s.AddTool(mcp.NewTool("check_spec",
+			mcp.WithDescription("Score a spec's completeness per section and list unresolved placeholders, open questions and requirements without measurable criteria"),
+			mcp.WithString("spec_path",
+				mcp.Required(),
+				mcp.Description("Path or filename of the spec to check"),
+			),
+			mcp.WithNumber("min_score",
+				mcp.Description("Minimum completeness score (0-100) required for planning (default 80)"),
+			),
+			mcp.WithString("format",
+				mcp.Description("Output format: 'text' (default) or 'json'"),
+			),
+		), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			args, ok := request.Params.Arguments.(map[string]interface{})
+			if !ok {
+				return mcp.NewToolResultError("invalid arguments"), nil
+			}
+			specPath, ok := args["spec_path"].(string)
+			if !ok {
+				return mcp.NewToolResultError("spec_path must be a string"), nil
+			}
+			minScore, _ := args["min_score"].(float64)
+			format, _ := args["format"].(string)
+
+			result, err := ops.CheckSpecFile(specPath, int(minScore))
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("failed to check spec: %v", err)), nil
+			}
+
+			if format == "json" {
+				output, err := result.FormatJSON()
+				if err != nil {
+					return mcp.NewToolResultError(fmt.Sprintf("failed to format: %v", err)), nil
+				}
+				return mcp.NewToolResultText(output), nil
+			}
+
+			return mcp.NewToolResultText(result.FormatText()), nil
+		})
+
+		// Tool: review_spec
+		s.AddTool(mcp.NewTool("review_spec",
+			mcp.WithDescription("Record a review decision on a spec: submit (Draft → In Review), approve (In Review → Approved) or reject (In Review → Rejected, comment required). Plans can only be created from approved specs."),
+			mcp.WithString("spec_path",
+				mcp.Required(),
+				mcp.Description("Path or filename of the spec"),
+			),
+			mcp.WithString("action",
+				mcp.Required(),
+				mcp.Description("Review action: 'submit', 'approve' or 'reject'"),
+			),
+			mcp.WithString("by",
+				mcp.Description("Reviewer name (defaults to the git user)"),
+			),
+			mcp.WithString("comment",
+				mcp.Description("Review comment"),
+			),
+		), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			args, ok := request.Params.Arguments.(map[string]interface{})
+			if !ok {
+				return mcp.NewToolResultError("invalid arguments"), nil
+			}
+			specPath, ok := args["spec_path"].(string)
+			if !ok {
+				return mcp.NewToolResultError("spec_path must be a string"), nil
+			}
+			action, ok := args["action"].(string)
+			if !ok {
+				return mcp.NewToolResultError("action must be a string"), nil
+			}
+			by, _ := args["by"].(string)
+			comment, _ := args["comment"].(string)
+
+			result, err := ops.ReviewSpec(specPath, action, by, comment)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("failed to review spec: %v", err)), nil
+			}
+
+			return mcp.NewToolResultText(fmt.Sprintf("%s: %s → %s (by %s)", result.SpecPath, result.From, result.To, result.Review.By)), nil
+		})
+
+		// Tool: trace_spec
+		s.AddTool(mcp.NewTool("trace_spec",
+			mcp.WithDescription("Show a traceability matrix linking a spec's requirements (FR1, SC1, ...) to the plan tasks that implement them (**Implements**: lines), their status and verification evidence, including uncovered requirements"),
+			mcp.WithString("spec_path",
+				mcp.Required(),
+				mcp.Description("Path or filename of the spec"),
+			),
+			mcp.WithString("format",
+				mcp.Description("Output format: 'markdown' (default) or 'json'"),
+			),
+		), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			args, ok := request.Params.Arguments.(map[string]interface{})
+			if !ok {
+				return mcp.NewToolResultError("invalid arguments"), nil
+			}
+			specPath, ok := args["spec_path"].(string)
+			if !ok {
+				return mcp.NewToolResultError("spec_path must be a string"), nil
+			}
+			format, _ := args["format"].(string)
+
+			matrix, err := ops.TraceSpec(specPath)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("failed to trace spec: %v", err)), nil
+			}
+
+			if format == "json" {
+				output, err := matrix.FormatJSON()
+				if err != nil {
+					return mcp.NewToolResultError(fmt.Sprintf("failed to format: %v", err)), nil
+				}
+				return mcp.NewToolResultText(output), nil
+			}
+
+			return mcp.NewToolResultText(matrix.FormatMarkdown()), nil
+		})
+
+		// Tool: generate_spec_tests
+		s.AddTool(mcp.NewTool("generate_spec_tests",
+			mcp.WithDescription("Generate acceptance test skeletons (Go table-driven tests or a Gherkin feature) with one case per spec success criterion and edge case row, named after its requirement ID. The stubs are added to the front of the linked plan's task queue."),
+			mcp.WithString("spec_path",
+				mcp.Required(),
+				mcp.Description("Path or filename of the spec"),
+			),
+			mcp.WithString("lang",
+				mcp.Description("Test language: 'go' (default) or 'gherkin'"),
+			),
+			mcp.WithString("out_dir",
+				mcp.Description("Output directory relative to the project root (default: acceptance, or features for gherkin)"),
+			),
+			mcp.WithString("plan",
+				mcp.Description("Plan whose task queue receives the stub tasks (default: the decomposed plan linked to the spec)"),
+			),
+			mcp.WithBoolean("force",
+				mcp.Description("Overwrite an existing test file"),
+			),
+		), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			args, ok := request.Params.Arguments.(map[string]interface{})
+			if !ok {
+				return mcp.NewToolResultError("invalid arguments"), nil
+			}
+			specPath, ok := args["spec_path"].(string)
+			if !ok {
+				return mcp.NewToolResultError("spec_path must be a string"), nil
+			}
+			opts := ops.AcceptanceTestOptions{}
+			opts.Lang, _ = args["lang"].(string)
+			opts.OutDir, _ = args["out_dir"].(string)
+			opts.Plan, _ = args["plan"].(string)
+			opts.Force, _ = args["force"].(bool)
+
+			result, err := ops.GenerateAcceptanceTests(specPath, opts)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("failed to generate tests: %v", err)), nil
+			}
+
+			return mcp.NewToolResultText(ops.FormatAcceptanceTestResult(result)), nil
+		})
+
+		// Tool: ingest_document
+		s.AddTool(mcp.NewTool("ingest_document",
+			mcp.WithDescription("Replace a spec or plan with a completed SPEC.md/PLAN.md returned by an LLM. Chat wrappers and a fence around the document are stripped, the structure is validated against the expected sections, and the previous version is backed up."),
+			mcp.WithString("kind",
+				mcp.Required(),
+				mcp.Description("Document kind: 'spec' or 'plan'"),
+			),
+			mcp.WithString("path",
+				mcp.Required(),
+				mcp.Description("Path or filename of the spec or plan"),
+			),
+			mcp.WithString("content",
+				mcp.Required(),
+				mcp.Description("The model output containing the completed document"),
+			),
+			mcp.WithBoolean("dry_run",
+				mcp.Description("Validate and return the diff without writing"),
+			),
+			mcp.WithBoolean("force",
+				mcp.Description("Write even if the output fails validation"),
+			),
+		), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			args, ok := request.Params.Arguments.(map[string]interface{})
+			if !ok {
+				return mcp.NewToolResultError("invalid arguments"), nil
+			}
+			kind, _ := args["kind"].(string)
+			path, ok := args["path"].(string)
+			if !ok {
+				return mcp.NewToolResultError("path must be a string"), nil
+			}
+			content, ok := args["content"].(string)
+			if !ok {
+				return mcp.NewToolResultError("content must be a string"), nil
+			}
+			opts := ops.IngestOptions{}
+			opts.DryRun, _ = args["dry_run"].(bool)
+			opts.Force, _ = args["force"].(bool)
+
+			ingest := ops.IngestSpec
+			switch kind {
+			case "spec":
+			case "plan":
+				ingest = ops.IngestPlan
+			default:
+				return mcp.NewToolResultError("kind must be 'spec' or 'plan'"), nil
+			}
+
+			result, err := ingest(path, content, opts)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("failed to ingest %s: %v", kind, err)), nil
+			}
+
+			output := ops.FormatIngestResult(result)
+			if opts.DryRun {
+				output = result.Diff + "\n" + output
+			}
+			return mcp.NewToolResultText(output), nil
+		})
+
 		// Tool: generate_prompt
 		s.AddTool(mcp.NewTool("generate_prompt",
 			mcp.WithDescription("Generate a prompt for an AI agent"),
@@ -360,11 +821,18 @@ var mcpCmd = &cobra.Command{
 				return mcp.NewToolResultError("content must be a string"), nil
 			}
 
+			// Keep the previous version of plans agents rewrite
+			_, _ = ops.SnapshotIfPlan(path, ops.RevisionReasonBeforeWrite)
+
 			err := ops.WriteFile(path, content)
 			if err != nil {
 				return mcp.NewToolResultError(fmt.Sprintf("failed to write file: %v", err)), nil
 			}
 
+			if rev, err := ops.SnapshotIfPlan(path, ops.RevisionReasonWriteFile); err == nil && rev != nil {
+				return mcp.NewToolResultText(fmt.Sprintf("Successfully wrote to %s (plan revision %d)", path, rev.Number)), nil
+			}
+
 			return mcp.NewToolResultText(fmt.Sprintf("Successfully wrote to %s", path)), nil
 		})
 
@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/tuanpep/oplusflow/internal/frontmatter"
+	"github.com/tuanpep/oplusflow/internal/ops"
+)
+
+var metaCmd = &cobra.Command{
+	Use:   "meta",
+	Short: "Read and update spec, plan and verification metadata",
+	Long: `Read and update the YAML frontmatter of specs, plans and verification reports.
+
+Frontmatter fields: id, kind, status, owner, tags, created, updated,
+spec_ref, plan_ref and workflow_id. Unknown fields are preserved.
+
+Documents can be given as a path or as a bare filename from the
+plans, specs or verifications directory.
+
+Examples:
+  opusflow meta show plan-01-auth.md
+  opusflow meta show spec-2026-01-10-oauth.md --json
+  opusflow meta set plan-01-auth.md --owner alice --tag backend --tag auth`,
+}
+
+var metaShowCmd = &cobra.Command{
+	Use:   "show [file]",
+	Short: "Show a document's metadata",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		asJSON, _ := cmd.Flags().GetBool("json")
+
+		path, err := ops.ResolveDocumentPath(args[0])
+		if err != nil {
+			return err
+		}
+
+		m, err := ops.ReadDocumentMeta(path)
+		if err != nil {
+			return err
+		}
+		if m == nil {
+			return fmt.Errorf("%s has no frontmatter (add it with 'opusflow meta set')", args[0])
+		}
+
+		if asJSON {
+			data, err := json.MarshalIndent(m, "", "  ")
+			if err != nil {
+				return fmt.Errorf("failed to format metadata: %w", err)
+			}
+			fmt.Println(string(data))
+			return nil
+		}
+
+		out, err := m.Render("")
+		if err != nil {
+			return err
+		}
+		fmt.Print(out)
+		return nil
+	},
+}
+
+var metaSetCmd = &cobra.Command{
+	Use:   "set [file]",
+	Short: "Update a document's metadata",
+	Long: `Update fields in a document's frontmatter, adding frontmatter if the
+document has none. The updated timestamp is always bumped.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, err := ops.ResolveDocumentPath(args[0])
+		if err != nil {
+			return err
+		}
+
+		flags := cmd.Flags()
+		status, _ := flags.GetString("status")
+		owner, _ := flags.GetString("owner")
+		specRef, _ := flags.GetString("spec-ref")
+		addTags, _ := flags.GetStringSlice("tag")
+		removeTags, _ := flags.GetStringSlice("remove-tag")
+
+		m, err := ops.UpdateDocumentMeta(path, func(m *frontmatter.Meta) {
+			if flags.Changed("status") {
+				m.Status = status
+			}
+			if flags.Changed("owner") {
+				m.Owner = owner
+			}
+			if flags.Changed("spec-ref") {
+				m.SpecRef = specRef
+			}
+			for _, t := range addTags {
+				if !m.HasTag(t) {
+					m.Tags = append(m.Tags, t)
+				}
+			}
+			for _, t := range removeTags {
+				kept := m.Tags[:0]
+				for _, existing := range m.Tags {
+					if !strings.EqualFold(existing, t) {
+						kept = append(kept, existing)
+					}
+				}
+				m.Tags = kept
+			}
+		})
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("✅ Updated %s (%s, status: %s)\n", path, m.ID, m.Status)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(metaCmd)
+	metaCmd.AddCommand(metaShowCmd)
+	metaCmd.AddCommand(metaSetCmd)
+
+	metaShowCmd.Flags().Bool("json", false, "Output metadata as JSON")
+
+	metaSetCmd.Flags().String("status", "", "Set the status")
+	metaSetCmd.Flags().String("owner", "", "Set the owner")
+	metaSetCmd.Flags().String("spec-ref", "", "Set the referenced spec")
+	metaSetCmd.Flags().StringSlice("tag", nil, "Add a tag (repeatable)")
+	metaSetCmd.Flags().StringSlice("remove-tag", nil, "Remove a tag (repeatable)")
+}
@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/tuanpep/oplusflow/internal/ops"
+)
+
+var phaseCmd = &cobra.Command{
+	Use:   "phase",
+	Short: "Group plans into roadmap phases",
+	Long: `Manage roadmap phases in opusflow-planning/phases.
+
+A phase is a milestone of a multi-plan epic. Each phase file lists its
+plans in the order they should be worked on and a checklist of exit
+criteria. Phases are ordered by their number (phase-01-..., phase-02-...).
+
+A phase is Complete once every plan is done, every plan's latest
+verification passed and every exit criterion is checked off.
+
+Examples:
+  opusflow phase create "Authentication" --plan plan-01-login.md --plan plan-02-sessions.md
+  opusflow phase add 1 plan-03-logout.md
+  opusflow phase list
+  opusflow phase status
+  opusflow phase status 1`,
+}
+
+var phaseCreateCmd = &cobra.Command{
+	Use:   "create [title]",
+	Short: "Create a new roadmap phase",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		goal, _ := cmd.Flags().GetString("goal")
+		plans, _ := cmd.Flags().GetStringSlice("plan")
+		exit, _ := cmd.Flags().GetStringArray("exit")
+
+		ph, err := ops.CreatePhase(strings.Join(args, " "), goal, plans, exit)
+		if err != nil {
+			return fmt.Errorf("failed to create phase: %w", err)
+		}
+
+		fmt.Printf("Created phase: %s (%d plans)\n", ph.Path, len(ph.Plans))
+		return nil
+	},
+}
+
+var phaseAddCmd = &cobra.Command{
+	Use:   "add [phase] [plan-file...]",
+	Short: "Append plans to a phase",
+	Args:  cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ph, err := ops.AddPlansToPhase(args[0], args[1:])
+		if err != nil {
+			return fmt.Errorf("failed to add plans: %w", err)
+		}
+
+		fmt.Printf("✅ Phase %s now has %d plans: %s\n", ph.Filename, len(ph.Plans), strings.Join(ph.Plans, ", "))
+		return nil
+	},
+}
+
+var phaseListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List phases in roadmap order",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		phases, err := ops.ListPhases()
+		if err != nil {
+			return err
+		}
+
+		if len(phases) == 0 {
+			fmt.Println("No phases found. Create one with 'opusflow phase create'.")
+			return nil
+		}
+		for _, ph := range phases {
+			fmt.Printf("%02d  %-40s %d plans  %s\n", ph.Index, ph.Title, len(ph.Plans), ph.Filename)
+		}
+		return nil
+	},
+}
+
+var phaseStatusCmd = &cobra.Command{
+	Use:   "status [phase]",
+	Short: "Show task and verification progress for a phase or the whole roadmap",
+	Long: `Aggregate task and verification progress across a phase's plans.
+
+Without a phase, prints one summary line per phase in roadmap order.
+A phase can be given as a path, a filename or its number.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		asJSON, _ := cmd.Flags().GetBool("json")
+
+		var phases []*ops.Phase
+		if len(args) == 1 {
+			ph, err := ops.LoadPhase(args[0])
+			if err != nil {
+				return err
+			}
+			phases = append(phases, ph)
+		} else {
+			var err error
+			if phases, err = ops.ListPhases(); err != nil {
+				return err
+			}
+		}
+
+		progress := make([]*ops.PhaseProgress, 0, len(phases))
+		for _, ph := range phases {
+			pp, err := ops.GetPhaseProgress(ph)
+			if err != nil {
+				return err
+			}
+			progress = append(progress, pp)
+		}
+
+		if asJSON {
+			out, err := ops.FormatPhaseProgressJSON(progress)
+			if err != nil {
+				return fmt.Errorf("failed to format phase status: %w", err)
+			}
+			fmt.Println(out)
+			return nil
+		}
+
+		if len(args) == 1 {
+			fmt.Print(progress[0].FormatText())
+			return nil
+		}
+		fmt.Print(ops.FormatPhaseRoadmap(progress))
+		return nil
+	},
+}
+
+func init() {
+	phaseCreateCmd.Flags().String("goal", "", "What the phase delivers")
+	phaseCreateCmd.Flags().StringSlice("plan", nil, "Plan to include, in order (repeatable)")
+	phaseCreateCmd.Flags().StringArray("exit", nil, "Exit criterion (repeatable)")
+	phaseStatusCmd.Flags().Bool("json", false, "Output progress as JSON")
+
+	phaseCmd.AddCommand(phaseCreateCmd)
+	phaseCmd.AddCommand(phaseAddCmd)
+	phaseCmd.AddCommand(phaseListCmd)
+	phaseCmd.AddCommand(phaseStatusCmd)
+	rootCmd.AddCommand(phaseCmd)
+}
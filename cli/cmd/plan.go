@@ -2,35 +2,475 @@ package cmd
 
 import (
 	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/tuanpep/oplusflow/internal/manager"
 	"github.com/tuanpep/oplusflow/internal/ops"
+	"github.com/tuanpep/oplusflow/internal/plan"
 )
 
 var planCmd = &cobra.Command{
 	Use:   "plan [title]",
 	Short: "Create a new implementation plan",
-	Args:  cobra.MinimumNArgs(1),
+	Long: `Create a new implementation plan from a template.
+
+Built-in templates: feature (default), bugfix, refactor.
+Projects can override them, or add their own, by creating
+.opusflow/templates/plan-<name>.md. Templates are Go text/template
+files and can use: .Title, .Goal, .Author, .SpecRef, .SpecLink,
+.CodebaseSummary, .BuildCommand, .TestCommand, .Dependencies,
+.Context and .Environment. Plans created with --from-spec also get
+.Requirements, .RequirementIDs, .Constraints and .SpecCriteria, and the
+"inc" function for 1-based step numbers.
+
+With --from-spec the plan is pre-populated from the spec: its goal,
+one step per functional requirement (tagged with **Implements**: FR<n>),
+architecture constraints and success criteria, plus a link back to the
+spec. The title defaults to the spec title. Specs that have not been
+approved (opusflow spec approve) are refused unless --force is given.
+
+The title must be quoted when it has more than one word, so it can't be
+mistaken for a subcommand: opusflow plan lint checks plans, while
+opusflow plan "Lint config files" creates one.
+
+Examples:
+  opusflow plan "Add user authentication"
+  opusflow plan "Fix crash on empty config" --template bugfix
+  opusflow plan --from-spec spec-2026-01-10-oauth.md
+  opusflow plan templates`,
+	Args: planTitleArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		title := strings.Join(args, " ")
+		templateName, _ := cmd.Flags().GetString("template")
+		fromSpec, _ := cmd.Flags().GetString("from-spec")
+		force, _ := cmd.Flags().GetBool("force")
 
-		// For CLI usage, we default the goal to the title or empty if we don't prompt for it.
-		// A better CLI experience might flag for it. For now, let's just use the title as the goal.
-		goal := title
+		opts := ops.PlanOptions{Template: templateName}
 
-		result, err := ops.CreatePlan(title, goal)
+		var result *ops.CreatePlanResult
+		var err error
+		switch {
+		case fromSpec != "":
+			result, err = ops.CreatePlanFromSpec(fromSpec, title, opts, force)
+		case title == "":
+			return fmt.Errorf("requires a plan title or --from-spec")
+		default:
+			// Without a spec, the title doubles as the goal
+			result, err = ops.CreatePlan(title, title, opts)
+		}
 		if err != nil {
 			return fmt.Errorf("failed to create plan: %w", err)
 		}
 
-		fmt.Printf("Created plan: %s\n", result.FullPath)
+		fmt.Printf("Created plan: %s (template: %s)\n", result.FullPath, result.Template)
 		fmt.Printf("To fill this plan, run:\n")
 		fmt.Printf("  opusflow prompt plan %s\n", result.Filename)
 		return nil
 	},
 }
 
+// planTitleArgs accepts a single quoted title; unquoted words could start with
+// a subcommand name and be run as that subcommand instead
+func planTitleArgs(cmd *cobra.Command, args []string) error {
+	if len(args) > 1 {
+		return fmt.Errorf("the plan title must be one quoted argument: opusflow plan %q", strings.Join(args, " "))
+	}
+	return nil
+}
+
+var planLintCmd = &cobra.Command{
+	Use:   "lint [plan-file]",
+	Short: "Check a plan for unfilled placeholders and structural problems",
+	Long: `Lint a plan document before decomposing or executing it.
+
+Reported problems:
+- Unfilled template placeholders ([Step Title], [Absolute Path], ...)
+- Pre-requisites still set to TBD
+- Steps without **File**, **Action** or **Verification**
+- MODIFY targets that do not exist on disk
+- Duplicate step numbers
+- Files referenced by more than one step (warning)
+
+The command exits with a nonzero status when errors are found
+(or warnings, with --strict), so it can be used in CI.
+
+Examples:
+  opusflow plan lint plan-01-auth.md
+  opusflow plan lint opusflow-planning/plans/plan-01-auth.md --json
+  opusflow plan lint plan-01-auth.md --strict`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		asJSON, _ := cmd.Flags().GetBool("json")
+		strict, _ := cmd.Flags().GetBool("strict")
+
+		result, err := ops.LintPlanFile(args[0])
+		if err != nil {
+			return err
+		}
+
+		if asJSON {
+			output, err := result.FormatJSON()
+			if err != nil {
+				return fmt.Errorf("failed to format lint result: %w", err)
+			}
+			fmt.Println(output)
+		} else {
+			fmt.Print(result.FormatText())
+		}
+
+		if result.HasErrors() || (strict && result.Warnings > 0) {
+			return fmt.Errorf("plan lint failed: %d error(s), %d warning(s)", result.Errors, result.Warnings)
+		}
+		return nil
+	},
+}
+
+var planHistoryCmd = &cobra.Command{
+	Use:   "history [plan-file]",
+	Short: "List recorded revisions of a plan",
+	Long: `List the revisions recorded for a plan.
+
+A revision is snapshotted whenever the plan is decomposed, a task is
+executed against it (before and after the agent runs) or it is rewritten
+through the MCP write_file tool. Unchanged content does not create a new
+revision. The revision the task queue was decomposed from is marked.
+
+Examples:
+  opusflow plan history plan-01-auth.md
+  opusflow plan diff plan-01-auth.md --rev 2`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		planPath, err := ops.ResolvePlanPath(args[0])
+		if err != nil {
+			return err
+		}
+
+		revisions, err := ops.ListPlanRevisions(planPath)
+		if err != nil {
+			return err
+		}
+
+		decomposedFrom := 0
+		if tq, err := ops.LoadTaskQueue(filepath.Base(planPath)); err == nil {
+			decomposedFrom = tq.PlanRevision
+		}
+
+		fmt.Print(ops.FormatPlanHistory(planPath, revisions, decomposedFrom))
+		return nil
+	},
+}
+
+var planDiffCmd = &cobra.Command{
+	Use:   "diff [plan-file]",
+	Short: "Show what changed in a plan revision",
+	Long: `Show a unified diff of a plan revision.
+
+With --rev N, shows the changes introduced by revision N (compared with
+revision N-1). Without --rev, shows changes made to the plan since its
+latest recorded revision.
+
+Examples:
+  opusflow plan diff plan-01-auth.md --rev 3
+  opusflow plan diff plan-01-auth.md`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		rev, _ := cmd.Flags().GetInt("rev")
+
+		planPath, err := ops.ResolvePlanPath(args[0])
+		if err != nil {
+			return err
+		}
+
+		diff, err := ops.DiffPlanRevision(planPath, rev)
+		if err != nil {
+			return err
+		}
+
+		if diff == "" {
+			fmt.Println("No changes.")
+			return nil
+		}
+		fmt.Print(diff)
+		return nil
+	},
+}
+
+var planUpdateSectionCmd = &cobra.Command{
+	Use:   "update-section [plan-file] [section]",
+	Short: "Replace or append to one section of a plan",
+	Long: `Replace or append to a single section of a plan, leaving the rest of
+the document untouched.
+
+The section is a level-2 section title such as "Observations" or
+"Proposed Changes", or a step such as "Step 3" (the step heading is
+kept). Missing sections are added at the end of the plan.
+
+The new content comes from --content, or from --file ("-" reads stdin).
+The result is validated with the plan parser: the content must not add
+section or step headings, and must not introduce parse errors.
+
+Examples:
+  opusflow plan update-section plan-01-auth.md Observations --content "- **Current State**: no auth"
+  opusflow plan update-section plan-01-auth.md "Step 2" --file step2.md
+  git diff --stat | opusflow plan update-section plan-01-auth.md Observations --file - --append`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		content, _ := cmd.Flags().GetString("content")
+		file, _ := cmd.Flags().GetString("file")
+		appendMode, _ := cmd.Flags().GetBool("append")
+
+		switch {
+		case file == "-":
+			data, err := io.ReadAll(cmd.InOrStdin())
+			if err != nil {
+				return fmt.Errorf("failed to read stdin: %w", err)
+			}
+			content = string(data)
+		case file != "":
+			data, err := os.ReadFile(file)
+			if err != nil {
+				return fmt.Errorf("failed to read %s: %w", file, err)
+			}
+			content = string(data)
+		case !cmd.Flags().Changed("content"):
+			return fmt.Errorf("provide the new content with --content or --file")
+		}
+
+		mode := plan.EditReplace
+		if appendMode {
+			mode = plan.EditAppend
+		}
+
+		result, err := ops.UpdatePlanSection(args[0], args[1], content, mode)
+		if err != nil {
+			return fmt.Errorf("failed to update section: %w", err)
+		}
+
+		fmt.Printf("✅ Updated %q in %s (%s, revision %d)\n", result.Target, result.PlanPath, result.Mode, result.Revision)
+		return nil
+	},
+}
+
+var planFmtCmd = &cobra.Command{
+	Use:   "fmt [plan-file]",
+	Short: "Normalize headings and renumber steps",
+	Long: `Format a plan: normalize section and step headings, renumber steps
+sequentially in document order (so "Step 1, Step 2, Step 5" becomes
+"Step 1, Step 2, Step 3") and rewrite "**Depends on**: Step N" references
+to the new numbers.
+
+If the plan was decomposed, its task queue is renumbered to match and
+task status is kept. With --check the plan is not changed; the command
+prints the diff and exits nonzero if formatting is needed.
+
+Examples:
+  opusflow plan fmt plan-01-auth.md
+  opusflow plan fmt plan-01-auth.md --check`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		check, _ := cmd.Flags().GetBool("check")
+
+		result, err := ops.FormatPlanFile(args[0], check)
+		if err != nil {
+			return err
+		}
+
+		if !result.Changed {
+			fmt.Printf("✅ %s is already formatted\n", result.PlanPath)
+			return nil
+		}
+		if check {
+			fmt.Print(result.Diff)
+			return fmt.Errorf("%s needs formatting", result.PlanPath)
+		}
+
+		printPlanRewrite("Formatted", result)
+		return nil
+	},
+}
+
+var planStepCmd = &cobra.Command{
+	Use:   "step",
+	Short: "Add or move implementation steps",
+	Long: `Structural edits to a plan's implementation steps. Steps are
+renumbered afterwards, "**Depends on**" references are rewritten, and the
+plan's task queue (if any) is updated so existing tasks keep their status.
+
+Examples:
+  opusflow plan step add plan-01-auth.md --after 2 --title "Add rate limiting" --file internal/auth/limit.go
+  opusflow plan step move plan-01-auth.md 4 --to 1`,
+}
+
+var planStepAddCmd = &cobra.Command{
+	Use:   "add [plan-file]",
+	Short: "Insert a new step",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		after, _ := cmd.Flags().GetInt("after")
+		var step ops.NewStep
+		step.Title, _ = cmd.Flags().GetString("title")
+		step.File, _ = cmd.Flags().GetString("file")
+		step.Action, _ = cmd.Flags().GetString("action")
+		step.Description, _ = cmd.Flags().GetString("description")
+		step.Verification, _ = cmd.Flags().GetString("verify")
+
+		result, err := ops.AddPlanStep(args[0], after, step)
+		if err != nil {
+			return fmt.Errorf("failed to add step: %w", err)
+		}
+
+		printPlanRewrite(fmt.Sprintf("Added step %d to", after+1), result)
+		return nil
+	},
+}
+
+var planStepMoveCmd = &cobra.Command{
+	Use:   "move [plan-file] [step]",
+	Short: "Move a step to a new position",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		to, _ := cmd.Flags().GetInt("to")
+		if !cmd.Flags().Changed("to") {
+			return fmt.Errorf("--to is required")
+		}
+
+		from, err := strconv.Atoi(args[1])
+		if err != nil {
+			return fmt.Errorf("invalid step number %q", args[1])
+		}
+
+		result, err := ops.MovePlanStep(args[0], from, to)
+		if err != nil {
+			return fmt.Errorf("failed to move step: %w", err)
+		}
+
+		printPlanRewrite(fmt.Sprintf("Moved step %d to %d in", from, to), result)
+		return nil
+	},
+}
+
+// printPlanRewrite reports a structural plan edit
+func printPlanRewrite(action string, result *ops.PlanRewriteResult) {
+	fmt.Printf("✅ %s %s\n", action, result.PlanPath)
+	if result.TasksUpdated {
+		fmt.Println("   Task queue renumbered to match")
+	}
+}
+
+var planIngestCmd = &cobra.Command{
+	Use:   "ingest [plan-file] [output-file|-]",
+	Short: "Replace a plan with LLM output",
+	Long: `Replace a plan with the completed PLAN.md returned by an LLM (for
+example from "opusflow prompt plan").
+
+The output is read from a file, or from stdin when the file is "-" or
+omitted. Chat preambles and sign-offs, wrapper tags and a code fence
+around the whole document are stripped; fences inside the plan are kept.
+The plan's frontmatter is kept.
+
+The result must parse as a plan with a title, a Goal section and
+implementation steps; otherwise nothing is written unless --force is set.
+Sections of the previous version that are missing and other lint findings
+are reported as warnings. The previous version is saved to
+.opusflow/backups and recorded in the plan's revision history.
+
+Examples:
+  opusflow plan ingest plan-01-auth.md completed-plan.md
+  pbpaste | opusflow plan ingest plan-01-auth.md
+  opusflow plan ingest plan-01-auth.md answer.md --dry-run`,
+	Args:         cobra.RangeArgs(1, 2),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runIngest(cmd, args, ops.IngestPlan)
+	},
+}
+
+// runIngest reads model output from the file argument or stdin and ingests it
+// into the document named by the first argument
+func runIngest(cmd *cobra.Command, args []string, ingest func(ref, input string, opts ops.IngestOptions) (*ops.IngestResult, error)) error {
+	var data []byte
+	var err error
+	if len(args) < 2 || args[1] == "-" {
+		data, err = io.ReadAll(cmd.InOrStdin())
+	} else {
+		data, err = os.ReadFile(args[1])
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read output: %w", err)
+	}
+
+	opts := ops.IngestOptions{}
+	opts.DryRun, _ = cmd.Flags().GetBool("dry-run")
+	opts.Force, _ = cmd.Flags().GetBool("force")
+
+	result, err := ingest(args[0], string(data), opts)
+	if err != nil {
+		return err
+	}
+
+	if opts.DryRun {
+		fmt.Print(result.Diff)
+	}
+	fmt.Print(ops.FormatIngestResult(result))
+	if !result.Written && !opts.DryRun {
+		return fmt.Errorf("output failed validation")
+	}
+	return nil
+}
+
+var planTemplatesCmd = &cobra.Command{
+	Use:   "templates",
+	Short: "List available plan templates",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		root, err := manager.FindProjectRoot()
+		if err != nil {
+			return fmt.Errorf("failed to find project root: %w", err)
+		}
+
+		for _, t := range ops.ListPlanTemplates(root) {
+			fmt.Printf("%-12s %s\n", t.Name, t.Source)
+		}
+		return nil
+	},
+}
+
 func init() {
+	planCmd.Flags().StringP("template", "t", "", "Plan template name (feature, bugfix, refactor or a project template)")
+	planCmd.Flags().String("from-spec", "", "Pre-populate the plan from a spec file")
+	planCmd.Flags().Bool("force", false, "Plan from a spec even if it is not approved")
+	planCmd.AddCommand(planTemplatesCmd)
+	planCmd.AddCommand(planIngestCmd)
+	planIngestCmd.Flags().Bool("dry-run", false, "Validate and show the diff without writing")
+	planIngestCmd.Flags().Bool("force", false, "Write even if the output fails validation")
+	planLintCmd.Flags().Bool("json", false, "Output lint results as JSON")
+	planLintCmd.Flags().Bool("strict", false, "Treat warnings as errors")
+	planCmd.AddCommand(planLintCmd)
+	planDiffCmd.Flags().Int("rev", 0, "Revision to show (default: changes since the latest revision)")
+	planCmd.AddCommand(planHistoryCmd)
+	planUpdateSectionCmd.Flags().String("content", "", "New section content")
+	planUpdateSectionCmd.Flags().String("file", "", "Read the new content from a file (- for stdin)")
+	planUpdateSectionCmd.Flags().Bool("append", false, "Append to the section instead of replacing it")
+	planCmd.AddCommand(planUpdateSectionCmd)
+	planFmtCmd.Flags().Bool("check", false, "Report formatting changes without writing them")
+	planCmd.AddCommand(planFmtCmd)
+	planStepAddCmd.Flags().Int("after", 0, "Insert after this step (0 inserts before the first step)")
+	planStepAddCmd.Flags().String("title", "", "Step title")
+	planStepAddCmd.Flags().String("file", "", "File the step changes")
+	planStepAddCmd.Flags().String("action", "", "Create, Update or Delete")
+	planStepAddCmd.Flags().String("description", "", "What the step does")
+	planStepAddCmd.Flags().String("verify", "", "Command that verifies the step")
+	planStepMoveCmd.Flags().Int("to", 0, "New position of the step")
+	planStepCmd.AddCommand(planStepAddCmd)
+	planStepCmd.AddCommand(planStepMoveCmd)
+	planCmd.AddCommand(planStepCmd)
+	planCmd.AddCommand(planDiffCmd)
 	rootCmd.AddCommand(planCmd)
 }
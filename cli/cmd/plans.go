@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/tuanpep/oplusflow/internal/ops"
+)
+
+var plansCmd = &cobra.Command{
+	Use:   "plans",
+	Short: "List and manage existing plans",
+	Long: `List and manage the plans in opusflow-planning/plans.
+
+Each plan is shown with its index, title, status, task progress (from
+the task queue created by 'opusflow decompose') and the result of its
+most recent verification.
+
+Archived plans are moved to opusflow-planning/plans/archive. Their task
+queues are kept, so 'opusflow tasks' keeps working with an archived plan.
+
+Examples:
+  opusflow plans list
+  opusflow plans list --all --status Done
+  opusflow plans show plan-01-auth.md
+  opusflow plans archive plan-01-auth.md
+  opusflow plans reopen plan-01-auth.md`,
+}
+
+var plansListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List plans with status, task progress and last verification",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		all, _ := cmd.Flags().GetBool("all")
+		status, _ := cmd.Flags().GetString("status")
+		asJSON, _ := cmd.Flags().GetBool("json")
+
+		plans, err := ops.ListPlans(all || strings.EqualFold(status, ops.PlanStatusArchived))
+		if err != nil {
+			return err
+		}
+
+		if status != "" {
+			filtered := plans[:0]
+			for _, p := range plans {
+				if strings.EqualFold(p.Status, status) {
+					filtered = append(filtered, p)
+				}
+			}
+			plans = filtered
+		}
+
+		if asJSON {
+			out, err := ops.FormatPlansJSON(plans)
+			if err != nil {
+				return fmt.Errorf("failed to format plans: %w", err)
+			}
+			fmt.Println(out)
+			return nil
+		}
+
+		fmt.Print(ops.FormatPlanTable(plans))
+		return nil
+	},
+}
+
+var plansShowCmd = &cobra.Command{
+	Use:   "show [plan-file]",
+	Short: "Show a plan's status, task progress and last verification",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		asJSON, _ := cmd.Flags().GetBool("json")
+
+		summary, err := ops.GetPlanSummary(args[0])
+		if err != nil {
+			return err
+		}
+
+		if asJSON {
+			out, err := ops.FormatPlansJSON([]ops.PlanSummary{*summary})
+			if err != nil {
+				return fmt.Errorf("failed to format plan: %w", err)
+			}
+			fmt.Println(out)
+			return nil
+		}
+
+		fmt.Print(summary.FormatDetails())
+		return nil
+	},
+}
+
+var plansArchiveCmd = &cobra.Command{
+	Use:   "archive [plan-file]",
+	Short: "Move a plan to the archive",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		summary, err := ops.ArchivePlan(args[0])
+		if err != nil {
+			return fmt.Errorf("failed to archive plan: %w", err)
+		}
+
+		fmt.Printf("📦 Archived %s\n", summary.Path)
+		return nil
+	},
+}
+
+var plansReopenCmd = &cobra.Command{
+	Use:   "reopen [plan-file]",
+	Short: "Move an archived plan back to the plans directory",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		summary, err := ops.ReopenPlan(args[0])
+		if err != nil {
+			return fmt.Errorf("failed to reopen plan: %w", err)
+		}
+
+		fmt.Printf("✅ Reopened %s (status: %s)\n", summary.Path, summary.Status)
+		return nil
+	},
+}
+
+func init() {
+	plansListCmd.Flags().Bool("all", false, "Include archived plans")
+	plansListCmd.Flags().String("status", "", "Only show plans with this status (e.g. Draft, In Progress, Done, Archived)")
+	plansListCmd.Flags().Bool("json", false, "Output plans as JSON")
+	plansShowCmd.Flags().Bool("json", false, "Output the plan summary as JSON")
+
+	plansCmd.AddCommand(plansListCmd)
+	plansCmd.AddCommand(plansShowCmd)
+	plansCmd.AddCommand(plansArchiveCmd)
+	plansCmd.AddCommand(plansReopenCmd)
+	rootCmd.AddCommand(plansCmd)
+}
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"bytes"
+	"strings"
 	"testing"
 
 	"github.com/spf13/cobra"
@@ -94,3 +95,19 @@ func TestRootCommand_VersionFlag(t *testing.T) {
 		t.Error("Expected version output")
 	}
 }
+
+func TestPlanCommand_TitleArgs(t *testing.T) {
+	if err := planTitleArgs(planCmd, []string{"Add user authentication"}); err != nil {
+		t.Errorf("Expected a quoted title to be accepted, got %v", err)
+	}
+	err := planTitleArgs(planCmd, []string{"Add", "user", "authentication"})
+	if err == nil || !strings.Contains(err.Error(), `opusflow plan "Add user authentication"`) {
+		t.Errorf("Expected unquoted titles to be refused, got %v", err)
+	}
+
+	// A subcommand name is run as the subcommand, not taken as a title
+	found, _, err := rootCmd.Find([]string{"plan", "lint", "plan-01-x.md"})
+	if err != nil || found != planLintCmd {
+		t.Errorf("Expected plan lint to run the lint subcommand, got %s, %v", found.Name(), err)
+	}
+}
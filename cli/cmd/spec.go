@@ -3,9 +3,14 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/tuanpep/oplusflow/internal/manager"
 	"github.com/tuanpep/oplusflow/internal/ops"
+	"github.com/tuanpep/oplusflow/internal/orchestrator"
+	"github.com/tuanpep/oplusflow/internal/spec"
 )
 
 var specCmd = &cobra.Command{
@@ -23,10 +28,23 @@ The generated SPEC.md includes:
 - Edge cases
 - Success criteria
 
+Use --type to pick a template suited to the work: feature (default), api,
+cli, library, ui, bugfix or migration. Each adds its own sections (API
+contract, command interface, public API, user flows, reproduction steps,
+rollback plan) and edge cases. Projects can override a template, or add
+new types, with .opusflow/templates/spec-<type>.md (Go text/template with
+.Title, .Query, .Type, .Author, .CodebaseSummary and .Context).
+
+Relative --context paths are looked up from the current directory first,
+then from the project root.
+
 Examples:
   opusflow spec "Add user authentication with OAuth2"
+  opusflow spec "Add an export subcommand" --type cli
+  opusflow spec templates
   opusflow spec "Implement caching layer for API responses" -c config.yaml
-  opusflow spec "Build dashboard analytics" --context src/analytics/`,
+  opusflow spec "Build dashboard analytics" --context src/analytics/
+  opusflow spec "Speed up parsing" -c 'internal/**/*.go' --context-budget 20000`,
 	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		description := args[0]
@@ -41,9 +59,11 @@ Examples:
 		}
 
 		contextFiles, _ := cmd.Flags().GetStringSlice("context")
+		contextBudget, _ := cmd.Flags().GetInt("context-budget")
 		generatePrompt, _ := cmd.Flags().GetBool("prompt")
+		specType, _ := cmd.Flags().GetString("type")
 
-		result, err := ops.CreateSpec(title, description, contextFiles)
+		result, err := ops.CreateSpec(title, description, ops.SpecOptions{Type: specType, ContextFiles: contextFiles, ContextBudget: contextBudget})
 		if err != nil {
 			return fmt.Errorf("failed to create spec: %w", err)
 		}
@@ -51,6 +71,19 @@ Examples:
 		fmt.Println("✅ Created specification:")
 		fmt.Printf("   📄 File: %s\n", result.FullPath)
 		fmt.Printf("   📝 Title: %s\n", result.Title)
+		fmt.Printf("   🏷️  Type: %s\n", result.Type)
+
+		// Record the spec on the active workflow so the planning transition can check it
+		if orchestrator.ActiveWorkflowID() != "" {
+			ws, err := orchestrator.LoadWorkflowState()
+			if err == nil {
+				ws.SpecPath = result.FullPath
+				err = ws.Save()
+			}
+			if err != nil {
+				fmt.Printf("⚠️  Could not record the spec on the active workflow: %v\n", err)
+			}
+		}
 
 		if generatePrompt {
 			fmt.Println("\n--- AI Prompt ---")
@@ -63,13 +96,148 @@ Examples:
 			fmt.Println("\n💡 Next steps:")
 			fmt.Println("   1. Review and fill in the spec template")
 			fmt.Println("   2. Use 'opusflow spec --prompt' to generate AI assistance")
-			fmt.Println("   3. Once approved, create a plan: opusflow plan \"<title>\"")
+			fmt.Printf("      and load the answer with: opusflow spec ingest %s <answer.md>\n", result.Filename)
+			fmt.Printf("   3. Check completeness: opusflow spec check %s\n", result.Filename)
+			fmt.Println("   4. Once approved, create a plan: opusflow plan \"<title>\"")
+		}
+
+		return nil
+	},
+}
+
+var specCheckCmd = &cobra.Command{
+	Use:   "check [spec-file]",
+	Short: "Score a spec's completeness before planning",
+	Long: `Check how complete a specification is before it is planned.
+
+Each template section (Goal, Functional Requirements, Edge Cases,
+Success Criteria, and User Stories, Non-Functional Requirements,
+Architecture Constraints and Out of Scope when present) is scored by the
+share of its entries that no longer contain template placeholders.
+
+Also reported:
+- Unresolved placeholders such as [TODO: ...] or [User Role]
+- Open Questions that are not checked off ([x]) or struck through (~~...~~)
+- Requirements and success criteria that use vague terms ("fast",
+  "secure", "user-friendly", ...) without a number or literal to measure by
+
+The spec is ready for planning when its score reaches --min-score and no
+placeholders or open questions remain; otherwise the command exits with
+a nonzero status. With --strict, unmeasurable requirements also fail.
+"opusflow workflow transition planning" applies the same check.
+
+Examples:
+  opusflow spec check spec-2026-01-10-oauth.md
+  opusflow spec check spec-2026-01-10-oauth.md --min-score 100 --strict
+  opusflow spec check spec-2026-01-10-oauth.md --json`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		asJSON, _ := cmd.Flags().GetBool("json")
+		strict, _ := cmd.Flags().GetBool("strict")
+		minScore, _ := cmd.Flags().GetInt("min-score")
+
+		result, err := ops.CheckSpecFile(args[0], minScore)
+		if err != nil {
+			return err
+		}
+
+		if asJSON {
+			output, err := result.FormatJSON()
+			if err != nil {
+				return fmt.Errorf("failed to format spec check: %w", err)
+			}
+			fmt.Println(output)
+		} else {
+			fmt.Print(result.FormatText())
+		}
+
+		if !result.Ready() {
+			return fmt.Errorf("spec is not ready for planning: %s", strings.Join(result.Blockers(), ", "))
+		}
+		if strict && len(result.Unmeasurable) > 0 {
+			return fmt.Errorf("spec check failed: %d requirement(s) without measurable criteria", len(result.Unmeasurable))
+		}
+		return nil
+	},
+}
+
+var specSubmitCmd = &cobra.Command{
+	Use:   "submit [spec-file]",
+	Short: "Submit a spec for review (Draft → In Review)",
+	Long: `Submit a spec for review.
+
+Specs move Draft → In Review → Approved or Rejected. Each decision is
+recorded in the spec's frontmatter (reviews) and appended to the project
+audit log (.opusflow/audit.jsonl). Rejected specs can be revised and
+submitted again. Plans can only be created from approved specs.
+
+Examples:
+  opusflow spec submit spec-2026-01-10-oauth.md --comment "Ready for review"
+  opusflow spec approve spec-2026-01-10-oauth.md --by alice
+  opusflow spec reject spec-2026-01-10-oauth.md --by bob --comment "FR2 is not testable"
+  opusflow spec reviews spec-2026-01-10-oauth.md`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runSpecReview(cmd, args[0], ops.ReviewSubmit)
+	},
+}
+
+var specApproveCmd = &cobra.Command{
+	Use:   "approve [spec-file]",
+	Short: "Approve a spec under review (In Review → Approved)",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runSpecReview(cmd, args[0], ops.ReviewApprove)
+	},
+}
+
+var specRejectCmd = &cobra.Command{
+	Use:   "reject [spec-file]",
+	Short: "Reject a spec under review (In Review → Rejected); requires --comment",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runSpecReview(cmd, args[0], ops.ReviewReject)
+	},
+}
+
+var specReviewsCmd = &cobra.Command{
+	Use:   "reviews [spec-file]",
+	Short: "Show the review history of a spec",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		specPath, err := ops.ResolveSpecPath(args[0])
+		if err != nil {
+			return err
 		}
 
+		entries, err := ops.ReadAuditLog(specPath)
+		if err != nil {
+			return err
+		}
+
+		fmt.Print(ops.FormatReviewHistory(specPath, entries))
 		return nil
 	},
 }
 
+// runSpecReview applies a review action with the --by and --comment flags
+func runSpecReview(cmd *cobra.Command, ref, action string) error {
+	by, _ := cmd.Flags().GetString("by")
+	comment, _ := cmd.Flags().GetString("comment")
+
+	result, err := ops.ReviewSpec(ref, action, by, comment)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("✅ %s: %s → %s (by %s)\n", result.SpecPath, result.From, result.To, result.Review.By)
+	if result.To == spec.StatusApproved {
+		fmt.Printf("Next step: opusflow plan --from-spec %s\n", filepath.Base(result.SpecPath))
+	}
+	return nil
+}
+
 var specPromptCmd = &cobra.Command{
 	Use:   "prompt [spec-file]",
 	Short: "Generate an AI prompt to complete a spec",
@@ -96,14 +264,127 @@ var specPromptCmd = &cobra.Command{
 	},
 }
 
+var specTestsCmd = &cobra.Command{
+	Use:   "tests [spec-file]",
+	Short: "Generate acceptance test skeletons from a spec",
+	Long: `Generate acceptance test skeletons from a spec's Success Criteria and
+Edge Cases.
+
+Each filled-in success criterion and edge case row becomes one test case
+named after its requirement ID (SC1_..., EC1_... for the first edge case
+row), so failures point back to the spec. Cases skip until implemented.
+
+  --lang go       table-driven Go tests in <out>/<title>_acceptance_test.go
+  --lang gherkin  a feature file with one tagged scenario per case
+
+One task per case is added to the front of the task queue of the plan
+linked to the spec (or --plan), so the tests are written before the
+feature. Running the command again replaces those tasks and keeps their
+status.
+
+Examples:
+  opusflow spec tests spec-2026-01-10-oauth.md
+  opusflow spec tests spec-2026-01-10-oauth.md --lang gherkin --out features/auth
+  opusflow spec tests spec-2026-01-10-oauth.md --plan plan-03-oauth.md --force`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opts := ops.AcceptanceTestOptions{}
+		opts.Lang, _ = cmd.Flags().GetString("lang")
+		opts.OutDir, _ = cmd.Flags().GetString("out")
+		opts.Package, _ = cmd.Flags().GetString("package")
+		opts.Plan, _ = cmd.Flags().GetString("plan")
+		opts.Force, _ = cmd.Flags().GetBool("force")
+
+		result, err := ops.GenerateAcceptanceTests(args[0], opts)
+		if err != nil {
+			return err
+		}
+
+		fmt.Print(ops.FormatAcceptanceTestResult(result))
+		return nil
+	},
+}
+
+var specIngestCmd = &cobra.Command{
+	Use:   "ingest [spec-file] [output-file|-]",
+	Short: "Replace a spec with LLM output",
+	Long: `Replace a spec with the completed SPEC.md returned by an LLM (for
+example from "opusflow spec prompt").
+
+The output is read from a file, or from stdin when the file is "-" or
+omitted. Chat preambles and sign-offs, wrapper tags and a code fence
+around the whole document are stripped; fences inside the spec are kept.
+The spec's frontmatter is kept, but a spec that was in review, approved
+or rejected goes back to Draft when its content changes.
+
+The result must have a title and the Goal, Functional Requirements, Edge
+Cases and Success Criteria sections; otherwise nothing is written unless
+--force is set. Missing sections of the previous version and spec check
+blockers are reported as warnings. The previous version is saved to
+.opusflow/backups.
+
+Examples:
+  opusflow spec ingest spec-2026-01-10-oauth.md completed-spec.md
+  pbpaste | opusflow spec ingest spec-2026-01-10-oauth.md
+  opusflow spec ingest spec-2026-01-10-oauth.md answer.md --dry-run`,
+	Args:         cobra.RangeArgs(1, 2),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runIngest(cmd, args, ops.IngestSpec)
+	},
+}
+
+var specTemplatesCmd = &cobra.Command{
+	Use:   "templates",
+	Short: "List available spec templates (work types)",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		root, err := manager.FindProjectRoot()
+		if err != nil {
+			return fmt.Errorf("failed to find project root: %w", err)
+		}
+
+		for _, t := range ops.ListSpecTemplates(root) {
+			fmt.Printf("%-12s %s\n", t.Name, t.Source)
+		}
+		return nil
+	},
+}
+
 func init() {
 	rootCmd.AddCommand(specCmd)
 
 	specCmd.Flags().StringP("title", "t", "", "Custom title for the spec (default: derived from description)")
-	specCmd.Flags().StringSliceP("context", "c", nil, "Context files to include in the spec")
+	specCmd.Flags().StringSliceP("context", "c", nil, "Context files, directories or globs to include in the spec")
+	specCmd.Flags().Int("context-budget", ops.DefaultSpecContextBudget, "Total bytes of context shared across --context entries")
 	specCmd.Flags().Bool("prompt", false, "Also generate an AI prompt to complete the spec")
+	specCmd.Flags().String("type", "", "Spec template: feature, api, cli, library, ui, bugfix, migration or a project template")
+	specCmd.AddCommand(specTemplatesCmd)
 
 	// Add subcommand for generating prompts from existing specs
 	specCmd.AddCommand(specPromptCmd)
 	specPromptCmd.Flags().StringP("output", "o", "", "Output file for the prompt")
+
+	for _, c := range []*cobra.Command{specSubmitCmd, specApproveCmd, specRejectCmd} {
+		specCmd.AddCommand(c)
+		c.Flags().String("by", "", "Reviewer name (default: git user.name)")
+		c.Flags().String("comment", "", "Review comment")
+	}
+	specCmd.AddCommand(specReviewsCmd)
+
+	specCmd.AddCommand(specIngestCmd)
+	specIngestCmd.Flags().Bool("dry-run", false, "Validate and show the diff without writing")
+	specIngestCmd.Flags().Bool("force", false, "Write even if the output fails validation")
+
+	specCmd.AddCommand(specTestsCmd)
+	specTestsCmd.Flags().String("lang", ops.TestLangGo, "Test language: go or gherkin")
+	specTestsCmd.Flags().StringP("out", "o", "", "Output directory (default: acceptance, or features for gherkin)")
+	specTestsCmd.Flags().String("package", "", "Go package name (default: the package in the output directory)")
+	specTestsCmd.Flags().String("plan", "", "Plan whose task queue receives the stub tasks (default: the plan linked to the spec)")
+	specTestsCmd.Flags().Bool("force", false, "Overwrite an existing test file")
+
+	specCmd.AddCommand(specCheckCmd)
+	specCheckCmd.Flags().Int("min-score", ops.DefaultSpecMinScore, "Minimum completeness score (0-100) required for planning")
+	specCheckCmd.Flags().Bool("strict", false, "Also fail on requirements without measurable criteria")
+	specCheckCmd.Flags().Bool("json", false, "Output as JSON")
 }
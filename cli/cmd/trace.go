@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/tuanpep/oplusflow/internal/ops"
+)
+
+var traceCmd = &cobra.Command{
+	Use:   "trace [spec-file]",
+	Short: "Show a requirement traceability matrix for a spec",
+	Long: `Show how a spec's requirements are covered by plans, tasks and verification.
+
+Plan steps declare the requirements they implement with a line such as
+"**Implements**: FR1, SC2" (plans created with --from-spec already have
+one per step). Decomposing the plan copies the IDs into each task.
+
+For every functional requirement and success criterion the matrix lists
+the implementing tasks of plans linked to the spec, their status and the
+verification evidence: the tasks' verification commands, matching plan
+success criteria and the plan's latest verification report. A
+requirement is uncovered (no task), skipped (all its tasks skipped),
+planned, in progress, implemented (all tasks done, ignoring skipped ones)
+or verified (implemented and the plan's last verification passed).
+
+With --strict the command fails when any requirement is uncovered or skipped.
+
+Examples:
+  opusflow trace spec-2026-01-10-oauth.md
+  opusflow trace spec-2026-01-10-oauth.md --json
+  opusflow trace spec-2026-01-10-oauth.md --strict`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		asJSON, _ := cmd.Flags().GetBool("json")
+		strict, _ := cmd.Flags().GetBool("strict")
+
+		matrix, err := ops.TraceSpec(args[0])
+		if err != nil {
+			return err
+		}
+
+		if asJSON {
+			out, err := matrix.FormatJSON()
+			if err != nil {
+				return fmt.Errorf("failed to format trace: %w", err)
+			}
+			fmt.Println(out)
+		} else {
+			fmt.Print(matrix.FormatMarkdown())
+		}
+
+		if uncovered := matrix.Uncovered(); strict && len(uncovered) > 0 {
+			return fmt.Errorf("%d requirement(s) not covered by any task", len(uncovered))
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(traceCmd)
+
+	traceCmd.Flags().Bool("json", false, "Output as JSON")
+	traceCmd.Flags().Bool("strict", false, "Fail when a requirement is not covered by any task")
+}
@@ -2,8 +2,11 @@ package cmd
 
 import (
 	"fmt"
+	"path/filepath"
+	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/tuanpep/oplusflow/internal/manager"
 	"github.com/tuanpep/oplusflow/internal/ops"
 )
 
@@ -12,22 +15,74 @@ var verifyCmd = &cobra.Command{
 	Short: "Verify implementation against a plan",
 	Long: `Verify that the implementation matches the plan.
 
-The Critic phase checks:
-- Build status
-- Test status  
-- Files mentioned in plan exist
-- Git diff summary
+The Critic phase runs these checks, in order:
+- diff:     git diff summary
+- commands: build, typecheck, lint and test status
+- coverage: coverage thresholds (with --coverage; must follow commands)
+- files:    files mentioned in plan exist
+
+Executables in .opusflow/checks run as extra checks named after the file.
+.sh scripts run through sh and need no executable bit. On Windows checks
+are picked by extension instead: .exe, .bat, .cmd, .ps1 (run with
+PowerShell) and .sh (needs an sh, such as Git Bash's, on the PATH).
+They receive {"root", "plan", "changed_files"} as JSON on stdin and print
+{"status": "passed|failed|skipped", "comments": [{"severity", "title",
+"description", "files"}]}. The "checks" section of the config enables,
+disables or orders checks and sets their timeout.
+
+Commands come from the "commands" section of .opusflow/config.yaml, falling
+back to detection from manifest files (go.mod, Cargo.toml, package.json,
+pyproject.toml, Makefile).
+
+In a monorepo every directory with a manifest is a subproject (or list them
+under "subprojects" in the config). Only subprojects containing changed files
+are verified, and the report has a section per subproject.
+
+Test commands running go test, jest, vitest or pytest directly report each
+failing test separately; for wrappers such as "npm test" set a reporter.
+
+With --coverage, go test writes a cover profile automatically; other test
+commands declare the LCOV or Cobertura file they write. Coverage of the
+changed lines and of the whole project is compared with the thresholds:
+
+  commands:
+    build: go build ./...
+    test:
+      run: npm test -- --json --outputFile=jest-results.json
+      workdir: web
+      timeout: 5m
+      reporter: jest             # per-test results: go, jest, vitest, pytest, junit
+      report: jest-results.json  # file the reporter writes (in workdir)
+      coverage_report: coverage/lcov.info
+    lint:
+      skip: true
+  coverage:
+    enabled: true                # same as --coverage
+    min_total: 70
+    min_changed: 80
+  checks:
+    enabled: [diff, commands, files, no-todos]
+    timeout: 20m
+  subprojects:
+    - path: cli
+    - path: web
+      commands:
+        test: npm run test:unit
 
 Examples:
   opusflow verify plan-01-auth.md           # Auto verify
   opusflow verify plan.md --prompt          # Generate LLM prompt
-  opusflow verify plan.md --spec spec.md    # Include spec context`,
+  opusflow verify plan.md --spec spec.md    # Include spec context
+  opusflow verify plan.md --coverage        # Also measure coverage
+  opusflow verify commands                  # Show the commands verify runs
+  opusflow verify checks                    # Show the checks verify runs`,
 	Args: cobra.MinimumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		planFile := args[0]
 
 		generatePrompt, _ := cmd.Flags().GetBool("prompt")
 		specFile, _ := cmd.Flags().GetString("spec")
+		coverage, _ := cmd.Flags().GetBool("coverage")
 
 		if generatePrompt {
 			// Generate LLM verification prompt
@@ -42,10 +97,13 @@ Examples:
 
 		// Run automated verification
 		fmt.Println("Running automated verification...")
-		result, err := ops.AutoVerifyPlan(planFile)
+		result, err := ops.AutoVerifyPlan(planFile, ops.VerifyOptions{Coverage: coverage})
 		if err != nil {
 			return fmt.Errorf("verification failed: %w", err)
 		}
+		if specFile != "" {
+			result.SpecRef = filepath.Base(specFile)
+		}
 
 		// Save report
 		reportPath, err := result.Save()
@@ -62,9 +120,74 @@ Examples:
 	},
 }
 
+var verifyCommandsCmd = &cobra.Command{
+	Use:   "commands",
+	Short: "Show the subprojects and commands verify runs",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		root, err := manager.FindProjectRoot()
+		if err != nil {
+			return fmt.Errorf("failed to find project root: %w", err)
+		}
+
+		subs, err := ops.DiscoverSubprojects(root)
+		if err != nil {
+			return err
+		}
+		if len(subs) == 0 {
+			fmt.Println("No commands configured or detected. Add a commands section to .opusflow/config.yaml.")
+			return nil
+		}
+		for _, sub := range subs {
+			if len(subs) > 1 || sub.Path != "" {
+				fmt.Printf("%s (%s)\n", sub.Name, strings.Join(sub.Manifests, ", "))
+			}
+			if len(sub.Commands) == 0 {
+				fmt.Println("  no commands detected")
+			}
+			for _, pc := range sub.Commands {
+				line := fmt.Sprintf("  %-10s %-40s %-9s timeout %s", pc.Kind, pc.CommandLine(), pc.Source, pc.Timeout)
+				if pc.Reporter != "" {
+					line += ", reporter " + pc.Reporter
+				}
+				fmt.Println(line)
+			}
+		}
+		return nil
+	},
+}
+
+var verifyChecksCmd = &cobra.Command{
+	Use:   "checks",
+	Short: "Show the checks verify runs, in order",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		root, err := manager.FindProjectRoot()
+		if err != nil {
+			return fmt.Errorf("failed to find project root: %w", err)
+		}
+
+		cfg, err := ops.LoadProjectConfig(root)
+		if err != nil {
+			return err
+		}
+		checks, err := ops.ResolveChecks(root, cfg)
+		if err != nil {
+			return err
+		}
+		for _, c := range checks {
+			fmt.Printf("%-12s %s\n", c.Name(), ops.CheckSource(c))
+		}
+		return nil
+	},
+}
+
 func init() {
 	rootCmd.AddCommand(verifyCmd)
+	verifyCmd.AddCommand(verifyCommandsCmd)
+	verifyCmd.AddCommand(verifyChecksCmd)
 
 	verifyCmd.Flags().Bool("prompt", false, "Generate an LLM verification prompt instead of auto-verifying")
 	verifyCmd.Flags().String("spec", "", "Path to the spec file for additional context")
+	verifyCmd.Flags().Bool("coverage", false, "Collect test coverage, including coverage of the changed lines")
 }
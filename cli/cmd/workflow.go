@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/spf13/cobra"
+	"github.com/tuanpep/oplusflow/internal/ops"
 	"github.com/tuanpep/oplusflow/internal/orchestrator"
 )
 
@@ -96,17 +97,49 @@ var workflowNextCmd = &cobra.Command{
 var workflowTransitionCmd = &cobra.Command{
 	Use:   "transition [phase]",
 	Short: "Manually transition to a phase",
-	Long:  `Valid phases: idle, specification, planning, decomposition, execution, verification, complete, failed`,
-	Args:  cobra.ExactArgs(1),
+	Long: `Valid phases: idle, specification, planning, decomposition, execution, verification, complete, failed
+
+Planning is refused while the workflow's spec (set with --spec, or the
+last spec created during the workflow) has not been approved with
+"opusflow spec approve". Moving from specification to planning also runs
+"opusflow spec check" and is refused while the spec is incomplete or
+still has placeholders or open questions; --force skips that check.
+
+Examples:
+  opusflow workflow transition specification
+  opusflow workflow transition planning --spec spec-2026-01-10-oauth.md
+  opusflow workflow transition planning --force --reason "spike"`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		phase := orchestrator.Phase(args[0])
 		reason, _ := cmd.Flags().GetString("reason")
+		specRef, _ := cmd.Flags().GetString("spec")
+		force, _ := cmd.Flags().GetBool("force")
+		minScore, _ := cmd.Flags().GetInt("min-score")
 
 		ws, err := orchestrator.LoadWorkflowState()
 		if err != nil {
 			return fmt.Errorf("failed to load workflow: %w", err)
 		}
 
+		if specRef != "" {
+			specPath, err := ops.ResolveSpecPath(specRef)
+			if err != nil {
+				return err
+			}
+			ws.SpecPath = specPath
+		}
+
+		if ws.CurrentPhase == orchestrator.PhaseSpec && phase == orchestrator.PhasePlan && ws.SpecPath != "" && !force {
+			if result, err := ops.CheckSpecGate(ws.SpecPath, minScore); err != nil {
+				if result != nil {
+					fmt.Print(result.FormatText())
+				}
+				return fmt.Errorf("transition refused: %w", err)
+			}
+		}
+
 		if err := ws.Transition(phase, reason); err != nil {
 			return fmt.Errorf("transition failed: %w", err)
 		}
@@ -129,4 +162,7 @@ func init() {
 	workflowCmd.AddCommand(workflowTransitionCmd)
 
 	workflowTransitionCmd.Flags().String("reason", "", "Reason for the transition")
+	workflowTransitionCmd.Flags().String("spec", "", "Spec the workflow is planning from")
+	workflowTransitionCmd.Flags().Bool("force", false, "Skip the spec completeness check")
+	workflowTransitionCmd.Flags().Int("min-score", ops.DefaultSpecMinScore, "Minimum spec completeness score for planning")
 }
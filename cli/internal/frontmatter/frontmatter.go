@@ -0,0 +1,129 @@
+// Package frontmatter reads and writes the YAML metadata block at the top of
+// opusflow documents (specs, plans, phases and verification reports):
+//
+//	---
+//	id: plan-01-add-auth
+//	kind: plan
+//	status: Draft
+//	---
+//	# Add Auth
+package frontmatter
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Document kinds
+const (
+	KindSpec         = "spec"
+	KindPlan         = "plan"
+	KindVerification = "verification"
+	KindPhase        = "phase"
+)
+
+const delimiter = "---"
+
+// Meta is the metadata stored in a document's frontmatter
+type Meta struct {
+	ID         string    `yaml:"id" json:"id"`
+	Kind       string    `yaml:"kind" json:"kind"`
+	Status     string    `yaml:"status" json:"status"`
+	Owner      string    `yaml:"owner,omitempty" json:"owner,omitempty"`
+	Tags       []string  `yaml:"tags,omitempty" json:"tags,omitempty"`
+	Created    time.Time `yaml:"created,omitempty" json:"created,omitempty"`
+	Updated    time.Time `yaml:"updated,omitempty" json:"updated,omitempty"`
+	SpecRef    string    `yaml:"spec_ref,omitempty" json:"spec_ref,omitempty"`
+	PlanRef    string    `yaml:"plan_ref,omitempty" json:"plan_ref,omitempty"`
+	WorkflowID string    `yaml:"workflow_id,omitempty" json:"workflow_id,omitempty"`
+	Reviews    []Review  `yaml:"reviews,omitempty" json:"reviews,omitempty"`
+
+	// Extra keeps keys opusflow does not know about, so rewriting preserves them
+	Extra map[string]interface{} `yaml:",inline" json:"extra,omitempty"`
+}
+
+// Review is a review decision recorded on a document (submit, approve, reject)
+type Review struct {
+	Action  string    `yaml:"action" json:"action"`
+	By      string    `yaml:"by" json:"by"`
+	Comment string    `yaml:"comment,omitempty" json:"comment,omitempty"`
+	At      time.Time `yaml:"at" json:"at"`
+}
+
+// Split separates the frontmatter block from the document body. bodyLine is the
+// 1-based line number where the body starts (1 when there is no frontmatter).
+func Split(content string) (front, body string, bodyLine int, ok bool) {
+	first, rest, found := strings.Cut(content, "\n")
+	if !found || strings.TrimRight(first, "\r") != delimiter {
+		return "", content, 1, false
+	}
+
+	var fb strings.Builder
+	line := 2
+	for rest != "" {
+		l, next, _ := strings.Cut(rest, "\n")
+		if strings.TrimRight(l, "\r") == delimiter {
+			return fb.String(), next, line + 1, true
+		}
+		fb.WriteString(l)
+		fb.WriteString("\n")
+		rest = next
+		line++
+	}
+
+	// No closing delimiter: this is not frontmatter (e.g. a horizontal rule)
+	return "", content, 1, false
+}
+
+// Parse returns the document metadata (nil when the document has no
+// frontmatter) and the body
+func Parse(content string) (*Meta, string, error) {
+	front, body, _, ok := Split(content)
+	if !ok {
+		return nil, content, nil
+	}
+
+	var m Meta
+	if err := yaml.Unmarshal([]byte(front), &m); err != nil {
+		return nil, content, fmt.Errorf("invalid frontmatter: %w", err)
+	}
+	return &m, body, nil
+}
+
+// Render returns the metadata as a frontmatter block followed by body
+func (m *Meta) Render(body string) (string, error) {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(m); err != nil {
+		return "", fmt.Errorf("failed to encode frontmatter: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return "", fmt.Errorf("failed to encode frontmatter: %w", err)
+	}
+
+	return delimiter + "\n" + buf.String() + delimiter + "\n" + body, nil
+}
+
+// Touch sets Updated (and Created, if unset) to now
+func (m *Meta) Touch() {
+	now := time.Now().Truncate(time.Second)
+	if m.Created.IsZero() {
+		m.Created = now
+	}
+	m.Updated = now
+}
+
+// HasTag reports whether the metadata has the given tag (case-insensitive)
+func (m *Meta) HasTag(tag string) bool {
+	for _, t := range m.Tags {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+	return false
+}
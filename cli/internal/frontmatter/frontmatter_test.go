@@ -0,0 +1,66 @@
+package frontmatter
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		ok       bool
+		body     string
+		bodyLine int
+	}{
+		{"none", "# Title\n", false, "# Title\n", 1},
+		{"frontmatter", "---\nid: x\n---\n# Title\n", true, "# Title\n", 4},
+		{"crlf", "---\r\nid: x\r\n---\r\n# Title\r\n", true, "# Title\r\n", 4},
+		{"unterminated rule", "---\n# Title\n", false, "---\n# Title\n", 1},
+		{"rule later", "# Title\n---\n", false, "# Title\n---\n", 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, body, line, ok := Split(tt.content)
+			if ok != tt.ok || body != tt.body || line != tt.bodyLine {
+				t.Errorf("Split() = (%q, %d, %v); want (%q, %d, %v)", body, line, ok, tt.body, tt.bodyLine, tt.ok)
+			}
+		})
+	}
+}
+
+func TestParseRenderRoundTrip(t *testing.T) {
+	content := "---\nid: plan-01-auth\nkind: plan\nstatus: Draft\ntags:\n  - auth\nreviewer: bob\n---\n# Auth\n"
+
+	m, body, err := Parse(content)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if m.ID != "plan-01-auth" || m.Status != "Draft" || !m.HasTag("AUTH") {
+		t.Errorf("Unexpected meta: %+v", m)
+	}
+	if m.Extra["reviewer"] != "bob" {
+		t.Errorf("Expected unknown keys to be kept, got %v", m.Extra)
+	}
+
+	m.Status = "Done"
+	out, err := m.Render(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "status: Done\n") || !strings.Contains(out, "reviewer: bob\n") || !strings.HasSuffix(out, "---\n# Auth\n") {
+		t.Errorf("Unexpected render:\n%s", out)
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	if _, _, err := Parse("---\nid: [unclosed\n---\n"); err == nil {
+		t.Error("Expected error for invalid YAML")
+	}
+
+	m, body, err := Parse("# No frontmatter\n")
+	if err != nil || m != nil || body != "# No frontmatter\n" {
+		t.Errorf("Expected nil meta, got %+v, %q, %v", m, body, err)
+	}
+}
@@ -77,9 +77,59 @@ func initProjectRoot(rootDir string) (string, error) {
 	return rootDir, nil
 }
 
+// PlansDir returns the path of the plans directory without creating it
+func PlansDir(rootDir string) string {
+	return filepath.Join(rootDir, "opusflow-planning", "plans")
+}
+
+// ArchivedPlansDir returns the path of the plan archive without creating it
+func ArchivedPlansDir(rootDir string) string {
+	return filepath.Join(PlansDir(rootDir), "archive")
+}
+
+// PhasesDir returns the path of the roadmap phases directory without creating it
+func PhasesDir(rootDir string) string {
+	return filepath.Join(rootDir, "opusflow-planning", "phases")
+}
+
+// SpecsDir returns the path of the specs directory without creating it
+func SpecsDir(rootDir string) string {
+	return filepath.Join(rootDir, "opusflow-planning", "specs")
+}
+
+// TemplatesDir returns the directory holding project template overrides
+func TemplatesDir(rootDir string) string {
+	return filepath.Join(rootDir, ".opusflow", "templates")
+}
+
+// RevisionsDir returns the directory holding plan revision snapshots
+func RevisionsDir(rootDir string) string {
+	return filepath.Join(rootDir, ".opusflow", "revisions")
+}
+
+// BackupsDir returns the directory holding copies of documents replaced by ingest
+func BackupsDir(rootDir string) string {
+	return filepath.Join(rootDir, ".opusflow", "backups")
+}
+
+// ConfigPath returns the path of the project configuration file
+func ConfigPath(rootDir string) string {
+	return filepath.Join(rootDir, ".opusflow", "config.yaml")
+}
+
+// ChecksDir returns the directory holding script verification checks
+func ChecksDir(rootDir string) string {
+	return filepath.Join(rootDir, ".opusflow", "checks")
+}
+
+// AuditLogPath returns the path of the review audit log (one JSON entry per line)
+func AuditLogPath(rootDir string) string {
+	return filepath.Join(rootDir, ".opusflow", "audit.jsonl")
+}
+
 // GetPlanningDirs returns paths to plans and verifications dirs, creating them if needed
 func GetPlanningDirs(rootDir string) (plansDir, verifyDir string, err error) {
-	plansDir = filepath.Join(rootDir, "opusflow-planning", "plans")
+	plansDir = PlansDir(rootDir)
 	verifyDir = filepath.Join(rootDir, "opusflow-planning", "verifications")
 
 	if err = os.MkdirAll(plansDir, 0755); err != nil {
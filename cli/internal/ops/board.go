@@ -0,0 +1,222 @@
+package ops
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/tuanpep/oplusflow/internal/manager"
+)
+
+// TaskBoardFilter narrows down the tasks shown on a board.
+// Empty fields match everything.
+type TaskBoardFilter struct {
+	Status string // exact task status (pending, in_progress, done, failed, skipped)
+	File   string // file path, path suffix or glob referenced by the task
+	Plan   string // glob matched against the plan reference
+	Agent  string // agent that was assigned the task
+}
+
+// BoardTask is a task together with the plan it belongs to
+type BoardTask struct {
+	PlanRef string `json:"plan_ref"`
+	Task
+}
+
+// BoardColumn holds all tasks sharing a status
+type BoardColumn struct {
+	Status string      `json:"status"`
+	Tasks  []BoardTask `json:"tasks"`
+}
+
+// TaskBoard is a cross-plan, kanban-style view of every task queue
+type TaskBoard struct {
+	GeneratedAt time.Time     `json:"generated_at"`
+	Plans       []string      `json:"plans"`
+	TotalTasks  int           `json:"total_tasks"`
+	Columns     []BoardColumn `json:"columns"`
+}
+
+// boardStatuses defines the column order of the board
+var boardStatuses = []string{
+	TaskStatusPending,
+	TaskStatusInProgress,
+	TaskStatusDone,
+	TaskStatusFailed,
+	TaskStatusSkipped,
+}
+
+// LoadAllTaskQueues loads every task queue stored under .opusflow/
+func LoadAllTaskQueues() ([]*TaskQueue, error) {
+	root, err := manager.FindProjectRoot()
+	if err != nil {
+		return nil, fmt.Errorf("failed to find project root: %w", err)
+	}
+
+	return loadTaskQueuesFromDir(filepath.Join(root, ".opusflow"))
+}
+
+// loadTaskQueuesFromDir loads all tasks-*.json files in dir, sorted by plan reference
+func loadTaskQueuesFromDir(dir string) ([]*TaskQueue, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "tasks-*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list task queues: %w", err)
+	}
+
+	queues := make([]*TaskQueue, 0, len(paths))
+	for _, p := range paths {
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", filepath.Base(p), err)
+		}
+
+		var tq TaskQueue
+		if err := json.Unmarshal(data, &tq); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal %s: %w", filepath.Base(p), err)
+		}
+		queues = append(queues, &tq)
+	}
+
+	sort.Slice(queues, func(i, j int) bool {
+		return queues[i].PlanRef < queues[j].PlanRef
+	})
+
+	return queues, nil
+}
+
+// ValidateTaskStatus checks that status is one of the known task statuses
+func ValidateTaskStatus(status string) error {
+	for _, s := range boardStatuses {
+		if s == status {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown task status: %s (valid: %s)", status, strings.Join(boardStatuses, ", "))
+}
+
+// BuildTaskBoard groups the tasks of all queues by status, applying the filter
+func BuildTaskBoard(queues []*TaskQueue, filter TaskBoardFilter) *TaskBoard {
+	board := &TaskBoard{
+		GeneratedAt: time.Now(),
+		Plans:       []string{},
+		Columns:     make([]BoardColumn, len(boardStatuses)),
+	}
+
+	columnIdx := make(map[string]int, len(boardStatuses))
+	for i, s := range boardStatuses {
+		board.Columns[i] = BoardColumn{Status: s, Tasks: []BoardTask{}}
+		columnIdx[s] = i
+	}
+
+	for _, tq := range queues {
+		if !matchPlanGlob(tq.PlanRef, filter.Plan) {
+			continue
+		}
+
+		matched := false
+		for _, task := range tq.Tasks {
+			if !filter.matches(&task) {
+				continue
+			}
+
+			idx, ok := columnIdx[task.Status]
+			if !ok {
+				continue
+			}
+			board.Columns[idx].Tasks = append(board.Columns[idx].Tasks, BoardTask{PlanRef: tq.PlanRef, Task: task})
+			board.TotalTasks++
+			matched = true
+		}
+
+		if matched {
+			board.Plans = append(board.Plans, tq.PlanRef)
+		}
+	}
+
+	return board
+}
+
+// matches reports whether a task passes the status, file and agent filters
+func (f TaskBoardFilter) matches(task *Task) bool {
+	if f.Status != "" && task.Status != f.Status {
+		return false
+	}
+	if f.Agent != "" && !strings.EqualFold(task.Agent, f.Agent) {
+		return false
+	}
+	if f.File != "" && !taskReferencesFile(task, f.File) {
+		return false
+	}
+	return true
+}
+
+// matchPlanGlob matches a plan reference (with or without extension) against a glob
+func matchPlanGlob(planRef, pattern string) bool {
+	if pattern == "" {
+		return true
+	}
+	if m, _ := filepath.Match(pattern, planRef); m {
+		return true
+	}
+	m, _ := filepath.Match(pattern, strings.TrimSuffix(planRef, filepath.Ext(planRef)))
+	return m
+}
+
+// taskReferencesFile checks whether any task file equals, ends with or matches the given path
+func taskReferencesFile(task *Task, path string) bool {
+	path = filepath.ToSlash(filepath.Clean(path))
+	for _, f := range task.Files {
+		f = filepath.ToSlash(filepath.Clean(f))
+		if f == path || strings.HasSuffix(f, "/"+path) {
+			return true
+		}
+		if m, _ := filepath.Match(path, f); m {
+			return true
+		}
+	}
+	return false
+}
+
+// FormatJSON returns the board as indented JSON
+func (b *TaskBoard) FormatJSON() (string, error) {
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// FormatMarkdown returns the board as a kanban-style markdown view
+func (b *TaskBoard) FormatMarkdown() string {
+	var sb strings.Builder
+
+	sb.WriteString("# Task Board\n\n")
+	sb.WriteString(fmt.Sprintf("**Plans**: %d | **Tasks**: %d\n\n", len(b.Plans), b.TotalTasks))
+
+	for _, col := range b.Columns {
+		sb.WriteString(fmt.Sprintf("## %s %s (%d)\n\n", getStatusEmoji(col.Status), col.Status, len(col.Tasks)))
+
+		if len(col.Tasks) == 0 {
+			sb.WriteString("_No tasks_\n\n")
+			continue
+		}
+
+		for _, t := range col.Tasks {
+			sb.WriteString(fmt.Sprintf("- **%s** `%s`: %s", strings.TrimSuffix(t.PlanRef, filepath.Ext(t.PlanRef)), t.ID, t.Title))
+			if t.Agent != "" {
+				sb.WriteString(fmt.Sprintf(" _(agent: %s)_", t.Agent))
+			}
+			sb.WriteString("\n")
+			if len(t.Files) > 0 {
+				sb.WriteString(fmt.Sprintf("  - Files: %s\n", strings.Join(t.Files, ", ")))
+			}
+		}
+		sb.WriteString("\n")
+	}
+
+	return sb.String()
+}
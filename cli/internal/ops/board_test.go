@@ -0,0 +1,137 @@
+package ops
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func sampleBoardQueues() []*TaskQueue {
+	return []*TaskQueue{
+		{
+			PlanRef: "plan-01-auth.md",
+			Tasks: []Task{
+				{ID: "task-1", Title: "Add login", Status: TaskStatusDone, Files: []string{"cmd/login.go"}},
+				{ID: "task-2", Title: "Add tokens", Status: TaskStatusFailed, Files: []string{"internal/auth/token.go"}, Agent: "aider"},
+			},
+		},
+		{
+			PlanRef: "plan-02-cache.md",
+			Tasks: []Task{
+				{ID: "task-1", Title: "Add cache", Status: TaskStatusPending, Files: []string{"internal/cache/cache.go"}},
+				{ID: "task-2", Title: "Wire cache", Status: TaskStatusInProgress, Files: []string{"cmd/login.go"}, Agent: "claude-code"},
+			},
+		},
+	}
+}
+
+func TestBuildTaskBoard_GroupsByStatus(t *testing.T) {
+	board := BuildTaskBoard(sampleBoardQueues(), TaskBoardFilter{})
+
+	if board.TotalTasks != 4 {
+		t.Errorf("Expected 4 tasks, got %d", board.TotalTasks)
+	}
+	if len(board.Plans) != 2 {
+		t.Errorf("Expected 2 plans, got %v", board.Plans)
+	}
+	if len(board.Columns) != len(boardStatuses) {
+		t.Fatalf("Expected %d columns, got %d", len(boardStatuses), len(board.Columns))
+	}
+
+	counts := make(map[string]int)
+	for _, col := range board.Columns {
+		counts[col.Status] = len(col.Tasks)
+	}
+	if counts[TaskStatusPending] != 1 || counts[TaskStatusInProgress] != 1 ||
+		counts[TaskStatusDone] != 1 || counts[TaskStatusFailed] != 1 {
+		t.Errorf("Unexpected column counts: %v", counts)
+	}
+}
+
+func TestBuildTaskBoard_Filters(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter TaskBoardFilter
+		want   int
+	}{
+		{"status", TaskBoardFilter{Status: TaskStatusFailed}, 1},
+		{"file exact", TaskBoardFilter{File: "cmd/login.go"}, 2},
+		{"file suffix", TaskBoardFilter{File: "token.go"}, 1},
+		{"file glob", TaskBoardFilter{File: "internal/*/*.go"}, 2},
+		{"plan glob", TaskBoardFilter{Plan: "plan-02-*"}, 2},
+		{"agent", TaskBoardFilter{Agent: "aider"}, 1},
+		{"combined", TaskBoardFilter{File: "cmd/login.go", Plan: "plan-01*"}, 1},
+		{"no match", TaskBoardFilter{Agent: "gemini"}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			board := BuildTaskBoard(sampleBoardQueues(), tt.filter)
+			if board.TotalTasks != tt.want {
+				t.Errorf("Expected %d tasks, got %d", tt.want, board.TotalTasks)
+			}
+		})
+	}
+}
+
+func TestTaskBoard_Format(t *testing.T) {
+	board := BuildTaskBoard(sampleBoardQueues(), TaskBoardFilter{})
+
+	md := board.FormatMarkdown()
+	if !strings.Contains(md, "# Task Board") {
+		t.Error("Expected board heading")
+	}
+	if !strings.Contains(md, "plan-02-cache") || !strings.Contains(md, "Wire cache") {
+		t.Error("Expected plan and task titles in board")
+	}
+
+	out, err := board.FormatJSON()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var decoded TaskBoard
+	if err := json.Unmarshal([]byte(out), &decoded); err != nil {
+		t.Fatalf("Invalid JSON: %v", err)
+	}
+	if decoded.TotalTasks != 4 {
+		t.Errorf("Expected 4 tasks in JSON, got %d", decoded.TotalTasks)
+	}
+}
+
+func TestLoadTaskQueuesFromDir(t *testing.T) {
+	dir := t.TempDir()
+
+	for _, tq := range sampleBoardQueues() {
+		data, _ := json.Marshal(tq)
+		name := "tasks-" + strings.TrimSuffix(tq.PlanRef, ".md") + ".json"
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Unrelated files are ignored
+	if err := os.WriteFile(filepath.Join(dir, "workflow-state.json"), []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	queues, err := loadTaskQueuesFromDir(dir)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(queues) != 2 {
+		t.Fatalf("Expected 2 queues, got %d", len(queues))
+	}
+	if queues[0].PlanRef != "plan-01-auth.md" {
+		t.Errorf("Expected queues sorted by plan ref, got %s first", queues[0].PlanRef)
+	}
+}
+
+func TestValidateTaskStatus(t *testing.T) {
+	if err := ValidateTaskStatus(TaskStatusFailed); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if err := ValidateTaskStatus("blocked"); err == nil {
+		t.Error("Expected error for unknown status")
+	}
+}
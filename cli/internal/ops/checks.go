@@ -0,0 +1,470 @@
+package ops
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/tuanpep/oplusflow/internal/manager"
+	"github.com/tuanpep/oplusflow/internal/plan"
+)
+
+// Check statuses
+const (
+	CheckPassed  = "passed"
+	CheckFailed  = "failed"
+	CheckSkipped = "skipped"
+	CheckError   = "error"
+)
+
+// DefaultCheckTimeout bounds a check without a configured timeout
+const DefaultCheckTimeout = 30 * time.Minute
+
+// Check is one step of automated verification. Run adds the check's findings
+// to the result (comments, counted checks and any statuses it owns) and
+// returns one of the check statuses.
+type Check interface {
+	Name() string
+	Run(cc *CheckContext, vr *VerificationResult) (string, error)
+}
+
+// CheckContext is the verification state shared by the checks of one run
+type CheckContext struct {
+	Context  context.Context // cancelled when the check's timeout elapses
+	Root     string
+	PlanPath string
+	Config   *ProjectConfig
+	Options  VerifyOptions
+
+	coverage *coverageData // collected by the commands check
+}
+
+// CheckRecord is the outcome of a check in a verification run
+type CheckRecord struct {
+	Name     string        `json:"name"`
+	Status   string        `json:"status"`
+	Duration time.Duration `json:"duration"`
+	Error    string        `json:"error,omitempty"`
+}
+
+// ChecksConfig selects and orders the checks verify runs:
+//
+//	checks:
+//	  enabled: [diff, commands, files, no-todos]  # run exactly these, in order
+//	  disabled: [coverage]                        # or drop some from the default
+//	  timeout: 20m                                # per check, defaults to 30m
+//	  dir: .opusflow/checks                       # script checks
+type ChecksConfig struct {
+	Enabled  []string `yaml:"enabled,omitempty"`
+	Disabled []string `yaml:"disabled,omitempty"`
+	Timeout  string   `yaml:"timeout,omitempty"`
+	Dir      string   `yaml:"dir,omitempty"`
+}
+
+// checkRegistry holds the built-in checks in their default order
+var checkRegistry = []Check{diffCheck{}, commandsCheck{}, coverageCheck{}, filesCheck{}}
+
+// RegisterCheck adds a check to the registry, after the built-in checks
+func RegisterCheck(c Check) error {
+	for _, existing := range checkRegistry {
+		if existing.Name() == c.Name() {
+			return fmt.Errorf("check %q is already registered", c.Name())
+		}
+	}
+	checkRegistry = append(checkRegistry, c)
+	return nil
+}
+
+// ResolveChecks returns the checks to run: the registered checks followed by
+// script checks, filtered and ordered by the checks section of the config.
+// The coverage check must come after the commands check.
+func ResolveChecks(rootDir string, cfg *ProjectConfig) ([]Check, error) {
+	all := slices.Clone(checkRegistry)
+	scripts, err := loadScriptChecks(rootDir, cfg.Checks.Dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, sc := range scripts {
+		if slices.ContainsFunc(all, func(c Check) bool { return c.Name() == sc.Name() }) {
+			return nil, fmt.Errorf("script check %s: name %q is already used by another check", sc.path, sc.name)
+		}
+		all = append(all, sc)
+	}
+
+	byName := make(map[string]Check, len(all))
+	names := make([]string, 0, len(all))
+	for _, c := range all {
+		byName[c.Name()] = c
+		names = append(names, c.Name())
+	}
+	unknown := func(name string) error {
+		return fmt.Errorf("unknown check %q (available: %s)", name, strings.Join(names, ", "))
+	}
+
+	selected := all
+	if len(cfg.Checks.Enabled) > 0 {
+		selected = nil
+		for _, name := range cfg.Checks.Enabled {
+			c, ok := byName[name]
+			if !ok {
+				return nil, unknown(name)
+			}
+			selected = append(selected, c)
+		}
+	}
+
+	for _, name := range cfg.Checks.Disabled {
+		if _, ok := byName[name]; !ok {
+			return nil, unknown(name)
+		}
+	}
+	var checks []Check
+	for _, c := range selected {
+		if !slices.Contains(cfg.Checks.Disabled, c.Name()) {
+			checks = append(checks, c)
+		}
+	}
+
+	// The coverage check reads the coverage the commands check collects
+	cov := slices.IndexFunc(checks, func(c Check) bool { return c.Name() == "coverage" })
+	cmds := slices.IndexFunc(checks, func(c Check) bool { return c.Name() == "commands" })
+	if cov >= 0 && cov < cmds {
+		return nil, fmt.Errorf("check \"coverage\" must run after \"commands\", which collects the coverage")
+	}
+	if cov >= 0 && cmds < 0 {
+		return nil, fmt.Errorf("check \"coverage\" needs the \"commands\" check, which collects the coverage; disable coverage too")
+	}
+	return checks, nil
+}
+
+// CheckSource describes where a check comes from: "built-in" or a script path
+func CheckSource(c Check) string {
+	if sc, ok := c.(scriptCheck); ok {
+		return sc.path
+	}
+	return "built-in"
+}
+
+// checkTimeout returns the configured per-check timeout
+func (cfg *ProjectConfig) checkTimeout() time.Duration {
+	if d, err := time.ParseDuration(cfg.Checks.Timeout); err == nil && d > 0 {
+		return d
+	}
+	return DefaultCheckTimeout
+}
+
+// runCheck runs a check within the timeout and records its outcome. A check
+// that errors or times out gets a critical comment.
+func (vr *VerificationResult) runCheck(cc *CheckContext, check Check, timeout time.Duration) {
+	ctx, cancel := context.WithTimeoutCause(context.Background(), timeout, fmt.Errorf("check timed out after %s", timeout))
+	defer cancel()
+	cc.Context = ctx
+
+	started := time.Now()
+	status, err := check.Run(cc, vr)
+
+	record := CheckRecord{Name: check.Name(), Status: status, Duration: time.Since(started)}
+	if ctx.Err() != nil {
+		err = context.Cause(ctx)
+	}
+	if err != nil {
+		record.Status, record.Error = CheckError, err.Error()
+		vr.Comments = append(vr.Comments, VerifyComment{
+			Number:      len(vr.Comments) + 1,
+			Severity:    SeverityCritical,
+			Title:       fmt.Sprintf("Check %s could not complete", check.Name()),
+			Description: err.Error(),
+		})
+	}
+	vr.Checks = append(vr.Checks, record)
+}
+
+// diffCheck summarizes the working tree changes
+type diffCheck struct{}
+
+func (diffCheck) Name() string { return "diff" }
+
+func (diffCheck) Run(cc *CheckContext, vr *VerificationResult) (string, error) {
+	diffOutput, err := captureFullGitDiff(cc.Root)
+	if err != nil || diffOutput == "" {
+		return CheckSkipped, nil
+	}
+	vr.DiffSummary = summarizeDiff(diffOutput)
+	return CheckPassed, nil
+}
+
+// commandsCheck runs the build, typecheck, lint and test commands of every
+// subproject touched by the change (all of them when nothing has changed)
+type commandsCheck struct{}
+
+func (commandsCheck) Name() string { return "commands" }
+
+func (commandsCheck) Run(cc *CheckContext, vr *VerificationResult) (string, error) {
+	subs, err := DiscoverSubprojects(cc.Root)
+	if err != nil {
+		return "", err
+	}
+	changed, changedErr := changedFiles(cc.Root)
+	groups := groupChangedFiles(subs, changed)
+	runAll := changedErr != nil || len(changed) == 0
+	coverage := cc.Options.Coverage || cc.Config.Coverage.Enabled
+
+	comments := len(vr.Comments)
+	statuses := make(map[string][]string)
+subprojects:
+	for _, sub := range subs {
+		sr := SubprojectResult{Path: sub.Path, Name: sub.Name, ChangedFiles: len(groups[sub.Path])}
+		if !runAll && sr.ChangedFiles == 0 {
+			sr.Skipped = true
+			vr.Subprojects = append(vr.Subprojects, sr)
+			continue
+		}
+
+		sr.Statuses = make(map[string]string)
+		prefix := ""
+		if len(subs) > 1 {
+			prefix = sub.Name + ": "
+		}
+		for _, pc := range sub.Commands {
+			status, subCov := vr.runCommand(cc.Context, cc.Root, sub, pc, prefix, coverage)
+			if subCov != nil {
+				if cc.coverage == nil {
+					cc.coverage = newCoverageData()
+				}
+				cc.coverage.merge(subCov)
+			}
+			sr.Statuses[pc.Kind] = status
+			statuses[pc.Kind] = append(statuses[pc.Kind], status)
+
+			// Once the check times out every remaining command would fail too
+			if cc.Context.Err() != nil {
+				vr.Subprojects = append(vr.Subprojects, sr)
+				break subprojects
+			}
+		}
+		vr.Subprojects = append(vr.Subprojects, sr)
+	}
+
+	// A lone root project needs no per-subproject section
+	if len(subs) == 1 && subs[0].Path == "" {
+		vr.Subprojects = nil
+	}
+	for _, kind := range CommandKinds {
+		vr.setCommandStatus(kind, combineStatuses(statuses[kind]))
+	}
+	idle := statusNoCommand
+	if !runAll && len(groups) == 0 {
+		idle = "⏭️ Skipped (no subproject affected by the change)"
+	}
+	if vr.BuildStatus == "" {
+		vr.BuildStatus = idle
+	}
+	if vr.TestStatus == "" {
+		vr.TestStatus = idle
+	}
+
+	switch {
+	case cc.Context.Err() != nil:
+		return "", context.Cause(cc.Context)
+	case len(statuses) == 0:
+		return CheckSkipped, nil
+	case len(vr.Comments) > comments:
+		return CheckFailed, nil
+	}
+	return CheckPassed, nil
+}
+
+// coverageCheck compares the coverage collected by the commands check,
+// overall and of the changed lines, with the thresholds
+type coverageCheck struct{}
+
+func (coverageCheck) Name() string { return "coverage" }
+
+func (coverageCheck) Run(cc *CheckContext, vr *VerificationResult) (string, error) {
+	if cc.coverage == nil {
+		return CheckSkipped, nil
+	}
+	lines, _ := changedLines(cc.Root)
+	vr.Coverage = evaluateCoverage(cc.coverage, lines, cc.Config.Coverage)
+
+	comments := len(vr.Comments)
+	vr.checkCoverage()
+	if len(vr.Comments) > comments {
+		return CheckFailed, nil
+	}
+	return CheckPassed, nil
+}
+
+// filesCheck checks that the files the plan creates or modifies exist
+type filesCheck struct{}
+
+func (filesCheck) Name() string { return "files" }
+
+func (filesCheck) Run(cc *CheckContext, vr *VerificationResult) (string, error) {
+	planContent, err := ReadFile(cc.PlanPath)
+	if err != nil {
+		return CheckSkipped, nil
+	}
+
+	status := CheckPassed
+	p, _ := plan.Parse(planContent)
+	deleted := p.DeletedFiles()
+	for _, f := range p.ReferencedFiles() {
+		// Files the plan deletes are not expected to exist
+		if slices.Contains(deleted, f) {
+			continue
+		}
+		exists, _ := fileExists(filepath.Join(cc.Root, f))
+		if !exists {
+			status = CheckFailed
+			vr.Comments = append(vr.Comments, VerifyComment{
+				Number:      len(vr.Comments) + 1,
+				Severity:    SeverityMajor,
+				Title:       fmt.Sprintf("Missing file: %s", f),
+				Description: "A file specified in the plan was not created.",
+				Files:       []string{f},
+			})
+		} else {
+			vr.PassedChecks++
+		}
+		vr.TotalChecks++
+	}
+	return status, nil
+}
+
+// scriptCheck runs an executable from the checks directory. The script gets
+// the verification context as JSON on stdin (and OPUSFLOW_* variables) and
+// prints its findings as JSON:
+//
+//	{"status": "failed", "comments": [{"severity": "major", "title": "...",
+//	  "description": "...", "files": ["cmd/x.go"]}]}
+//
+// Status defaults to failed when there are comments and passed otherwise.
+type scriptCheck struct {
+	name string
+	path string // relative to the project root
+}
+
+func (sc scriptCheck) Name() string { return sc.name }
+
+// scriptCheckInput is the JSON a script check receives on stdin
+type scriptCheckInput struct {
+	Root         string   `json:"root"`
+	Plan         string   `json:"plan"`
+	ChangedFiles []string `json:"changed_files"`
+}
+
+// scriptCheckOutput is the JSON a script check prints on stdout
+type scriptCheckOutput struct {
+	Status   string `json:"status"`
+	Comments []struct {
+		Severity    string   `json:"severity"`
+		Title       string   `json:"title"`
+		Description string   `json:"description"`
+		Files       []string `json:"files"`
+	} `json:"comments"`
+}
+
+func (sc scriptCheck) Run(cc *CheckContext, vr *VerificationResult) (string, error) {
+	changed, _ := changedFiles(cc.Root)
+	input, _ := json.Marshal(scriptCheckInput{Root: cc.Root, Plan: cc.PlanPath, ChangedFiles: changed})
+
+	path := filepath.Join(cc.Root, sc.path)
+	cmd := scriptCommand(cc.Context, path)
+	cmd.Dir = cc.Root
+	cmd.Env = append(os.Environ(), "OPUSFLOW_ROOT="+cc.Root, "OPUSFLOW_PLAN="+cc.PlanPath)
+	cmd.Stdin = bytes.NewReader(input)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	killProcessGroupOnCancel(cmd)
+	cmd.WaitDelay = 5 * time.Second
+
+	runErr := cmd.Run()
+	if cc.Context.Err() != nil {
+		return "", cc.Context.Err()
+	}
+
+	var out scriptCheckOutput
+	if len(bytes.TrimSpace(stdout.Bytes())) > 0 {
+		if err := json.Unmarshal(stdout.Bytes(), &out); err != nil {
+			return "", fmt.Errorf("invalid JSON output from %s: %w\n%s%s", sc.path, err, stdout.String(), stderr.String())
+		}
+	} else if runErr != nil {
+		return "", fmt.Errorf("%s failed: %w\n%s", sc.path, runErr, stderr.String())
+	}
+
+	for _, c := range out.Comments {
+		severity := c.Severity
+		if !slices.Contains([]string{SeverityCritical, SeverityMajor, SeverityMinor, SeverityOutdated}, severity) {
+			severity = SeverityMajor
+		}
+		title := c.Title
+		if title == "" {
+			title = sc.name
+		}
+		vr.Comments = append(vr.Comments, VerifyComment{
+			Number:      len(vr.Comments) + 1,
+			Severity:    severity,
+			Title:       title,
+			Description: c.Description,
+			Files:       c.Files,
+		})
+	}
+
+	status := out.Status
+	if status == "" {
+		status = CheckPassed
+		if len(out.Comments) > 0 || runErr != nil {
+			status = CheckFailed
+		}
+	}
+	if !slices.Contains([]string{CheckPassed, CheckFailed, CheckSkipped}, status) {
+		return "", fmt.Errorf("%s reported unknown status %q", sc.path, status)
+	}
+	if status != CheckSkipped {
+		vr.TotalChecks++
+		if status == CheckPassed {
+			vr.PassedChecks++
+		}
+	}
+	return status, nil
+}
+
+// loadScriptChecks finds script checks in dir (default .opusflow/checks):
+// executable files and .sh scripts (see isScriptCheck), named after the file
+// without extension
+func loadScriptChecks(rootDir, dir string) ([]scriptCheck, error) {
+	checksDir := manager.ChecksDir(rootDir)
+	if dir != "" {
+		checksDir = filepath.Join(rootDir, dir)
+	}
+	entries, err := os.ReadDir(checksDir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read checks directory: %w", err)
+	}
+
+	var checks []scriptCheck
+	for _, e := range entries {
+		info, err := e.Info()
+		if err != nil || info.IsDir() || strings.HasPrefix(e.Name(), ".") {
+			continue
+		}
+		if !isScriptCheck(e.Name(), info.Mode()) {
+			continue
+		}
+		name := strings.TrimSuffix(e.Name(), filepath.Ext(e.Name()))
+		checks = append(checks, scriptCheck{name: name, path: relToRoot(rootDir, filepath.Join(checksDir, e.Name()))})
+	}
+	sort.Slice(checks, func(i, j int) bool { return checks[i].name < checks[j].name })
+	return checks, nil
+}
@@ -0,0 +1,247 @@
+package ops
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestResolveChecks(t *testing.T) {
+	root := t.TempDir()
+	writeTestFiles(t, root, map[string]string{
+		".opusflow/checks/no-todos.sh": "exit 0\n",
+		".opusflow/checks/README.md":   "not a check\n",
+		"ci/checks/files.sh":           "exit 0\n",
+	})
+
+	tests := []struct {
+		name    string
+		checks  ChecksConfig
+		want    string
+		wantErr string
+	}{
+		{"default", ChecksConfig{}, "diff commands coverage files no-todos", ""},
+		{"enabled order", ChecksConfig{Enabled: []string{"no-todos", "files", "diff"}}, "no-todos files diff", ""},
+		{"disabled", ChecksConfig{Disabled: []string{"coverage", "diff"}}, "commands files no-todos", ""},
+		{"coverage before commands", ChecksConfig{Enabled: []string{"coverage", "commands"}}, "", `"coverage" must run after "commands"`},
+		{"coverage without commands", ChecksConfig{Disabled: []string{"commands"}}, "", `"coverage" needs the "commands" check`},
+		{"commands disabled with coverage", ChecksConfig{Disabled: []string{"commands", "coverage"}}, "diff files no-todos", ""},
+		{"enabled and disabled", ChecksConfig{Enabled: []string{"files", "diff"}, Disabled: []string{"diff"}}, "files", ""},
+		{"unknown enabled", ChecksConfig{Enabled: []string{"lint"}}, "", `unknown check "lint" (available: diff, commands, coverage, files, no-todos)`},
+		{"unknown disabled", ChecksConfig{Disabled: []string{"docs"}}, "", `unknown check "docs"`},
+		{"name collision", ChecksConfig{Dir: "ci/checks"}, "", `name "files" is already used`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checks, err := ResolveChecks(root, &ProjectConfig{Checks: tt.checks})
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ResolveChecks() error = %v; want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ResolveChecks() failed: %v", err)
+			}
+			var names []string
+			for _, c := range checks {
+				names = append(names, c.Name())
+			}
+			if strings.Join(names, " ") != tt.want {
+				t.Errorf("ResolveChecks() = %v; want %s", names, tt.want)
+			}
+		})
+	}
+
+	checks, _ := ResolveChecks(root, &ProjectConfig{Checks: ChecksConfig{Enabled: []string{"diff", "no-todos"}}})
+	if CheckSource(checks[0]) != "built-in" || CheckSource(checks[1]) != ".opusflow/checks/no-todos.sh" {
+		t.Errorf("Unexpected check sources: %s, %s", CheckSource(checks[0]), CheckSource(checks[1]))
+	}
+}
+
+type stubCheck struct{ name string }
+
+func (s stubCheck) Name() string { return s.name }
+
+func (s stubCheck) Run(cc *CheckContext, vr *VerificationResult) (string, error) {
+	return CheckPassed, nil
+}
+
+func TestRegisterCheck(t *testing.T) {
+	saved := checkRegistry
+	defer func() { checkRegistry = saved }()
+
+	if err := RegisterCheck(stubCheck{"files"}); err == nil {
+		t.Error("Expected registering a duplicate name to fail")
+	}
+	if err := RegisterCheck(stubCheck{"licenses"}); err != nil {
+		t.Fatalf("RegisterCheck failed: %v", err)
+	}
+
+	checks, err := ResolveChecks(t.TempDir(), &ProjectConfig{})
+	if err != nil {
+		t.Fatalf("ResolveChecks failed: %v", err)
+	}
+	if last := checks[len(checks)-1]; last.Name() != "licenses" || CheckSource(last) != "built-in" {
+		t.Errorf("Expected the registered check last, got %s", last.Name())
+	}
+}
+
+func TestScriptCheck(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+
+	tests := []struct {
+		name       string
+		script     string
+		timeout    time.Duration
+		wantStatus string
+		wantTitles []string
+		wantErr    string
+	}{
+		{"passed", "echo '{\"status\": \"passed\"}'\n", 0, CheckPassed, nil, ""},
+		{"no output", "exit 0\n", 0, CheckPassed, nil, ""},
+		{"comments", `echo '{"comments": [{"severity": "minor", "title": "TODO left", "files": ["a.go"]}, {"severity": "bogus", "description": "x"}]}'` + "\n",
+			0, CheckFailed, []string{"TODO left", "lint"}, ""},
+		{"skipped", "echo '{\"status\": \"skipped\"}'\n", 0, CheckSkipped, nil, ""},
+		{"failing exit", "echo oops >&2\nexit 3\n", 0, CheckError, []string{"Check lint could not complete"}, "lint.sh failed"},
+		{"invalid output", "echo not json\n", 0, CheckError, []string{"Check lint could not complete"}, "invalid JSON output"},
+		{"unknown status", "echo '{\"status\": \"great\"}'\n", 0, CheckError, []string{"Check lint could not complete"}, `unknown status "great"`},
+		{"timeout", "sleep 5\n", 200 * time.Millisecond, CheckError, []string{"Check lint could not complete"}, "timed out after 200ms"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			writeTestFiles(t, root, map[string]string{".opusflow/checks/lint.sh": tt.script})
+			checks, err := loadScriptChecks(root, "")
+			if err != nil || len(checks) != 1 {
+				t.Fatalf("loadScriptChecks() = %v, %v", checks, err)
+			}
+
+			timeout := tt.timeout
+			if timeout == 0 {
+				timeout = DefaultCheckTimeout
+			}
+			vr := &VerificationResult{}
+			cc := &CheckContext{Root: root, PlanPath: filepath.Join(root, "plan.md"), Config: &ProjectConfig{}}
+			started := time.Now()
+			vr.runCheck(cc, checks[0], timeout)
+			if time.Since(started) > 3*time.Second {
+				t.Errorf("Check took %s; the timeout did not stop it", time.Since(started))
+			}
+
+			record := vr.Checks[0]
+			if record.Name != "lint" || record.Status != tt.wantStatus {
+				t.Errorf("Check record = %+v; want status %s", record, tt.wantStatus)
+			}
+			if tt.wantErr != "" && !strings.Contains(record.Error, tt.wantErr) {
+				t.Errorf("Check error = %q; want %q", record.Error, tt.wantErr)
+			}
+			var titles []string
+			for _, c := range vr.Comments {
+				titles = append(titles, c.Title)
+			}
+			if strings.Join(titles, "|") != strings.Join(tt.wantTitles, "|") {
+				t.Errorf("Comments = %v; want %v", titles, tt.wantTitles)
+			}
+			if tt.name == "comments" && (vr.Comments[0].Severity != SeverityMinor || vr.Comments[1].Severity != SeverityMajor) {
+				t.Errorf("Unexpected severities: %s, %s", vr.Comments[0].Severity, vr.Comments[1].Severity)
+			}
+		})
+	}
+}
+
+func TestCommandsCheck_Timeout(t *testing.T) {
+	root := t.TempDir()
+	writeTestFiles(t, root, map[string]string{
+		".opusflow/config.yaml": "subprojects:\n" +
+			"  - path: api\n    commands:\n      build: sleep 5\n      test: sleep 5\n" +
+			"  - path: web\n    commands:\n      build: sleep 5\n",
+		"api/.keep": "",
+		"web/.keep": "",
+	})
+	cfg, err := LoadProjectConfig(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	vr := &VerificationResult{}
+	cc := &CheckContext{Root: root, Config: cfg}
+	started := time.Now()
+	vr.runCheck(cc, commandsCheck{}, 300*time.Millisecond)
+	if time.Since(started) > 3*time.Second {
+		t.Errorf("Check took %s; the timeout did not stop the remaining commands", time.Since(started))
+	}
+
+	// Only the check reports the timeout, naming its own deadline
+	if len(vr.Comments) != 1 || vr.Comments[0].Title != "Check commands could not complete" {
+		t.Fatalf("Expected a single check comment, got %+v", vr.Comments)
+	}
+	if record := vr.Checks[0]; record.Status != CheckError || record.Error != "check timed out after 300ms" {
+		t.Errorf("Unexpected check record: %+v", record)
+	}
+	if len(vr.Subprojects) != 1 || vr.Subprojects[0].Statuses[CommandBuild] != statusInterrupted {
+		t.Errorf("Expected the loop to stop in the first subproject, got %+v", vr.Subprojects)
+	}
+}
+
+func TestAutoVerifyPlan_Checks(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	root := t.TempDir()
+	writeTestFiles(t, root, map[string]string{
+		".agent/.keep": "",
+		"plan.md":      "# Plan\n",
+		"main.go":      "package main\n",
+		".opusflow/config.yaml": "commands:\n  build: \"false\"\n" +
+			"checks:\n  enabled: [no-todos, files]\n",
+		".opusflow/checks/no-todos.sh": `input=$(cat)
+case "$input" in
+  *main.go*) echo '{"comments": [{"severity": "minor", "title": "TODO in main.go", "files": ["main.go"]}]}' ;;
+  *) echo '{"status": "passed"}' ;;
+esac
+`,
+	})
+
+	oldWd, _ := os.Getwd()
+	os.Chdir(root)
+	defer os.Chdir(oldWd)
+
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"add", "-A"},
+		{"-c", "user.name=t", "-c", "user.email=t@example.com", "commit", "-qm", "init"},
+	} {
+		if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+
+	writeTestFiles(t, root, map[string]string{"main.go": "package main\n\n// TODO\n"})
+	result, err := AutoVerifyPlan(filepath.Join(root, "plan.md"), VerifyOptions{})
+	if err != nil {
+		t.Fatalf("AutoVerifyPlan failed: %v", err)
+	}
+
+	// Only the enabled checks run, so the failing build is never attempted
+	if len(result.Checks) != 2 || result.Checks[0].Name != "no-todos" || result.Checks[0].Status != CheckFailed || result.Checks[1].Name != "files" {
+		t.Fatalf("Unexpected checks: %+v", result.Checks)
+	}
+	if len(result.Comments) != 1 || result.Comments[0].Title != "TODO in main.go" {
+		t.Errorf("Expected the script's comment only, got %+v", result.Comments)
+	}
+	if result.BuildStatus != "" {
+		t.Errorf("Expected no build to run, got %q", result.BuildStatus)
+	}
+	if md := result.FormatMarkdown(); !strings.Contains(md, "## Checks") || !strings.Contains(md, "no-todos") {
+		t.Errorf("Expected a checks section, got:\n%s", md)
+	}
+}
@@ -0,0 +1,316 @@
+package ops
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/tuanpep/oplusflow/internal/manager"
+	"gopkg.in/yaml.v3"
+)
+
+// Project command kinds, in the order verify runs them
+const (
+	CommandBuild     = "build"
+	CommandTypecheck = "typecheck"
+	CommandLint      = "lint"
+	CommandTest      = "test"
+)
+
+// CommandKinds lists the project command kinds in run order
+var CommandKinds = []string{CommandBuild, CommandTypecheck, CommandLint, CommandTest}
+
+// DefaultCommandTimeout bounds a project command without a configured timeout
+const DefaultCommandTimeout = 10 * time.Minute
+
+// Project command sources
+const (
+	CommandSourceConfig   = "config"
+	CommandSourceDetected = "detected"
+)
+
+// ProjectConfig is the project configuration stored in .opusflow/config.yaml:
+//
+//	commands:
+//	  build: go build ./...        # shorthand for {run: ...}
+//	  test:
+//	    run: npm test
+//	    workdir: web               # relative to the project root
+//	    timeout: 5m                # defaults to 10m
+//	    reporter: jest             # go, jest, vitest, pytest or junit
+//	    report: reports/jest.json  # file the reporter writes, relative to workdir
+//	    coverage_report: coverage/lcov.info  # LCOV, Cobertura XML or Go profile
+//	  lint:
+//	    workdir: web               # no run: detect from web's manifests
+//	  typecheck:
+//	    skip: true                 # never run this kind of command
+//	coverage:
+//	  enabled: true                # collect on every verify (or pass --coverage)
+//	  min_total: 70                # percent, minor comment when short
+//	  min_changed: 80              # percent of changed lines, major comment
+//	checks:                        # see ChecksConfig
+//	  disabled: [coverage]
+//	subprojects:                   # optional, replaces discovery
+//	  - path: cli
+//	    commands:                  # workdirs are relative to the subproject
+//	      test: go test -short ./...
+//	  - path: vscode-extension
+//	    name: extension
+type ProjectConfig struct {
+	Commands    map[string]CommandConfig `yaml:"commands,omitempty"`
+	Coverage    CoverageConfig           `yaml:"coverage,omitempty"`
+	Checks      ChecksConfig             `yaml:"checks,omitempty"`
+	Subprojects []SubprojectConfig       `yaml:"subprojects,omitempty"`
+}
+
+// SubprojectConfig declares a subproject verified on its own
+type SubprojectConfig struct {
+	Path     string                   `yaml:"path"`
+	Name     string                   `yaml:"name,omitempty"`
+	Commands map[string]CommandConfig `yaml:"commands,omitempty"`
+}
+
+// CommandConfig declares how to run one kind of project command
+type CommandConfig struct {
+	Run      string `yaml:"run,omitempty"`
+	Workdir  string `yaml:"workdir,omitempty"`
+	Timeout  string `yaml:"timeout,omitempty"`
+	Skip     bool   `yaml:"skip,omitempty"`
+	Reporter string `yaml:"reporter,omitempty"`
+	Report   string `yaml:"report,omitempty"`
+	// CoverageReport is the coverage file the test command writes
+	CoverageReport string `yaml:"coverage_report,omitempty"`
+}
+
+// UnmarshalYAML accepts either a command string or a full mapping
+func (c *CommandConfig) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		c.Run = value.Value
+		return nil
+	}
+	// Decoding through a custom unmarshaler loses KnownFields, so check here
+	if value.Kind == yaml.MappingNode {
+		for i := 0; i < len(value.Content); i += 2 {
+			key := value.Content[i]
+			if !slices.Contains([]string{"run", "workdir", "timeout", "skip", "reporter", "report", "coverage_report"}, key.Value) {
+				return fmt.Errorf("line %d: field %s not found in command", key.Line, key.Value)
+			}
+		}
+	}
+	type plain CommandConfig
+	return value.Decode((*plain)(c))
+}
+
+// ProjectCommand is a resolved project command ready to run
+type ProjectCommand struct {
+	Kind    string        `json:"kind"`
+	Run     string        `json:"run"`
+	Workdir string        `json:"workdir,omitempty"` // relative to the project root
+	Timeout time.Duration `json:"timeout"`
+	Source  string        `json:"source"`
+	// Reporter and Report describe machine-readable test results, see TestReporters
+	Reporter string `json:"reporter,omitempty"`
+	Report   string `json:"report,omitempty"` // relative to Workdir
+	// CoverageReport is the coverage file the command writes, relative to Workdir
+	CoverageReport string `json:"coverage_report,omitempty"`
+}
+
+// CommandLine returns the command as it would be typed from the project root
+func (pc ProjectCommand) CommandLine() string {
+	if pc.Workdir == "" {
+		return pc.Run
+	}
+	return fmt.Sprintf("cd %s && %s", pc.Workdir, pc.Run)
+}
+
+// LoadProjectConfig reads .opusflow/config.yaml. A missing file yields an
+// empty configuration.
+func LoadProjectConfig(rootDir string) (*ProjectConfig, error) {
+	data, err := os.ReadFile(manager.ConfigPath(rootDir))
+	if errors.Is(err, os.ErrNotExist) {
+		return &ProjectConfig{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read project config: %w", err)
+	}
+
+	return ParseProjectConfig(data)
+}
+
+// ParseProjectConfig decodes and validates a project configuration
+func ParseProjectConfig(data []byte) (*ProjectConfig, error) {
+	var cfg ProjectConfig
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&cfg); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to parse project config: %w", err)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// Validate checks command kinds, working directories, timeouts and subprojects
+func (cfg *ProjectConfig) Validate() error {
+	problems := validateCommands("commands", cfg.Commands)
+
+	for _, t := range []struct {
+		name string
+		pct  float64
+	}{{"coverage.min_total", cfg.Coverage.MinTotal}, {"coverage.min_changed", cfg.Coverage.MinChanged}} {
+		if t.pct < 0 || t.pct > 100 {
+			problems = append(problems, fmt.Sprintf("%s: %v is not a percentage between 0 and 100", t.name, t.pct))
+		}
+	}
+
+	if cfg.Checks.Timeout != "" {
+		if d, err := time.ParseDuration(cfg.Checks.Timeout); err != nil || d <= 0 {
+			problems = append(problems, fmt.Sprintf("checks.timeout: invalid timeout %q (use a duration such as 90s or 5m)", cfg.Checks.Timeout))
+		}
+	}
+	if cfg.Checks.Dir != "" && !filepath.IsLocal(cfg.Checks.Dir) {
+		problems = append(problems, fmt.Sprintf("checks.dir: %q must be relative to the project root", cfg.Checks.Dir))
+	}
+
+	paths := make(map[string]bool, len(cfg.Subprojects))
+	for i, sp := range cfg.Subprojects {
+		label := fmt.Sprintf("subprojects[%d]", i)
+		switch {
+		case sp.Path == "":
+			problems = append(problems, label+": missing path")
+		case !filepath.IsLocal(sp.Path) && filepath.Clean(sp.Path) != ".":
+			problems = append(problems, fmt.Sprintf("%s: path %q must be relative to the project root", label, sp.Path))
+		case paths[filepath.Clean(sp.Path)]:
+			problems = append(problems, fmt.Sprintf("%s: duplicate path %q", label, sp.Path))
+		}
+		paths[filepath.Clean(sp.Path)] = true
+		problems = append(problems, validateCommands(label+".commands", sp.Commands)...)
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid project config:\n  - %s", strings.Join(problems, "\n  - "))
+	}
+	return nil
+}
+
+// validateCommands checks a commands section, labelling problems with prefix
+func validateCommands(prefix string, commands map[string]CommandConfig) []string {
+	var problems []string
+
+	kinds := make([]string, 0, len(commands))
+	for kind := range commands {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+
+	for _, kind := range kinds {
+		c := commands[kind]
+		label := prefix + "." + kind
+		if !slices.Contains(CommandKinds, kind) {
+			problems = append(problems, fmt.Sprintf("%s: unknown command kind (expected one of %s)", label, strings.Join(CommandKinds, ", ")))
+			continue
+		}
+		if c.Workdir != "" && !filepath.IsLocal(c.Workdir) {
+			problems = append(problems, fmt.Sprintf("%s: workdir %q must be relative to the project root", label, c.Workdir))
+		}
+		if c.Timeout != "" {
+			if d, err := time.ParseDuration(c.Timeout); err != nil || d <= 0 {
+				problems = append(problems, fmt.Sprintf("%s: invalid timeout %q (use a duration such as 90s or 5m)", label, c.Timeout))
+			}
+		}
+		if c.Reporter != "" && !slices.Contains(TestReporters, c.Reporter) {
+			problems = append(problems, fmt.Sprintf("%s: unknown reporter %q (expected one of %s)", label, c.Reporter, strings.Join(TestReporters, ", ")))
+		}
+		if (c.Reporter != "" || c.Report != "" || c.CoverageReport != "") && kind != CommandTest {
+			problems = append(problems, fmt.Sprintf("%s: reporter, report and coverage_report only apply to test commands", label))
+		}
+		if c.Reporter == ReporterJUnit && c.Report == "" {
+			problems = append(problems, fmt.Sprintf("%s: the junit reporter needs a report file", label))
+		}
+		for _, path := range []string{c.Report, c.CoverageReport} {
+			if path != "" && !filepath.IsLocal(path) {
+				problems = append(problems, fmt.Sprintf("%s: report %q must be relative to the workdir", label, path))
+			}
+		}
+	}
+	return problems
+}
+
+// ResolveProjectCommands combines the configured commands with commands
+// detected from manifest files. Configured commands win; a configured workdir
+// without a run command detects from that directory's manifests instead.
+// Only kinds with a command are returned, in CommandKinds order.
+func ResolveProjectCommands(rootDir string) ([]ProjectCommand, error) {
+	cfg, err := LoadProjectConfig(rootDir)
+	if err != nil {
+		return nil, err
+	}
+	return resolveCommands(rootDir, "", "commands", cfg.Commands)
+}
+
+// resolveCommands resolves the commands of the project in base (relative to
+// rootDir); configured workdirs are relative to base
+func resolveCommands(rootDir, base, label string, commands map[string]CommandConfig) ([]ProjectCommand, error) {
+	detected := make(map[string]ProjectCommands)
+	var cmds []ProjectCommand
+	for _, kind := range CommandKinds {
+		c := commands[kind]
+		if c.Skip {
+			continue
+		}
+
+		workdir := filepath.ToSlash(filepath.Join(base, c.Workdir))
+		if workdir == "." {
+			workdir = ""
+		}
+		if c.Workdir != "" {
+			info, err := os.Stat(filepath.Join(rootDir, workdir))
+			if err != nil || !info.IsDir() {
+				return nil, fmt.Errorf("%s.%s: workdir %s is not a directory", label, kind, workdir)
+			}
+		}
+
+		pc := ProjectCommand{Kind: kind, Workdir: workdir, Timeout: DefaultCommandTimeout, Run: c.Run, Source: CommandSourceConfig}
+		if c.Timeout != "" {
+			pc.Timeout, _ = time.ParseDuration(c.Timeout)
+		}
+		if pc.Run == "" {
+			if _, ok := detected[workdir]; !ok {
+				detected[workdir] = DetectProjectCommands(filepath.Join(rootDir, workdir))
+			}
+			pc.Run = detected[workdir].Get(kind)
+			pc.Source = CommandSourceDetected
+		}
+		if pc.Run == "" {
+			continue
+		}
+		if kind == CommandTest {
+			pc.Reporter, pc.Report, pc.CoverageReport = c.Reporter, c.Report, c.CoverageReport
+			if pc.Reporter == "" {
+				pc.Reporter = inferTestReporter(pc.Run)
+			}
+		}
+		cmds = append(cmds, pc)
+	}
+
+	return cmds, nil
+}
+
+// FindProjectCommand returns the command of the given kind, if any
+func FindProjectCommand(cmds []ProjectCommand, kind string) (ProjectCommand, bool) {
+	for _, c := range cmds {
+		if c.Kind == kind {
+			return c, true
+		}
+	}
+	return ProjectCommand{}, false
+}
@@ -0,0 +1,136 @@
+package ops
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseProjectConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    map[string]CommandConfig
+		wantErr string
+	}{
+		{"empty", "", nil, ""},
+		{"shorthand", "commands:\n  build: make\n", map[string]CommandConfig{"build": {Run: "make"}}, ""},
+		{"mapping", "commands:\n  test:\n    run: npm test\n    workdir: web\n    timeout: 5m\n",
+			map[string]CommandConfig{"test": {Run: "npm test", Workdir: "web", Timeout: "5m"}}, ""},
+		{"skip", "commands:\n  lint:\n    skip: true\n", map[string]CommandConfig{"lint": {Skip: true}}, ""},
+		{"unknown kind", "commands:\n  deploy: make deploy\n", nil, "commands.deploy: unknown command kind"},
+		{"bad timeout", "commands:\n  test:\n    run: go test\n    timeout: soon\n", nil, `invalid timeout "soon"`},
+		{"escaping workdir", "commands:\n  test:\n    run: go test\n    workdir: ../other\n", nil, "must be relative to the project root"},
+		{"reporter", "commands:\n  test:\n    run: npm test\n    reporter: junit\n    report: out/junit.xml\n",
+			map[string]CommandConfig{"test": {Run: "npm test", Reporter: "junit", Report: "out/junit.xml"}}, ""},
+		{"unknown reporter", "commands:\n  test:\n    reporter: mocha\n", nil, `unknown reporter "mocha"`},
+		{"junit without report", "commands:\n  test:\n    reporter: junit\n", nil, "the junit reporter needs a report file"},
+		{"reporter on build", "commands:\n  build:\n    reporter: go\n", nil, "only apply to test commands"},
+		{"coverage threshold", "coverage:\n  min_changed: 120\n", nil, "coverage.min_changed: 120 is not a percentage"},
+		{"coverage report on lint", "commands:\n  lint:\n    coverage_report: c.out\n", nil, "only apply to test commands"},
+		{"checks", "checks:\n  enabled: [diff, files]\n  timeout: 20m\n  dir: ci/checks\n", nil, ""},
+		{"checks timeout", "checks:\n  timeout: later\n", nil, `checks.timeout: invalid timeout "later"`},
+		{"checks dir", "checks:\n  dir: /etc/checks\n", nil, "checks.dir: \"/etc/checks\" must be relative to the project root"},
+		{"unknown field", "commands:\n  test:\n    cmd: go test\n", nil, "failed to parse project config"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := ParseProjectConfig([]byte(tt.input))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ParseProjectConfig() error = %v; want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseProjectConfig() failed: %v", err)
+			}
+			if len(cfg.Commands) != len(tt.want) {
+				t.Fatalf("ParseProjectConfig() = %+v; want %+v", cfg.Commands, tt.want)
+			}
+			for kind, want := range tt.want {
+				if cfg.Commands[kind] != want {
+					t.Errorf("commands.%s = %+v; want %+v", kind, cfg.Commands[kind], want)
+				}
+			}
+		})
+	}
+}
+
+func TestResolveProjectCommands(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"go.mod":           "module x\n",
+		"web/package.json": `{"scripts": {"test": "jest", "lint": "eslint ."}}`,
+		".opusflow/config.yaml": "commands:\n" +
+			"  build:\n    run: make all\n    timeout: 90s\n" +
+			"  lint:\n    workdir: web\n" +
+			"  typecheck:\n    skip: true\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cmds, err := ResolveProjectCommands(root)
+	if err != nil {
+		t.Fatalf("ResolveProjectCommands failed: %v", err)
+	}
+
+	want := []ProjectCommand{
+		{Kind: CommandBuild, Run: "make all", Timeout: 90 * time.Second, Source: CommandSourceConfig},
+		{Kind: CommandLint, Run: "npm run lint", Workdir: "web", Timeout: DefaultCommandTimeout, Source: CommandSourceDetected},
+		{Kind: CommandTest, Run: "go test -short ./...", Timeout: DefaultCommandTimeout, Source: CommandSourceDetected, Reporter: ReporterGo},
+	}
+	if len(cmds) != len(want) {
+		t.Fatalf("ResolveProjectCommands() = %+v; want %+v", cmds, want)
+	}
+	for i := range want {
+		if cmds[i] != want[i] {
+			t.Errorf("command %d = %+v; want %+v", i, cmds[i], want[i])
+		}
+	}
+	if got := cmds[1].CommandLine(); got != "cd web && npm run lint" {
+		t.Errorf("CommandLine() = %q", got)
+	}
+
+	// A workdir that doesn't exist is reported
+	os.WriteFile(filepath.Join(root, ".opusflow", "config.yaml"), []byte("commands:\n  test:\n    workdir: api\n"), 0644)
+	if _, err := ResolveProjectCommands(root); err == nil || !strings.Contains(err.Error(), "workdir api is not a directory") {
+		t.Errorf("Expected missing workdir error, got %v", err)
+	}
+}
+
+func TestRunProjectCommand(t *testing.T) {
+	root := t.TempDir()
+	if err := os.Mkdir(filepath.Join(root, "sub"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	output, err := runProjectCommand(context.Background(), root, ProjectCommand{Run: "basename \"$PWD\"", Workdir: "sub", Timeout: time.Minute})
+	if err != nil || strings.TrimSpace(output) != "sub" {
+		t.Errorf("Expected command to run in its workdir, got %q, %v", output, err)
+	}
+
+	if _, err := runProjectCommand(context.Background(), root, ProjectCommand{Run: "exit 3", Timeout: time.Minute}); err == nil {
+		t.Error("Expected failing command to return an error")
+	}
+
+	start := time.Now()
+	_, err = runProjectCommand(context.Background(), root, ProjectCommand{Run: "sleep 5", Timeout: 100 * time.Millisecond})
+	if err == nil || !strings.Contains(err.Error(), "timed out after 100ms") {
+		t.Errorf("Expected timeout error, got %v", err)
+	}
+	if time.Since(start) > 4*time.Second {
+		t.Error("Expected command to be killed at its timeout")
+	}
+}
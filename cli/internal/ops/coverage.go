@@ -0,0 +1,345 @@
+package ops
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// CoverageConfig sets coverage collection and thresholds (percentages)
+type CoverageConfig struct {
+	Enabled    bool    `yaml:"enabled,omitempty"` // collect without --coverage
+	MinTotal   float64 `yaml:"min_total,omitempty"`
+	MinChanged float64 `yaml:"min_changed,omitempty"`
+}
+
+// CoverageResult records the coverage measured during verification
+type CoverageResult struct {
+	Total          float64  `json:"total"` // percent of statements (Go) or lines covered
+	ChangedLines   int      `json:"changed_lines"`
+	ChangedCovered int      `json:"changed_covered"`
+	MinTotal       float64  `json:"min_total,omitempty"`
+	MinChanged     float64  `json:"min_changed,omitempty"`
+	Uncovered      []string `json:"uncovered,omitempty"` // changed line ranges without coverage
+}
+
+// ChangedPercent returns the percentage of changed executable lines covered
+func (cr *CoverageResult) ChangedPercent() float64 {
+	if cr.ChangedLines == 0 {
+		return 100
+	}
+	return 100 * float64(cr.ChangedCovered) / float64(cr.ChangedLines)
+}
+
+// coverageData is coverage merged from one or more reports
+type coverageData struct {
+	Lines   map[string]map[int]bool // file relative to the project root -> line -> covered
+	Covered int                     // statements (Go) or lines
+	Total   int
+}
+
+func newCoverageData() *coverageData {
+	return &coverageData{Lines: make(map[string]map[int]bool)}
+}
+
+// mark records a line as executable, and covered if any report covers it
+func (cd *coverageData) mark(file string, line int, covered bool) {
+	if cd.Lines[file] == nil {
+		cd.Lines[file] = make(map[int]bool)
+	}
+	cd.Lines[file][line] = cd.Lines[file][line] || covered
+}
+
+// merge adds other's coverage to cd
+func (cd *coverageData) merge(other *coverageData) {
+	if other == nil {
+		return
+	}
+	for file, lines := range other.Lines {
+		for line, covered := range lines {
+			cd.mark(file, line, covered)
+		}
+	}
+	cd.Covered += other.Covered
+	cd.Total += other.Total
+}
+
+// parseCoverageReport detects the report format (Go cover profile, LCOV or
+// Cobertura XML) and parses it. Paths in the report are resolved relative to
+// the command's workdir and returned relative to root.
+func parseCoverageReport(data []byte, root, workdir string) (*coverageData, error) {
+	trimmed := bytes.TrimSpace(data)
+	switch {
+	case bytes.HasPrefix(trimmed, []byte("mode:")):
+		return parseGoCoverProfile(data, modulePath(filepath.Join(root, workdir)), workdir)
+	case bytes.Contains(trimmed, []byte("<coverage")):
+		return parseCoberturaXML(data, root, workdir)
+	case bytes.Contains(trimmed, []byte("SF:")):
+		return parseLCOV(data, root, workdir), nil
+	}
+	return nil, fmt.Errorf("unrecognized coverage report format")
+}
+
+var goCoverLineRe = regexp.MustCompile(`^(.+):(\d+)\.\d+,(\d+)\.\d+ (\d+) (\d+)$`)
+
+// parseGoCoverProfile parses a go test -coverprofile file. Overall coverage
+// counts statements, like go test -cover does.
+func parseGoCoverProfile(data []byte, module, workdir string) (*coverageData, error) {
+	cd := newCoverageData()
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "mode:") {
+			continue
+		}
+		m := goCoverLineRe.FindStringSubmatch(line)
+		if m == nil {
+			return nil, fmt.Errorf("invalid cover profile line: %s", line)
+		}
+		start, _ := strconv.Atoi(m[2])
+		end, _ := strconv.Atoi(m[3])
+		stmts, _ := strconv.Atoi(m[4])
+		count, _ := strconv.Atoi(m[5])
+
+		file := m[1]
+		if module != "" && strings.HasPrefix(file, module+"/") {
+			file = filepath.ToSlash(filepath.Join(workdir, strings.TrimPrefix(file, module+"/")))
+		}
+		for l := start; l <= end; l++ {
+			cd.mark(file, l, count > 0)
+		}
+		cd.Total += stmts
+		if count > 0 {
+			cd.Covered += stmts
+		}
+	}
+	return cd, nil
+}
+
+// parseLCOV parses an LCOV tracefile such as jest, vitest or c8 write
+func parseLCOV(data []byte, root, workdir string) *coverageData {
+	cd := newCoverageData()
+	var file string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case strings.HasPrefix(line, "SF:"):
+			file = coverageFilePath(root, workdir, strings.TrimPrefix(line, "SF:"))
+		case strings.HasPrefix(line, "DA:") && file != "":
+			fields := strings.Split(strings.TrimPrefix(line, "DA:"), ",")
+			if len(fields) < 2 {
+				continue
+			}
+			n, err1 := strconv.Atoi(fields[0])
+			hits, err2 := strconv.Atoi(fields[1])
+			if err1 != nil || err2 != nil {
+				continue
+			}
+			cd.mark(file, n, hits > 0)
+			cd.Total++
+			if hits > 0 {
+				cd.Covered++
+			}
+		case line == "end_of_record":
+			file = ""
+		}
+	}
+	return cd
+}
+
+// coberturaReport is the Cobertura XML format pytest-cov and others write
+type coberturaReport struct {
+	Sources  []string `xml:"sources>source"`
+	Packages []struct {
+		Classes []struct {
+			Filename string `xml:"filename,attr"`
+			Lines    []struct {
+				Number int `xml:"number,attr"`
+				Hits   int `xml:"hits,attr"`
+			} `xml:"lines>line"`
+		} `xml:"classes>class"`
+	} `xml:"packages>package"`
+}
+
+// parseCoberturaXML parses a Cobertura XML report. Relative filenames are
+// resolved against the report's sources when they exist there.
+func parseCoberturaXML(data []byte, root, workdir string) (*coverageData, error) {
+	var report coberturaReport
+	if err := xml.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("failed to parse Cobertura report: %w", err)
+	}
+
+	cd := newCoverageData()
+	for _, pkg := range report.Packages {
+		for _, class := range pkg.Classes {
+			name := class.Filename
+			for _, src := range report.Sources {
+				candidate := filepath.Join(src, name)
+				if !filepath.IsAbs(candidate) {
+					candidate = filepath.Join(root, workdir, candidate)
+				}
+				if _, err := os.Stat(candidate); err == nil {
+					name = candidate
+					break
+				}
+			}
+			file := coverageFilePath(root, workdir, name)
+			for _, l := range class.Lines {
+				cd.mark(file, l.Number, l.Hits > 0)
+				cd.Total++
+				if l.Hits > 0 {
+					cd.Covered++
+				}
+			}
+		}
+	}
+	return cd, nil
+}
+
+// coverageFilePath returns a report path relative to the project root
+func coverageFilePath(root, workdir, path string) string {
+	if filepath.IsAbs(path) {
+		return relToRoot(root, path)
+	}
+	return filepath.ToSlash(filepath.Join(workdir, path))
+}
+
+var hunkHeaderRe = regexp.MustCompile(`^@@ -\d+(?:,\d+)? \+(\d+)(?:,(\d+))? @@`)
+
+// changedLines returns the lines added or modified since HEAD by file,
+// relative to root. Untracked files count as entirely changed.
+func changedLines(root string) (map[string][]int, error) {
+	cmd := exec.Command("git", "diff", "-U0", "--relative", "HEAD")
+	cmd.Dir = root
+	diff, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+	changed := parseChangedLines(string(diff))
+
+	others := exec.Command("git", "ls-files", "--others", "--exclude-standard")
+	others.Dir = root
+	untracked, err := others.Output()
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range strings.Fields(string(untracked)) {
+		data, err := os.ReadFile(filepath.Join(root, file))
+		if err != nil {
+			continue
+		}
+		n := bytes.Count(data, []byte("\n"))
+		if len(data) > 0 && !bytes.HasSuffix(data, []byte("\n")) {
+			n++
+		}
+		for l := 1; l <= n; l++ {
+			changed[file] = append(changed[file], l)
+		}
+	}
+	return changed, nil
+}
+
+// parseChangedLines extracts the new-side line numbers of each hunk in a
+// unified diff
+func parseChangedLines(diff string) map[string][]int {
+	changed := make(map[string][]int)
+	var file string
+	for _, line := range strings.Split(diff, "\n") {
+		switch {
+		case strings.HasPrefix(line, "+++ "):
+			file = strings.TrimPrefix(strings.TrimPrefix(line, "+++ "), "b/")
+			if file == "/dev/null" {
+				file = ""
+			}
+		case strings.HasPrefix(line, "@@") && file != "":
+			m := hunkHeaderRe.FindStringSubmatch(line)
+			if m == nil {
+				continue
+			}
+			start, _ := strconv.Atoi(m[1])
+			count := 1
+			if m[2] != "" {
+				count, _ = strconv.Atoi(m[2])
+			}
+			for l := start; l < start+count; l++ {
+				changed[file] = append(changed[file], l)
+			}
+		}
+	}
+	return changed
+}
+
+// evaluateCoverage computes overall and changed-line coverage against the
+// configured thresholds. Changed lines that aren't executable are ignored.
+func evaluateCoverage(cd *coverageData, changed map[string][]int, cfg CoverageConfig) *CoverageResult {
+	cr := &CoverageResult{MinTotal: cfg.MinTotal, MinChanged: cfg.MinChanged}
+	if cd.Total > 0 {
+		cr.Total = 100 * float64(cd.Covered) / float64(cd.Total)
+	}
+
+	files := make([]string, 0, len(changed))
+	for file := range changed {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+
+	for _, file := range files {
+		lines := cd.Lines[file]
+		if lines == nil {
+			continue
+		}
+		var uncovered []int
+		for _, l := range changed[file] {
+			covered, executable := lines[l]
+			if !executable {
+				continue
+			}
+			cr.ChangedLines++
+			if covered {
+				cr.ChangedCovered++
+			} else {
+				uncovered = append(uncovered, l)
+			}
+		}
+		for _, r := range lineRanges(uncovered) {
+			cr.Uncovered = append(cr.Uncovered, file+":"+r)
+		}
+	}
+	return cr
+}
+
+// lineRanges compresses sorted line numbers into ranges such as "4-7"
+func lineRanges(lines []int) []string {
+	sort.Ints(lines)
+	var ranges []string
+	for i := 0; i < len(lines); {
+		j := i
+		for j+1 < len(lines) && lines[j+1] == lines[j]+1 {
+			j++
+		}
+		if i == j {
+			ranges = append(ranges, strconv.Itoa(lines[i]))
+		} else {
+			ranges = append(ranges, fmt.Sprintf("%d-%d", lines[i], lines[j]))
+		}
+		i = j + 1
+	}
+	return ranges
+}
+
+// withGoCoverProfile makes a go test command write a cover profile
+func withGoCoverProfile(run, profile string) string {
+	if strings.Contains(run, "-coverprofile") {
+		return run
+	}
+	return strings.Replace(run, "go test", "go test -coverprofile="+shellQuote(profile), 1)
+}
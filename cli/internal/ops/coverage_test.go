@@ -0,0 +1,143 @@
+package ops
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseCoverageReport(t *testing.T) {
+	tests := []struct {
+		name    string
+		report  string
+		workdir string
+		total   int
+		covered int
+		lines   map[string]map[int]bool
+	}{
+		{
+			name:    "go profile",
+			report:  "mode: set\nexample.com/svc/calc/calc.go:3.30,5.2 2 1\nexample.com/svc/calc/calc.go:7.20,8.2 1 0\n",
+			workdir: "svc",
+			total:   3, covered: 2,
+			lines: map[string]map[int]bool{"svc/calc/calc.go": {3: true, 4: true, 5: true, 7: false, 8: false}},
+		},
+		{
+			name:    "lcov",
+			report:  "TN:\nSF:src/sum.ts\nDA:1,4\nDA:2,0\nLF:2\nLH:1\nend_of_record\nSF:/repo/web/src/b.ts\nDA:5,1\nend_of_record\n",
+			workdir: "web",
+			total:   3, covered: 2,
+			lines: map[string]map[int]bool{"web/src/sum.ts": {1: true, 2: false}, "web/src/b.ts": {5: true}},
+		},
+		{
+			name: "cobertura",
+			report: `<?xml version="1.0" ?>
+<coverage line-rate="0.5"><sources><source>src</source></sources>
+<packages><package name="app"><classes><class filename="app/api.py">
+<lines><line number="1" hits="1"/><line number="2" hits="0"/></lines>
+</class></classes></package></packages></coverage>`,
+			total: 2, covered: 1,
+			lines: map[string]map[int]bool{"app/api.py": {1: true, 2: false}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := "/repo"
+			if tt.workdir == "svc" {
+				root = t.TempDir()
+				writeTestFiles(t, root, map[string]string{"svc/go.mod": "module example.com/svc\n"})
+			}
+			cd, err := parseCoverageReport([]byte(tt.report), root, tt.workdir)
+			if err != nil {
+				t.Fatalf("parseCoverageReport failed: %v", err)
+			}
+			if cd.Total != tt.total || cd.Covered != tt.covered {
+				t.Errorf("Expected %d/%d covered, got %d/%d", tt.covered, tt.total, cd.Covered, cd.Total)
+			}
+			if !reflect.DeepEqual(cd.Lines, tt.lines) {
+				t.Errorf("Lines = %v; want %v", cd.Lines, tt.lines)
+			}
+		})
+	}
+
+	if _, err := parseCoverageReport([]byte("hello"), "/repo", ""); err == nil {
+		t.Error("Expected an error for an unknown format")
+	}
+}
+
+func TestParseChangedLines(t *testing.T) {
+	diff := `diff --git a/calc.go b/calc.go
+--- a/calc.go
++++ b/calc.go
+@@ -3 +3,2 @@ func Add(a, b int) int {
+-	return a - b
++	sum := a + b
++	return sum
+@@ -10,2 +11,0 @@
+-// old
+-// comment
+@@ -20 +19 @@
+-x
++y
+diff --git a/gone.go b/gone.go
+--- a/gone.go
++++ /dev/null
+@@ -1 +0,0 @@
+-package gone
+`
+	got := parseChangedLines(diff)
+	want := map[string][]int{"calc.go": {3, 4, 19}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseChangedLines() = %v; want %v", got, want)
+	}
+}
+
+func TestEvaluateCoverage(t *testing.T) {
+	cd := newCoverageData()
+	for line, covered := range map[int]bool{1: true, 2: false, 3: false, 4: true, 6: false} {
+		cd.mark("a.go", line, covered)
+	}
+	cd.Total, cd.Covered = 10, 6
+
+	changed := map[string][]int{
+		"a.go":      {2, 3, 4, 5, 6}, // 5 is not executable
+		"README.md": {1},
+	}
+	cr := evaluateCoverage(cd, changed, CoverageConfig{MinTotal: 70, MinChanged: 50})
+
+	if cr.Total != 60 || cr.ChangedLines != 4 || cr.ChangedCovered != 1 || cr.ChangedPercent() != 25 {
+		t.Errorf("Unexpected coverage: %+v", cr)
+	}
+	if strings.Join(cr.Uncovered, ",") != "a.go:2-3,a.go:6" {
+		t.Errorf("Uncovered = %v", cr.Uncovered)
+	}
+
+	vr := &VerificationResult{Coverage: cr}
+	vr.checkCoverage()
+	if vr.TotalChecks != 2 || vr.PassedChecks != 0 || len(vr.Comments) != 2 {
+		t.Fatalf("Expected two failed coverage checks, got %+v", vr)
+	}
+	if vr.Comments[0].Severity != SeverityMajor || !strings.Contains(vr.Comments[0].Title, "Changed lines coverage 25.0% is below 50%") || !reflect.DeepEqual(vr.Comments[0].Files, []string{"a.go"}) {
+		t.Errorf("Unexpected changed-lines comment: %+v", vr.Comments[0])
+	}
+	if vr.Comments[1].Severity != SeverityMinor || !strings.Contains(vr.Comments[1].Title, "Total coverage 60.0% is below 70%") {
+		t.Errorf("Unexpected total comment: %+v", vr.Comments[1])
+	}
+	if md := vr.FormatMarkdown(); !strings.Contains(md, "**Changed lines**: 25.0% (1/4) (minimum 50%)") {
+		t.Errorf("Expected coverage section, got:\n%s", md)
+	}
+
+	// Without thresholds coverage is only recorded
+	vr = &VerificationResult{Coverage: evaluateCoverage(cd, changed, CoverageConfig{})}
+	vr.checkCoverage()
+	if vr.TotalChecks != 0 || len(vr.Comments) != 0 {
+		t.Errorf("Expected no coverage checks without thresholds, got %+v", vr)
+	}
+}
+
+func TestLineRanges(t *testing.T) {
+	if got := lineRanges([]int{9, 1, 2, 3, 5}); strings.Join(got, ",") != "1-3,5,9" {
+		t.Errorf("lineRanges() = %v", got)
+	}
+}
@@ -1,28 +1,46 @@
 package ops
 
 import (
+	"context"
 	"fmt"
+	"os"
 	"os/exec"
 	"path/filepath"
 	"slices"
 	"strings"
 	"time"
 
+	"github.com/tuanpep/oplusflow/internal/frontmatter"
 	"github.com/tuanpep/oplusflow/internal/manager"
 )
 
 // VerificationResult represents the result of a verification check
 type VerificationResult struct {
-	PlanRef      string          `json:"plan_ref"`
-	SpecRef      string          `json:"spec_ref,omitempty"`
-	VerifiedAt   time.Time       `json:"verified_at"`
-	Status       string          `json:"status"` // passed, failed, partial
-	TotalChecks  int             `json:"total_checks"`
-	PassedChecks int             `json:"passed_checks"`
-	Comments     []VerifyComment `json:"comments,omitempty"`
-	DiffSummary  string          `json:"diff_summary,omitempty"`
-	BuildStatus  string          `json:"build_status,omitempty"`
-	TestStatus   string          `json:"test_status,omitempty"`
+	PlanRef         string             `json:"plan_ref"`
+	SpecRef         string             `json:"spec_ref,omitempty"`
+	VerifiedAt      time.Time          `json:"verified_at"`
+	Status          string             `json:"status"` // passed, failed, partial
+	TotalChecks     int                `json:"total_checks"`
+	PassedChecks    int                `json:"passed_checks"`
+	Comments        []VerifyComment    `json:"comments,omitempty"`
+	DiffSummary     string             `json:"diff_summary,omitempty"`
+	BuildStatus     string             `json:"build_status,omitempty"`
+	TestStatus      string             `json:"test_status,omitempty"`
+	LintStatus      string             `json:"lint_status,omitempty"`
+	TypecheckStatus string             `json:"typecheck_status,omitempty"`
+	Subprojects     []SubprojectResult `json:"subprojects,omitempty"`
+	TestResults     []TestCase         `json:"test_results,omitempty"`
+	Coverage        *CoverageResult    `json:"coverage,omitempty"`
+	Checks          []CheckRecord      `json:"checks,omitempty"`
+}
+
+// SubprojectResult is the verification outcome of one subproject
+type SubprojectResult struct {
+	Path         string            `json:"path"`
+	Name         string            `json:"name"`
+	ChangedFiles int               `json:"changed_files"`
+	Skipped      bool              `json:"skipped,omitempty"`  // no changed files
+	Statuses     map[string]string `json:"statuses,omitempty"` // by command kind
 }
 
 // VerifyComment represents a single verification comment
@@ -42,12 +60,25 @@ const (
 	SeverityOutdated = "outdated"
 )
 
+// VerifyOptions configures automated verification
+type VerifyOptions struct {
+	Coverage bool // collect coverage even if the project config doesn't enable it
+}
+
 // AutoVerifyPlan performs automated verification against a plan
-func AutoVerifyPlan(planPath string) (*VerificationResult, error) {
+func AutoVerifyPlan(planPath string, opts VerifyOptions) (*VerificationResult, error) {
 	root, err := manager.FindProjectRoot()
 	if err != nil {
 		return nil, fmt.Errorf("failed to find project root: %w", err)
 	}
+	cfg, err := LoadProjectConfig(root)
+	if err != nil {
+		return nil, err
+	}
+	checks, err := ResolveChecks(root, cfg)
+	if err != nil {
+		return nil, err
+	}
 
 	result := &VerificationResult{
 		PlanRef:    filepath.Base(planPath),
@@ -55,63 +86,10 @@ func AutoVerifyPlan(planPath string) (*VerificationResult, error) {
 		Comments:   []VerifyComment{},
 	}
 
-	// 1. Capture git diff
-	diffOutput, err := captureFullGitDiff(root)
-	if err == nil && diffOutput != "" {
-		result.DiffSummary = summarizeDiff(diffOutput)
-	}
-
-	// 2. Run build verification
-	buildOutput, buildErr := runBuildCheck(root)
-	if buildErr != nil {
-		result.BuildStatus = "❌ Failed"
-		result.Comments = append(result.Comments, VerifyComment{
-			Number:      len(result.Comments) + 1,
-			Severity:    SeverityCritical,
-			Title:       "Build Failed",
-			Description: fmt.Sprintf("Build verification failed:\n%s", buildOutput),
-		})
-	} else {
-		result.BuildStatus = "✅ Passed"
-		result.PassedChecks++
-	}
-	result.TotalChecks++
-
-	// 3. Run test verification
-	testOutput, testErr := runTestCheck(root)
-	if testErr != nil {
-		result.TestStatus = "❌ Failed"
-		result.Comments = append(result.Comments, VerifyComment{
-			Number:      len(result.Comments) + 1,
-			Severity:    SeverityMajor,
-			Title:       "Tests Failed",
-			Description: fmt.Sprintf("Test verification failed:\n%s", testOutput),
-		})
-	} else {
-		result.TestStatus = "✅ Passed"
-		result.PassedChecks++
-	}
-	result.TotalChecks++
-
-	// 4. Check if plan files were created/modified
-	planContent, err := ReadFile(planPath)
-	if err == nil {
-		filesFromPlan := extractFilesFromPlan(planContent)
-		for _, f := range filesFromPlan {
-			exists, _ := fileExists(filepath.Join(root, f))
-			if !exists {
-				result.Comments = append(result.Comments, VerifyComment{
-					Number:      len(result.Comments) + 1,
-					Severity:    SeverityMajor,
-					Title:       fmt.Sprintf("Missing file: %s", f),
-					Description: "A file specified in the plan was not created.",
-					Files:       []string{f},
-				})
-			} else {
-				result.PassedChecks++
-			}
-			result.TotalChecks++
-		}
+	cc := &CheckContext{Root: root, PlanPath: planPath, Config: cfg, Options: opts}
+	timeout := cfg.checkTimeout()
+	for _, check := range checks {
+		result.runCheck(cc, check, timeout)
 	}
 
 	// Determine overall status
@@ -175,81 +153,283 @@ func summarizeDiff(diff string) string {
 		len(files), additions, deletions, strings.Join(fileList, ", "))
 }
 
-// runBuildCheck attempts to build the project
-func runBuildCheck(root string) (string, error) {
-	// Try common build commands
-	buildCommands := []struct {
-		Cmd  string
-		Args []string
-	}{
-		{"go", []string{"build", "./..."}},
-		{"npm", []string{"run", "build"}},
-		{"make", []string{"build"}},
-	}
-
-	for _, bc := range buildCommands {
-		if _, err := exec.LookPath(bc.Cmd); err == nil {
-			cmd := exec.Command(bc.Cmd, bc.Args...)
-			cmd.Dir = root
-
-			output, err := cmd.CombinedOutput()
-			if err == nil {
-				return string(output), nil
+// statusNoCommand marks a command kind the project has no command for
+const statusNoCommand = "➖ No command detected"
+
+// commandFailureSeverity is the comment severity for each failing command kind
+var commandFailureSeverity = map[string]string{
+	CommandBuild:     SeverityCritical,
+	CommandTypecheck: SeverityMajor,
+	CommandLint:      SeverityMinor,
+	CommandTest:      SeverityMajor,
+}
+
+var commandLabel = map[string]string{
+	CommandBuild:     "Build",
+	CommandTypecheck: "Typecheck",
+	CommandLint:      "Lint",
+	CommandTest:      "Tests",
+}
+
+var commandFailureTitle = map[string]string{
+	CommandBuild:     "Build Failed",
+	CommandTypecheck: "Typecheck Failed",
+	CommandLint:      "Lint Failed",
+	CommandTest:      "Tests Failed",
+}
+
+// checkCoverage records a check per coverage threshold, with a major comment
+// when changed lines fall short and a minor one for overall coverage
+func (vr *VerificationResult) checkCoverage() {
+	cr := vr.Coverage
+	if cr.MinChanged > 0 && cr.ChangedLines > 0 {
+		vr.TotalChecks++
+		if pct := cr.ChangedPercent(); pct < cr.MinChanged {
+			files := make([]string, 0, len(cr.Uncovered))
+			for _, u := range cr.Uncovered {
+				f := u[:strings.LastIndex(u, ":")]
+				if !slices.Contains(files, f) {
+					files = append(files, f)
+				}
 			}
-			// If command exists but fails, report that
-			return string(output), err
+			vr.Comments = append(vr.Comments, VerifyComment{
+				Number:   len(vr.Comments) + 1,
+				Severity: SeverityMajor,
+				Title:    fmt.Sprintf("Changed lines coverage %.1f%% is below %.0f%%", pct, cr.MinChanged),
+				Description: fmt.Sprintf("%d of %d changed executable lines are covered by tests. Uncovered changed lines:\n- `%s`",
+					cr.ChangedCovered, cr.ChangedLines, strings.Join(cr.Uncovered, "`\n- `")),
+				Files: files,
+			})
+		} else {
+			vr.PassedChecks++
 		}
 	}
 
-	return "No build system detected", nil
+	if cr.MinTotal > 0 {
+		vr.TotalChecks++
+		if cr.Total < cr.MinTotal {
+			vr.Comments = append(vr.Comments, VerifyComment{
+				Number:      len(vr.Comments) + 1,
+				Severity:    SeverityMinor,
+				Title:       fmt.Sprintf("Total coverage %.1f%% is below %.0f%%", cr.Total, cr.MinTotal),
+				Description: "Overall test coverage is below the project threshold.",
+			})
+		} else {
+			vr.PassedChecks++
+		}
+	}
 }
 
-// runTestCheck attempts to run tests
-func runTestCheck(root string) (string, error) {
-	testCommands := []struct {
-		Cmd  string
-		Args []string
-	}{
-		{"go", []string{"test", "./...", "-short"}},
-		{"npm", []string{"test"}},
-		{"make", []string{"test"}},
-	}
-
-	for _, tc := range testCommands {
-		if _, err := exec.LookPath(tc.Cmd); err == nil {
-			cmd := exec.Command(tc.Cmd, tc.Args...)
-			cmd.Dir = root
-
-			output, err := cmd.CombinedOutput()
-			// Even if tests fail, we have output
-			return string(output), err
+// Command statuses
+const (
+	statusPassed      = "✅ Passed"
+	statusFailed      = "❌ Failed"
+	statusInterrupted = "⏱️ Interrupted"
+)
+
+// combineStatuses reduces the statuses of one command kind across
+// subprojects: any failure fails the whole
+func combineStatuses(statuses []string) string {
+	if len(statuses) == 0 {
+		return ""
+	}
+	for _, s := range statuses {
+		if s != statusPassed {
+			return s
 		}
 	}
+	return statusPassed
+}
 
-	return "No test system detected", nil
+// setCommandStatus records the outcome of a project command
+func (vr *VerificationResult) setCommandStatus(kind, status string) {
+	switch kind {
+	case CommandBuild:
+		vr.BuildStatus = status
+	case CommandTypecheck:
+		vr.TypecheckStatus = status
+	case CommandLint:
+		vr.LintStatus = status
+	case CommandTest:
+		vr.TestStatus = status
+	}
 }
 
-// extractFilesFromPlan extracts file paths mentioned in a plan
-func extractFilesFromPlan(content string) []string {
-	var files []string
-	lines := strings.Split(content, "\n")
+// runCommand runs a subproject command and records it as one check. Test
+// commands with a reporter get a comment per failing test; other failures get
+// a single comment with the command output. Coverage read from a test
+// command is returned. A command cut short by ctx is not recorded; the
+// caller reports why ctx was done.
+func (vr *VerificationResult) runCommand(ctx context.Context, root string, sub Subproject, pc ProjectCommand, prefix string, coverage bool) (string, *coverageData) {
+	var cases []TestCase
+	var cov *coverageData
+	var output string
+	var err error
+	if pc.Kind == CommandTest {
+		var tr testRun
+		tr, err = runTestCommand(ctx, root, pc, coverage)
+		cases, cov, output = tr.Cases, tr.Coverage, tr.Output
+	} else {
+		output, err = runProjectCommand(ctx, root, pc)
+	}
+	if ctx.Err() != nil {
+		return statusInterrupted, nil
+	}
+	vr.TotalChecks++
+
+	failed := 0
+	for i := range cases {
+		tc := &cases[i]
+		tc.Subproject = sub.Path
+		if tc.Status != TestFail {
+			continue
+		}
+		failed++
+		comment := VerifyComment{
+			Number:      len(vr.Comments) + 1,
+			Severity:    commandFailureSeverity[CommandTest],
+			Title:       fmt.Sprintf("%sTest failed: %s", prefix, tc.DisplayName()),
+			Description: fmt.Sprintf("```\n%s\n```", testOutputSnippet(tc.Output)),
+		}
+		if tc.File != "" {
+			comment.Description = fmt.Sprintf("**Location**: `%s`\n\n%s", tc.Location(), comment.Description)
+			comment.Files = []string{tc.File}
+		}
+		vr.Comments = append(vr.Comments, comment)
+	}
+	vr.TestResults = append(vr.TestResults, cases...)
 
-	for _, line := range lines {
-		// Look for **File**: patterns
-		if strings.Contains(line, "**File**:") || strings.Contains(line, "**File:**") {
-			// Extract path between backticks
-			start := strings.Index(line, "`")
-			end := strings.LastIndex(line, "`")
-			if start != -1 && end > start {
-				path := line[start+1 : end]
-				if path != "" && !slices.Contains(files, path) {
-					files = append(files, path)
-				}
-			}
+	if err == nil && failed == 0 {
+		vr.PassedChecks++
+		return statusPassed, cov
+	}
+	if failed == 0 {
+		vr.Comments = append(vr.Comments, VerifyComment{
+			Number:      len(vr.Comments) + 1,
+			Severity:    commandFailureSeverity[pc.Kind],
+			Title:       prefix + commandFailureTitle[pc.Kind],
+			Description: fmt.Sprintf("`%s` failed: %v\n%s", pc.CommandLine(), err, output),
+		})
+	}
+	return statusFailed, cov
+}
+
+// testRun is the outcome of a test command
+type testRun struct {
+	Cases    []TestCase    // nil without a readable test report
+	Coverage *coverageData // nil unless coverage was requested and read
+	Output   string
+}
+
+// runTestCommand runs a test command with its reporter enabled and parses the
+// per-test results and, when requested, the coverage report. Go tests write
+// a cover profile automatically; other runners need a coverage_report.
+func runTestCommand(ctx context.Context, root string, pc ProjectCommand, coverage bool) (testRun, error) {
+	run := pc
+	var reportPath, coverPath string
+	switch {
+	case pc.Reporter == "":
+	case pc.Report != "":
+		reportPath = filepath.Join(root, pc.Workdir, pc.Report)
+	case pc.Reporter == ReporterGo:
+		run.Run = withReporterFlags(run.Run, pc.Reporter, "")
+	default:
+		path, cleanup, err := tempReportFile()
+		if err != nil {
+			return testRun{}, err
 		}
+		defer cleanup()
+		reportPath = path
+		run.Run = withReporterFlags(run.Run, pc.Reporter, reportPath)
 	}
 
-	return files
+	switch {
+	case !coverage:
+	case pc.CoverageReport != "":
+		coverPath = filepath.Join(root, pc.Workdir, pc.CoverageReport)
+	case inferTestReporter(pc.Run) == ReporterGo:
+		path, cleanup, err := tempReportFile()
+		if err != nil {
+			return testRun{}, err
+		}
+		defer cleanup()
+		coverPath = path
+		run.Run = withGoCoverProfile(run.Run, coverPath)
+	}
+
+	started := time.Now()
+	output, err := runProjectCommand(ctx, root, run)
+	result := testRun{Output: output}
+	if pc.Reporter == ReporterGo {
+		result.Output = goTestPlainOutput(output)
+	}
+
+	if pc.Reporter != "" {
+		data := []byte(output)
+		if reportPath != "" {
+			data = readFreshReport(reportPath, started)
+		}
+		if cases, parseErr := parseTestReport(pc.Reporter, data, root, pc.Workdir); parseErr == nil && len(cases) > 0 {
+			result.Cases = cases
+		}
+	}
+	if coverPath != "" {
+		if data := readFreshReport(coverPath, started); data != nil {
+			result.Coverage, _ = parseCoverageReport(data, root, pc.Workdir)
+		}
+	}
+	return result, err
+}
+
+// tempReportFile creates an empty file for a runner to write a report to
+func tempReportFile() (string, func(), error) {
+	f, err := os.CreateTemp("", "opusflow-report-*")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create report file: %w", err)
+	}
+	f.Close()
+	return f.Name(), func() { os.Remove(f.Name()) }, nil
+}
+
+// readFreshReport reads a report file, ignoring one left over from a run
+// before started
+func readFreshReport(path string, started time.Time) []byte {
+	info, err := os.Stat(path)
+	if err != nil || info.ModTime().Before(started.Add(-time.Second)) {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	return data
+}
+
+// runProjectCommand runs a project command through the shell in its working
+// directory, killing it once its timeout elapses or ctx is done
+func runProjectCommand(parent context.Context, root string, pc ProjectCommand) (string, error) {
+	timeout := pc.Timeout
+	if timeout <= 0 {
+		timeout = DefaultCommandTimeout
+	}
+	ctx, cancel := context.WithTimeout(parent, timeout)
+	defer cancel()
+
+	cmd := shellCommand(ctx, pc.Run)
+	cmd.Dir = filepath.Join(root, pc.Workdir)
+	killProcessGroupOnCancel(cmd)
+	// Don't wait forever on pipes held open by the command's children
+	cmd.WaitDelay = 5 * time.Second
+
+	output, err := cmd.CombinedOutput()
+	switch {
+	case parent.Err() != nil:
+		// Report the deadline that fired, such as the check's timeout
+		return string(output), context.Cause(parent)
+	case ctx.Err() == context.DeadlineExceeded:
+		return string(output), fmt.Errorf("timed out after %s", timeout)
+	}
+	return string(output), err
 }
 
 // fileExists checks if a file exists
@@ -275,8 +455,94 @@ func (vr *VerificationResult) FormatMarkdown() string {
 	sb.WriteString(fmt.Sprintf("**Status**: %s %s\n\n", statusEmoji, strings.Title(vr.Status)))
 
 	sb.WriteString(fmt.Sprintf("**Checks**: %d/%d passed\n", vr.PassedChecks, vr.TotalChecks))
-	sb.WriteString(fmt.Sprintf("**Build**: %s\n", vr.BuildStatus))
-	sb.WriteString(fmt.Sprintf("**Tests**: %s\n\n", vr.TestStatus))
+	if vr.BuildStatus != "" {
+		sb.WriteString(fmt.Sprintf("**Build**: %s\n", vr.BuildStatus))
+	}
+	if vr.TypecheckStatus != "" {
+		sb.WriteString(fmt.Sprintf("**Typecheck**: %s\n", vr.TypecheckStatus))
+	}
+	if vr.LintStatus != "" {
+		sb.WriteString(fmt.Sprintf("**Lint**: %s\n", vr.LintStatus))
+	}
+	if vr.TestStatus != "" {
+		sb.WriteString(fmt.Sprintf("**Tests**: %s\n", vr.TestStatus))
+	}
+	sb.WriteString("\n")
+
+	if len(vr.Checks) > 0 {
+		sb.WriteString("## Checks\n\n")
+		sb.WriteString("| Check | Status | Duration |\n")
+		sb.WriteString("|-------|--------|----------|\n")
+		for _, c := range vr.Checks {
+			emoji := map[string]string{CheckPassed: "✅", CheckFailed: "❌", CheckSkipped: "⏭️", CheckError: "⚠️"}[c.Status]
+			sb.WriteString(fmt.Sprintf("| %s | %s %s | %s |\n", c.Name, emoji, c.Status, c.Duration.Round(time.Millisecond)))
+		}
+		sb.WriteString("\n")
+	}
+
+	if len(vr.Subprojects) > 0 {
+		sb.WriteString("## Subprojects\n\n")
+		for _, sr := range vr.Subprojects {
+			sb.WriteString(fmt.Sprintf("### %s\n\n", sr.Name))
+			sb.WriteString(fmt.Sprintf("**Changed files**: %d\n", sr.ChangedFiles))
+			switch {
+			case sr.Skipped:
+				sb.WriteString("**Status**: ⏭️ Skipped (not affected by the change)\n")
+			case len(sr.Statuses) == 0:
+				sb.WriteString(fmt.Sprintf("**Status**: %s\n", statusNoCommand))
+			default:
+				for _, kind := range CommandKinds {
+					if status, ok := sr.Statuses[kind]; ok {
+						sb.WriteString(fmt.Sprintf("**%s**: %s\n", commandLabel[kind], status))
+					}
+				}
+			}
+			sb.WriteString("\n")
+		}
+	}
+
+	if len(vr.TestResults) > 0 {
+		passed, failed, skipped, total := countTestCases(vr.TestResults)
+		sb.WriteString("## Test Results\n\n")
+		sb.WriteString(fmt.Sprintf("**%d passed, %d failed, %d skipped** (%s)\n\n", passed, failed, skipped, total.Round(time.Millisecond)))
+		if failed+skipped > 0 {
+			cases := slices.Clone(vr.TestResults)
+			sortTestCases(cases)
+			sb.WriteString("| Status | Test | Duration | Location |\n")
+			sb.WriteString("|--------|------|----------|----------|\n")
+			for _, tc := range cases[:failed+skipped] {
+				emoji := "❌"
+				if tc.Status == TestSkip {
+					emoji = "⏭️"
+				}
+				location := ""
+				if tc.File != "" {
+					location = "`" + tc.Location() + "`"
+				}
+				sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s |\n", emoji, tc.DisplayName(), tc.Duration.Round(time.Millisecond), location))
+			}
+			sb.WriteString("\n")
+		}
+	}
+
+	if cr := vr.Coverage; cr != nil {
+		sb.WriteString("## Coverage\n\n")
+		sb.WriteString(fmt.Sprintf("**Total**: %.1f%%", cr.Total))
+		if cr.MinTotal > 0 {
+			sb.WriteString(fmt.Sprintf(" (minimum %.0f%%)", cr.MinTotal))
+		}
+		sb.WriteString("\n")
+		if cr.ChangedLines > 0 {
+			sb.WriteString(fmt.Sprintf("**Changed lines**: %.1f%% (%d/%d)", cr.ChangedPercent(), cr.ChangedCovered, cr.ChangedLines))
+			if cr.MinChanged > 0 {
+				sb.WriteString(fmt.Sprintf(" (minimum %.0f%%)", cr.MinChanged))
+			}
+			sb.WriteString("\n")
+		} else {
+			sb.WriteString("**Changed lines**: no executable lines changed\n")
+		}
+		sb.WriteString("\n")
+	}
 
 	if vr.DiffSummary != "" {
 		sb.WriteString("## Changes Summary\n\n")
@@ -413,7 +679,22 @@ func (vr *VerificationResult) Save() (string, error) {
 		timestamp)
 	filePath := filepath.Join(verifyDir, filename)
 
-	content := vr.FormatMarkdown()
+	meta := NewDocumentMeta(filePath, frontmatter.KindVerification, vr.Status)
+	meta.PlanRef = vr.PlanRef
+	meta.SpecRef = vr.SpecRef
+	if meta.SpecRef == "" {
+		// Inherit the spec link from the plan's frontmatter
+		if planPath, err := ResolvePlanPath(vr.PlanRef); err == nil {
+			if pm, err := ReadDocumentMeta(planPath); err == nil && pm != nil {
+				meta.SpecRef = pm.SpecRef
+			}
+		}
+	}
+
+	content, err := meta.Render(vr.FormatMarkdown())
+	if err != nil {
+		return "", err
+	}
 	if err := WriteFile(filePath, content); err != nil {
 		return "", fmt.Errorf("failed to write report: %w", err)
 	}
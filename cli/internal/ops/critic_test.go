@@ -5,57 +5,6 @@ import (
 	"testing"
 )
 
-func TestExtractFilesFromPlan(t *testing.T) {
-	planContent := `# Plan
-
-## Steps
-
-### Step 1
-**File**: ` + "`src/main.go`" + `
-
-### Step 2
-**File:** ` + "`config/config.yaml`" + `
-
-### Step 3
-No file here.
-
-### Step 4
-**File**: ` + "`src/utils.go`" + `
-`
-
-	files := extractFilesFromPlan(planContent)
-
-	if len(files) != 3 {
-		t.Errorf("Expected 3 files, got %d: %v", len(files), files)
-	}
-
-	expected := map[string]bool{
-		"src/main.go":        true,
-		"config/config.yaml": true,
-		"src/utils.go":       true,
-	}
-
-	for _, f := range files {
-		if !expected[f] {
-			t.Errorf("Unexpected file: %s", f)
-		}
-	}
-}
-
-func TestExtractFilesFromPlan_NoDuplicates(t *testing.T) {
-	planContent := `
-**File**: ` + "`same.go`" + `
-**File**: ` + "`same.go`" + `
-**File**: ` + "`different.go`" + `
-`
-
-	files := extractFilesFromPlan(planContent)
-
-	if len(files) != 2 {
-		t.Errorf("Expected 2 unique files, got %d: %v", len(files), files)
-	}
-}
-
 func TestSummarizeDiff(t *testing.T) {
 	diff := `diff --git a/file1.go b/file1.go
 index abc123..def456 100644
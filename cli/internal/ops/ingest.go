@@ -0,0 +1,406 @@
+package ops
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/tuanpep/oplusflow/internal/frontmatter"
+	"github.com/tuanpep/oplusflow/internal/manager"
+	"github.com/tuanpep/oplusflow/internal/plan"
+	"github.com/tuanpep/oplusflow/internal/spec"
+)
+
+// RevisionReasonIngest is recorded with plan snapshots taken by plan ingest
+const RevisionReasonIngest = "plan ingest"
+
+// IngestOptions are the settings for IngestSpec and IngestPlan
+type IngestOptions struct {
+	// DryRun validates the output and returns the diff without writing
+	DryRun bool
+	// Force writes the document even if validation fails
+	Force bool
+}
+
+// IngestResult describes model output ingested into a spec or plan
+type IngestResult struct {
+	Path       string   `json:"path"`
+	Kind       string   `json:"kind"`
+	BackupPath string   `json:"backup_path,omitempty"`
+	Errors     []string `json:"errors"`
+	Warnings   []string `json:"warnings"`
+	Diff       string   `json:"diff"`
+	Written    bool     `json:"written"`
+	// StatusReset is set when a reviewed spec was changed and moved back to Draft
+	StatusReset bool `json:"status_reset,omitempty"`
+	// TasksUpdated is set when an ingested plan's task queue was rebuilt to match
+	TasksUpdated bool `json:"tasks_updated,omitempty"`
+}
+
+var (
+	// fenceOpenPattern matches a fence that may wrap a whole markdown document
+	fenceOpenPattern = regexp.MustCompile("^(`{3,}|~{3,})\\s*(markdown|md)?\\s*$")
+	// wrapperTagPattern matches chat wrapper tags such as <document> or </output>
+	wrapperTagPattern = regexp.MustCompile(`^</?[A-Za-z][\w-]*>$`)
+	// chatLinePattern matches conversational lines models put around documents
+	chatLinePattern = regexp.MustCompile(`(?i)^(here('s| is| are)|sure[,!.]|certainly|of course|i('ve| have)|i hope|hope this|let me know|feel free|would you like|if you (want|need|have|'d like)|this (completed |updated |revised )?(spec|specification|plan|document)|the (completed |updated )?(spec|specification|plan) (above|below))`)
+	// signOffPattern matches chat lines that never belong to the document itself
+	signOffPattern = regexp.MustCompile(`(?i)^(i hope|hope this|let me know|feel free|would you like|if you (want|need|have|'d like))`)
+)
+
+// ExtractDocument returns the markdown document inside model output: chat
+// preambles and sign-offs, wrapper tags and a fence around the whole document
+// are removed. Fences inside the document are kept.
+func ExtractDocument(output string) string {
+	doc, _ := extractDocument(output)
+	return doc
+}
+
+// extractDocument is ExtractDocument that also returns a final paragraph that
+// reads like chat but was kept, since nothing marks it as outside the document
+func extractDocument(output string) (string, string) {
+	lines := strings.Split(strings.ReplaceAll(output, "\r\n", "\n"), "\n")
+
+	// Drop everything before the document starts: frontmatter, a heading, or a
+	// fence whose first line is one of those
+	start := -1
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if isDocumentStart(lines, i) {
+			start = i
+			break
+		}
+		if m := fenceOpenPattern.FindStringSubmatch(trimmed); m != nil {
+			if next := nextNonBlank(lines, i+1); next >= 0 && isDocumentStart(lines, next) {
+				if end := closingFence(lines, i, m[1]); end > 0 {
+					return strings.TrimSpace(strings.Join(lines[next:end], "\n")) + "\n", ""
+				}
+			}
+		}
+	}
+	if start < 0 {
+		return strings.TrimSpace(output) + "\n", ""
+	}
+	lines = lines[start:]
+
+	// Drop trailing wrapper tags, stray closing fences and chat sign-offs. Other
+	// chat-like paragraphs are only dropped after a wrapper tag or stray fence;
+	// a plan may well end with "This plan is complete when ..."
+	end := len(lines)
+	suspect := ""
+	for end > 0 {
+		trimmed := strings.TrimSpace(lines[end-1])
+		switch {
+		case trimmed == "", wrapperTagPattern.MatchString(trimmed):
+			end--
+			continue
+		case strings.Trim(trimmed, "`~") == "" && unbalancedFence(lines[:end]):
+			end--
+			continue
+		}
+		p := paragraphStart(lines, end)
+		first := strings.TrimSpace(lines[p])
+		if signOffPattern.MatchString(first) || (chatLinePattern.MatchString(first) && afterWrapper(lines, p)) {
+			end = p
+			continue
+		}
+		if chatLinePattern.MatchString(first) {
+			suspect = first
+		}
+		break
+	}
+	return strings.Join(lines[:end], "\n") + "\n", suspect
+}
+
+// afterWrapper reports whether the paragraph starting at p follows a wrapper
+// tag or a stray closing fence, so it sits outside the document
+func afterWrapper(lines []string, p int) bool {
+	i := p - 1
+	for i >= 0 && strings.TrimSpace(lines[i]) == "" {
+		i--
+	}
+	if i < 0 {
+		return false
+	}
+	trimmed := strings.TrimSpace(lines[i])
+	return wrapperTagPattern.MatchString(trimmed) || (strings.Trim(trimmed, "`~") == "" && unbalancedFence(lines[:i+1]))
+}
+
+// isDocumentStart reports whether line i opens a document: frontmatter or a level-1 heading
+func isDocumentStart(lines []string, i int) bool {
+	trimmed := strings.TrimSpace(lines[i])
+	if strings.HasPrefix(trimmed, "# ") {
+		return true
+	}
+	return trimmed == "---" && i+1 < len(lines) && strings.Contains(lines[i+1], ":")
+}
+
+func nextNonBlank(lines []string, i int) int {
+	for ; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) != "" {
+			return i
+		}
+	}
+	return -1
+}
+
+// closingFence returns the last line closing a fence opened with marker at
+// open, or -1. Inner fences open and close in pairs before it.
+func closingFence(lines []string, open int, marker string) int {
+	for i := len(lines) - 1; i > open; i-- {
+		trimmed := strings.TrimSpace(lines[i])
+		if trimmed == "" || wrapperTagPattern.MatchString(trimmed) || chatLinePattern.MatchString(trimmed) {
+			continue
+		}
+		if strings.HasPrefix(trimmed, marker[:3]) && strings.Trim(trimmed, marker[:1]) == "" && len(trimmed) >= len(marker) {
+			return i
+		}
+	}
+	return -1
+}
+
+// unbalancedFence reports whether lines leave a code fence open
+func unbalancedFence(lines []string) bool {
+	open := false
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			open = !open
+		}
+	}
+	return open
+}
+
+// paragraphStart returns the first line of the paragraph ending before end
+func paragraphStart(lines []string, end int) int {
+	i := end - 1
+	for i > 0 && strings.TrimSpace(lines[i-1]) != "" {
+		i--
+	}
+	return i
+}
+
+// IngestSpec replaces a spec with model output read from input. The existing
+// frontmatter is kept; a reviewed spec whose content changes goes back to Draft.
+func IngestSpec(ref, input string, opts IngestOptions) (*IngestResult, error) {
+	path, err := ResolveSpecPath(ref)
+	if err != nil {
+		return nil, err
+	}
+	return ingestDocument(path, frontmatter.KindSpec, input, opts)
+}
+
+// IngestPlan replaces a plan with model output read from input. The existing
+// frontmatter is kept and the previous version is recorded as a plan revision.
+func IngestPlan(ref, input string, opts IngestOptions) (*IngestResult, error) {
+	path, err := ResolvePlanPath(ref)
+	if err != nil {
+		return nil, err
+	}
+	return ingestDocument(path, frontmatter.KindPlan, input, opts)
+}
+
+func ingestDocument(path, kind, input string, opts IngestOptions) (*IngestResult, error) {
+	root, err := manager.FindProjectRoot()
+	if err != nil {
+		return nil, fmt.Errorf("failed to find project root: %w", err)
+	}
+
+	original, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", kind, err)
+	}
+	oldMeta, oldBody, err := frontmatter.Parse(string(original))
+	if err != nil {
+		return nil, err
+	}
+
+	// The model's own frontmatter, if any, is discarded in favour of the document's
+	doc, suspect := extractDocument(input)
+	_, body, _, _ := frontmatter.Split(doc)
+	body = strings.TrimLeft(body, "\n")
+
+	result := &IngestResult{Path: path, Kind: kind, Errors: []string{}, Warnings: []string{}}
+	if strings.TrimSpace(body) == "" {
+		return nil, fmt.Errorf("no %s content found in the input", kind)
+	}
+	if suspect != "" {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("the last paragraph looks like chat but was kept: %q", suspect))
+	}
+
+	switch kind {
+	case frontmatter.KindSpec:
+		validateIngestedSpec(result, oldBody, body)
+	case frontmatter.KindPlan:
+		validateIngestedPlan(result, oldBody, body, root)
+	}
+
+	content := body
+	if oldMeta != nil {
+		if kind == frontmatter.KindSpec && body != oldBody && oldMeta.Status != "" && oldMeta.Status != spec.StatusDraft {
+			oldMeta.Status = spec.StatusDraft
+			result.StatusReset = true
+		}
+		oldMeta.Touch()
+		if content, err = oldMeta.Render(body); err != nil {
+			return nil, err
+		}
+	}
+
+	result.Diff = UnifiedDiff(string(original), content, "a/"+filepath.Base(path), "b/"+filepath.Base(path))
+	if opts.DryRun || (len(result.Errors) > 0 && !opts.Force) {
+		return result, nil
+	}
+
+	if kind == frontmatter.KindPlan {
+		// Keep the version being replaced; history is best effort
+		_, _ = SnapshotPlan(path, RevisionReasonIngest)
+	}
+	if result.BackupPath, err = backupDocument(root, path, original); err != nil {
+		return nil, err
+	}
+
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return nil, fmt.Errorf("failed to write %s: %w", kind, err)
+	}
+	result.Written = true
+	if kind == frontmatter.KindPlan {
+		rev, _ := SnapshotPlan(path, RevisionReasonIngest)
+		if result.TasksUpdated, err = renumberTaskQueue(path, content, ingestMoves(oldBody, body), rev); err != nil {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("task queue not updated (%v); run decompose again", err))
+		}
+	}
+	return result, nil
+}
+
+// ingestMoves pairs the ingested plan's steps with the previous version's by
+// title, so tasks keep their status when the model renumbers steps. New steps
+// have Old 0.
+func ingestMoves(oldBody, body string) []plan.StepMove {
+	oldPlan, _ := plan.Parse(oldBody)
+	newPlan, _ := plan.Parse(body)
+
+	numbers := make(map[string]int)
+	for i, st := range oldPlan.Steps {
+		key := strings.ToLower(strings.TrimSpace(st.Title))
+		if _, dup := numbers[key]; dup {
+			numbers[key] = 0
+			continue
+		}
+		numbers[key] = i + 1
+	}
+
+	moves := make([]plan.StepMove, len(newPlan.Steps))
+	for i, st := range newPlan.Steps {
+		moves[i] = plan.StepMove{Old: numbers[strings.ToLower(strings.TrimSpace(st.Title))], New: i + 1}
+	}
+	return moves
+}
+
+// backupDocument copies a document's current content to .opusflow/backups
+func backupDocument(root, path string, content []byte) (string, error) {
+	dir := manager.BackupsDir(root)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create backups directory: %w", err)
+	}
+
+	base := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	backup := filepath.Join(dir, fmt.Sprintf("%s.%s%s", base, time.Now().Format("20060102-150405"), filepath.Ext(path)))
+	if err := os.WriteFile(backup, content, 0644); err != nil {
+		return "", fmt.Errorf("failed to write backup: %w", err)
+	}
+	return backup, nil
+}
+
+// validateIngestedSpec requires a title and the scored required sections, and
+// warns about sections of the previous version that disappeared
+func validateIngestedSpec(r *IngestResult, oldBody, body string) {
+	s := spec.Parse(body)
+	if s.Title == "" {
+		r.Errors = append(r.Errors, "missing the level-1 title heading")
+	}
+
+	check := CheckSpecContent(body, 0)
+	missing := make(map[string]bool)
+	for _, sec := range check.Sections {
+		if sec.Required && sec.Missing {
+			missing[sec.Title] = true
+			r.Errors = append(r.Errors, fmt.Sprintf("missing required section %q", sec.Title))
+		}
+	}
+
+	old := spec.Parse(oldBody)
+	for _, sec := range old.Sections {
+		if s.Section(sec.Title) == nil && !missing[sec.Title] {
+			r.Warnings = append(r.Warnings, fmt.Sprintf("section %q from the previous version is missing", sec.Title))
+		}
+	}
+	if blockers := check.Blockers(); len(blockers) > 0 {
+		r.Warnings = append(r.Warnings, fmt.Sprintf("spec check score %d/100: %s", check.Score, strings.Join(blockers, "; ")))
+	}
+}
+
+// validateIngestedPlan requires a plan that parses with a title, a goal and
+// implementation steps, and warns about lost sections and lint findings
+func validateIngestedPlan(r *IngestResult, oldBody, body, root string) {
+	p, _ := plan.Parse(body)
+	if p.Title == "" {
+		r.Errors = append(r.Errors, "missing the level-1 title heading")
+	}
+	if p.Section(plan.SectionGoal) == nil {
+		r.Errors = append(r.Errors, fmt.Sprintf("missing required section %q", plan.SectionGoal))
+	}
+
+	others := 0
+	for _, issue := range LintPlanContent(body, root).Issues {
+		switch issue.Rule {
+		case RuleParse, RuleNoSteps:
+			r.Errors = append(r.Errors, fmt.Sprintf("line %d: %s", issue.Line, issue.Message))
+		default:
+			others++
+		}
+	}
+	if others > 0 {
+		r.Warnings = append(r.Warnings, fmt.Sprintf("plan lint reports %d more issues (opusflow plan lint)", others))
+	}
+
+	old, _ := plan.Parse(oldBody)
+	for _, sec := range old.Sections {
+		if p.Section(sec.Title) == nil && sec.Title != plan.SectionGoal {
+			r.Warnings = append(r.Warnings, fmt.Sprintf("section %q from the previous version is missing", sec.Title))
+		}
+	}
+}
+
+// FormatIngestResult summarizes an ingest for the terminal
+func FormatIngestResult(r *IngestResult) string {
+	var sb strings.Builder
+	name := filepath.Base(r.Path)
+
+	for _, e := range r.Errors {
+		sb.WriteString(fmt.Sprintf("❌ %s\n", e))
+	}
+	for _, w := range r.Warnings {
+		sb.WriteString(fmt.Sprintf("⚠️  %s\n", w))
+	}
+
+	switch {
+	case r.Written:
+		sb.WriteString(fmt.Sprintf("✅ Updated %s\n", r.Path))
+		sb.WriteString(fmt.Sprintf("   Backup: %s\n", r.BackupPath))
+		if r.StatusReset {
+			sb.WriteString("   Status reset to Draft; submit the spec for review again\n")
+		}
+		if r.TasksUpdated {
+			sb.WriteString("   Task queue renumbered to match\n")
+		}
+	case len(r.Errors) > 0:
+		sb.WriteString(fmt.Sprintf("%s was not changed; fix the output or use --force\n", name))
+	default:
+		sb.WriteString(fmt.Sprintf("Dry run: %s was not changed\n", name))
+	}
+	return sb.String()
+}
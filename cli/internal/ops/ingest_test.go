@@ -0,0 +1,202 @@
+package ops
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tuanpep/oplusflow/internal/frontmatter"
+	"github.com/tuanpep/oplusflow/internal/spec"
+)
+
+func TestExtractDocument(t *testing.T) {
+	doc := "# Title\n\n## Goal\n\nDo it\n\n```go\nfmt.Println(1)\n```\n"
+
+	tests := []struct {
+		name   string
+		output string
+	}{
+		{"plain", doc},
+		{"preamble and sign-off", "Sure! Here's the completed spec:\n\n" + doc + "\nLet me know if you want any changes.\n"},
+		{"markdown fence", "Here is the plan:\n\n```markdown\n" + doc + "```\n\nI hope this helps!"},
+		{"bare fence", "```\n" + doc + "```"},
+		{"longer fence", "````md\n" + doc + "````\n"},
+		{"wrapper tags", "<document>\n" + doc + "</document>\n"},
+		{"crlf", strings.ReplaceAll("Here you go:\n"+doc, "\n", "\r\n")},
+		{"stray closing fence", doc + "```\n"},
+		{"chat after wrapper", "<document>\n" + doc + "</document>\n\nHere is the spec you asked for.\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExtractDocument(tt.output); got != doc {
+				t.Errorf("ExtractDocument() = %q; want %q", got, doc)
+			}
+		})
+	}
+
+	// Frontmatter marks the document start too
+	withMeta := "Output:\n---\nid: x\n---\n" + doc
+	if got := ExtractDocument(withMeta); got != "---\nid: x\n---\n"+doc {
+		t.Errorf("Expected frontmatter to be kept, got %q", got)
+	}
+
+	// A closing paragraph that only reads like chat stays part of the document
+	ending := doc + "\nThis plan is complete when the migration has run in production.\n"
+	if got, suspect := extractDocument(ending); got != ending || suspect == "" {
+		t.Errorf("extractDocument() = %q, %q; want the paragraph kept and reported", got, suspect)
+	}
+}
+
+func TestIngestSpec(t *testing.T) {
+	tmpDir := t.TempDir()
+	specsDir := filepath.Join(tmpDir, "opusflow-planning", "specs")
+	if err := os.MkdirAll(specsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	oldWd, _ := os.Getwd()
+	os.Chdir(tmpDir)
+	defer os.Chdir(oldWd)
+
+	m := &frontmatter.Meta{ID: "spec-oauth", Kind: frontmatter.KindSpec, Status: spec.StatusApproved}
+	original, err := m.Render("# Feature Specification: OAuth\n\n## Goal\n\n> Sign in\n\n## Open Questions\n\n1. [Question]\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	specPath := filepath.Join(specsDir, "spec-oauth.md")
+	if err := os.WriteFile(specPath, []byte(original), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Missing required sections block the write
+	result, err := IngestSpec("spec-oauth.md", "Here it is:\n\n# Feature Specification: OAuth\n\n## Goal\n\n> Sign in with Google\n", IngestOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Written || len(result.Errors) != 3 || !strings.Contains(strings.Join(result.Warnings, "\n"), `"Open Questions"`) {
+		t.Errorf("Expected missing sections to block the write, got %+v", result)
+	}
+	if data, _ := os.ReadFile(specPath); string(data) != original {
+		t.Error("Expected the spec to be unchanged")
+	}
+
+	complete := "```markdown\n# Feature Specification: OAuth\n\n## Goal\n\n> Sign in with Google\n\n" +
+		"### Functional Requirements\n\n- [ ] **FR1**: Redirect to Google\n\n" +
+		"## Edge Cases\n\n| Edge Case | Expected Behavior |\n|---|---|\n| Denied | Show \"cancelled\" |\n\n" +
+		"## Success Criteria\n\n- [ ] **SC1**: `GET /login` returns 302\n\n## Open Questions\n\n1. [x] None\n```\n"
+
+	result, err = IngestSpec("spec-oauth.md", complete, IngestOptions{DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Written || len(result.Errors) != 0 || !strings.Contains(result.Diff, "+> Sign in with Google") {
+		t.Errorf("Expected a clean dry run with a diff, got %+v", result)
+	}
+
+	result, err = IngestSpec("spec-oauth.md", complete, IngestOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Written || !result.StatusReset {
+		t.Fatalf("Expected the spec to be written and reset to Draft, got %+v", result)
+	}
+	backup, _ := os.ReadFile(result.BackupPath)
+	if string(backup) != original {
+		t.Errorf("Expected the backup to hold the previous version, got:\n%s", backup)
+	}
+
+	s, _, err := LoadSpec("spec-oauth.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Status != spec.StatusDraft || s.Meta.ID != "spec-oauth" || s.Goal != "Sign in with Google" {
+		t.Errorf("Expected ingested content with the original frontmatter, got %+v", s)
+	}
+}
+
+func TestIngestPlan(t *testing.T) {
+	tmpDir := t.TempDir()
+	plansDir := filepath.Join(tmpDir, "opusflow-planning", "plans")
+	if err := os.MkdirAll(plansDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	oldWd, _ := os.Getwd()
+	os.Chdir(tmpDir)
+	defer os.Chdir(oldWd)
+
+	planPath := filepath.Join(plansDir, "plan-01-x.md")
+	original := "# X\n\n## Goal\nDo X\n\n## Implementation Steps\n\n### Step 1: [Step Title]\n"
+	if err := os.WriteFile(planPath, []byte(original), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := IngestPlan("plan-01-x.md", "Here's the plan:\n\n# X\n\n## Goal\nDo X\n", IngestOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Written || len(result.Errors) == 0 {
+		t.Errorf("Expected a plan without steps to be refused, got %+v", result)
+	}
+
+	completed := "# X\n\n## Goal\nDo X\n\n## Implementation Steps\n\n### Step 1: Add the handler\n" +
+		"**File**: `main.go`\n**Action**: Create\n\n**Verification**:\n- [ ] Automated: `go test ./...`\n"
+	result, err = IngestPlan("plan-01-x.md", completed+"\nHope this helps!\n", IngestOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Written {
+		t.Fatalf("Expected the plan to be written, got %+v", result)
+	}
+	if data, _ := os.ReadFile(planPath); string(data) != completed {
+		t.Errorf("Expected the extracted plan, got:\n%s", data)
+	}
+
+	// A final paragraph that reads like chat is kept, with a warning
+	withDone := completed + "\nThis plan is complete when the migration has run in production.\n"
+	result, err = IngestPlan("plan-01-x.md", withDone, IngestOptions{DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(result.Diff, "+This plan is complete") || !strings.Contains(strings.Join(result.Warnings, "\n"), "looks like chat") {
+		t.Errorf("Expected the paragraph kept with a warning, got %+v", result)
+	}
+
+	revisions, err := ListPlanRevisions("plan-01-x.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(revisions) != 2 || revisions[1].Reason != RevisionReasonIngest {
+		t.Errorf("Expected the previous and ingested versions as revisions, got %+v", revisions)
+	}
+
+	// The task queue follows the steps by title, keeping progress
+	tq, err := QuickDecomposeFromFile(planPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tq.CompleteTask("task-1")
+	if err := tq.Save(); err != nil {
+		t.Fatal(err)
+	}
+	reordered := "# X\n\n## Goal\nDo X\n\n## Implementation Steps\n\n### Step 1: Add the schema\n" +
+		"**File**: `schema.sql`\n**Action**: Create\n\n**Verification**:\n- [ ] Automated: `go test ./...`\n\n" +
+		"### Step 2: Add the handler\n**File**: `main.go`\n**Action**: Create\n\n**Verification**:\n- [ ] Automated: `go test ./...`\n"
+	result, err = IngestPlan("plan-01-x.md", reordered, IngestOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Written || !result.TasksUpdated {
+		t.Fatalf("Expected the task queue to be updated, got %+v", result)
+	}
+	tq, _ = LoadTaskQueue("plan-01-x.md")
+	if len(tq.Tasks) != 2 || tq.Tasks[0].Status != TaskStatusPending || tq.Tasks[1].Title != "Add the handler" || tq.Tasks[1].Status != TaskStatusDone {
+		t.Errorf("Unexpected tasks after ingest: %+v", tq.Tasks)
+	}
+	revisions, _ = ListPlanRevisions("plan-01-x.md")
+	if tq.CompletedSteps != 1 || tq.PlanRevision != revisions[len(revisions)-1].Number {
+		t.Errorf("Expected progress and the new revision, got %d done, revision %d", tq.CompletedSteps, tq.PlanRevision)
+	}
+}
@@ -0,0 +1,475 @@
+package ops
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Issue tracker export formats
+const (
+	IssueFormatGitHubJSON        = "github-json"
+	IssueFormatJiraCSV           = "jira-csv"
+	IssueFormatMarkdownChecklist = "markdown-checklist"
+)
+
+// issueTaskLabelPrefix marks the label carrying the opusflow task ID
+const issueTaskLabelPrefix = "opusflow-task:"
+
+// issueSkippedLabel marks issues of skipped tasks, which are closed as not planned
+const issueSkippedLabel = "opusflow-status:skipped"
+
+// issueRevisionPrefix records the plan revision the tasks were exported at.
+// Editing a plan's steps renumbers task IDs, so imports refuse other revisions.
+const issueRevisionPrefix = "opusflow-revision:"
+
+var issueRevisionPattern = regexp.MustCompile(`opusflow-revision:(\d+)`)
+
+// GitHubIssue is a single issue in the github-json format.
+// The shape matches `gh issue create` input and `gh issue list --json title,body,labels,state,stateReason` output.
+type GitHubIssue struct {
+	Title       string       `json:"title"`
+	Body        string       `json:"body"`
+	Labels      []IssueLabel `json:"labels"`
+	State       string       `json:"state"`
+	StateReason string       `json:"stateReason,omitempty"` // "not_planned" for skipped tasks
+}
+
+// UnmarshalJSON also accepts the REST API's state_reason field
+func (gi *GitHubIssue) UnmarshalJSON(data []byte) error {
+	type issue GitHubIssue
+	var raw struct {
+		issue
+		RESTStateReason string `json:"state_reason"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*gi = GitHubIssue(raw.issue)
+	if gi.StateReason == "" {
+		gi.StateReason = raw.RESTStateReason
+	}
+	return nil
+}
+
+// IssueLabel is a GitHub label, accepted either as a plain string or as {"name": "..."}
+type IssueLabel string
+
+// UnmarshalJSON accepts both label representations
+func (l *IssueLabel) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err == nil {
+		*l = IssueLabel(name)
+		return nil
+	}
+
+	var obj struct {
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return fmt.Errorf("invalid label: %s", string(data))
+	}
+	*l = IssueLabel(obj.Name)
+	return nil
+}
+
+// TaskStatusChange records a status update applied during import
+type TaskStatusChange struct {
+	TaskID string `json:"task_id"`
+	From   string `json:"from"`
+	To     string `json:"to"`
+}
+
+// jiraCSVHeader lists the columns of the jira-csv format
+var jiraCSVHeader = []string{"Issue Id", "Summary", "Description", "Issue Type", "Status", "Labels", "Blocked By"}
+
+// ExportFormatFromPath derives an export format from an output file extension
+func ExportFormatFromPath(path string) (string, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return IssueFormatJiraCSV, nil
+	case ".md", ".markdown":
+		return IssueFormatMarkdownChecklist, nil
+	default:
+		return TaskFileFormatFromPath(path)
+	}
+}
+
+// ExportTasks encodes a task queue as a structured task file or an issue-tracker format
+func ExportTasks(tq *TaskQueue, format string) ([]byte, error) {
+	switch format {
+	case TaskFileFormatYAML, TaskFileFormatJSON:
+		return NewTaskFile(tq).Marshal(format)
+	case IssueFormatGitHubJSON:
+		return exportGitHubJSON(tq)
+	case IssueFormatJiraCSV:
+		return exportJiraCSV(tq)
+	case IssueFormatMarkdownChecklist:
+		return exportMarkdownChecklist(tq), nil
+	default:
+		return nil, fmt.Errorf("unsupported export format: %s (valid: yaml, json, %s, %s, %s)",
+			format, IssueFormatGitHubJSON, IssueFormatJiraCSV, IssueFormatMarkdownChecklist)
+	}
+}
+
+func exportGitHubJSON(tq *TaskQueue) ([]byte, error) {
+	planLabel := "opusflow-plan:" + strings.TrimSuffix(tq.PlanRef, filepath.Ext(tq.PlanRef))
+
+	issues := make([]GitHubIssue, 0, len(tq.Tasks))
+	for _, t := range tq.Tasks {
+		labels := []IssueLabel{"opusflow", IssueLabel(planLabel), IssueLabel(issueTaskLabelPrefix + t.ID)}
+		if tq.PlanRevision > 0 {
+			labels = append(labels, IssueLabel(fmt.Sprintf("%s%d", issueRevisionPrefix, tq.PlanRevision)))
+		}
+		for _, dep := range t.Dependencies {
+			labels = append(labels, IssueLabel("depends-on:"+dep))
+		}
+
+		state, reason := "open", ""
+		switch t.Status {
+		case TaskStatusDone:
+			state = "closed"
+		case TaskStatusSkipped:
+			state, reason = "closed", "not_planned"
+			labels = append(labels, issueSkippedLabel)
+		}
+
+		issues = append(issues, GitHubIssue{
+			Title:       fmt.Sprintf("[%s] %s", t.ID, t.Title),
+			Body:        formatIssueBody(tq.PlanRef, &t),
+			Labels:      labels,
+			State:       state,
+			StateReason: reason,
+		})
+	}
+
+	data, err := json.MarshalIndent(issues, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode JSON: %w", err)
+	}
+	return append(data, '\n'), nil
+}
+
+func exportJiraCSV(tq *TaskQueue) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+
+	if err := w.Write(jiraCSVHeader); err != nil {
+		return nil, err
+	}
+
+	labels := "opusflow opusflow-" + strings.TrimSuffix(tq.PlanRef, filepath.Ext(tq.PlanRef))
+	if tq.PlanRevision > 0 {
+		labels += fmt.Sprintf(" %s%d", issueRevisionPrefix, tq.PlanRevision)
+	}
+	for _, t := range tq.Tasks {
+		// Jira replaces the Issue Id with its own on export, so the summary and
+		// a label carry the task ID back
+		record := []string{
+			t.ID,
+			fmt.Sprintf("[%s] %s", t.ID, t.Title),
+			formatIssueBody(tq.PlanRef, &t),
+			"Task",
+			jiraStatus(t.Status),
+			labels + " " + issueTaskLabelPrefix + t.ID,
+			strings.Join(t.Dependencies, ";"),
+		}
+		if err := w.Write(record); err != nil {
+			return nil, err
+		}
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, fmt.Errorf("failed to encode CSV: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func exportMarkdownChecklist(tq *TaskQueue) []byte {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("# Tasks: %s\n\n", tq.PlanRef))
+	if tq.PlanRevision > 0 {
+		sb.WriteString(fmt.Sprintf("<!-- %s%d -->\n\n", issueRevisionPrefix, tq.PlanRevision))
+	}
+	for _, t := range tq.Tasks {
+		check := " "
+		if t.Status == TaskStatusDone {
+			check = "x"
+		}
+		sb.WriteString(fmt.Sprintf("- [%s] **%s**: %s", check, t.ID, t.Title))
+		if len(t.Dependencies) > 0 {
+			sb.WriteString(fmt.Sprintf(" _(depends on %s)_", strings.Join(t.Dependencies, ", ")))
+		}
+		sb.WriteString("\n")
+	}
+
+	return []byte(sb.String())
+}
+
+// formatIssueBody renders the task details shared by the issue formats
+func formatIssueBody(planRef string, t *Task) string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("**Plan**: %s\n**Task ID**: %s\n\n", planRef, t.ID))
+	if t.Description != "" {
+		sb.WriteString(t.Description)
+		sb.WriteString("\n\n")
+	}
+	if len(t.Files) > 0 {
+		sb.WriteString("**Files**:\n")
+		for _, f := range t.Files {
+			sb.WriteString(fmt.Sprintf("- `%s`\n", f))
+		}
+		sb.WriteString("\n")
+	}
+	if len(t.Dependencies) > 0 {
+		sb.WriteString(fmt.Sprintf("**Depends on**: %s\n\n", strings.Join(t.Dependencies, ", ")))
+	}
+	if len(t.Verification) > 0 {
+		sb.WriteString("**Verification**:\n")
+		for _, v := range t.Verification {
+			sb.WriteString(fmt.Sprintf("- %s\n", v))
+		}
+	}
+
+	return strings.TrimSpace(sb.String())
+}
+
+// jiraStatus maps a task status to a Jira workflow status
+func jiraStatus(status string) string {
+	switch status {
+	case TaskStatusInProgress:
+		return "In Progress"
+	case TaskStatusDone:
+		return "Done"
+	case TaskStatusFailed:
+		return "Failed"
+	case TaskStatusSkipped:
+		return "Won't Do"
+	default:
+		return "To Do"
+	}
+}
+
+// taskStatusFromJira maps a Jira workflow status back to a task status
+func taskStatusFromJira(status string) (string, bool) {
+	switch strings.ToLower(strings.TrimSpace(status)) {
+	case "to do", "open", "backlog", "selected for development", "reopened":
+		return TaskStatusPending, true
+	case "in progress", "in review":
+		return TaskStatusInProgress, true
+	case "done", "closed", "resolved":
+		return TaskStatusDone, true
+	case "failed":
+		return TaskStatusFailed, true
+	case "won't do", "wont do", "cancelled", "canceled":
+		return TaskStatusSkipped, true
+	default:
+		return "", false
+	}
+}
+
+// ImportIssueStatuses applies status changes from an exported issue file back to the queue.
+// Closed or checked issues mark their task done, or skipped when closed as not planned;
+// reopened issues move done tasks back to pending. Files exported at another plan
+// revision are rejected, since task IDs may have been renumbered since.
+func ImportIssueStatuses(tq *TaskQueue, data []byte, format string) ([]TaskStatusChange, error) {
+	var statuses map[string]string
+	var err error
+
+	switch format {
+	case IssueFormatGitHubJSON:
+		statuses, err = parseGitHubStatuses(data)
+	case IssueFormatJiraCSV:
+		statuses, err = parseJiraStatuses(data)
+	case IssueFormatMarkdownChecklist:
+		statuses = parseChecklistStatuses(data)
+	default:
+		return nil, fmt.Errorf("unsupported import format: %s (valid: %s, %s, %s)",
+			format, IssueFormatGitHubJSON, IssueFormatJiraCSV, IssueFormatMarkdownChecklist)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if m := issueRevisionPattern.FindSubmatch(data); m != nil {
+		if rev, _ := strconv.Atoi(string(m[1])); rev != tq.PlanRevision {
+			return nil, fmt.Errorf("issues were exported at plan revision %d but the task queue is at revision %d; task IDs may have changed, so export the tasks again", rev, tq.PlanRevision)
+		}
+	}
+
+	var changes []TaskStatusChange
+	for i := range tq.Tasks {
+		task := &tq.Tasks[i]
+		status, ok := statuses[task.ID]
+		if !ok || status == task.Status {
+			continue
+		}
+		// An open issue only reopens tasks that were done; it does not reset progress
+		if status == TaskStatusPending && task.Status != TaskStatusDone {
+			continue
+		}
+
+		changes = append(changes, TaskStatusChange{TaskID: task.ID, From: task.Status, To: status})
+		task.Status = status
+	}
+
+	if len(changes) > 0 {
+		tq.recountCompleted()
+		tq.UpdatedAt = time.Now()
+	}
+
+	return changes, nil
+}
+
+var issueTitleIDPattern = regexp.MustCompile(`^\[([^\]]+)\]`)
+
+func parseGitHubStatuses(data []byte) (map[string]string, error) {
+	var issues []GitHubIssue
+	if err := json.Unmarshal(data, &issues); err != nil {
+		return nil, fmt.Errorf("failed to parse GitHub issues: %w", err)
+	}
+
+	statuses := make(map[string]string, len(issues))
+	for _, issue := range issues {
+		id, skipped := "", strings.EqualFold(issue.StateReason, "not_planned")
+		for _, l := range issue.Labels {
+			if strings.HasPrefix(string(l), issueTaskLabelPrefix) && id == "" {
+				id = strings.TrimPrefix(string(l), issueTaskLabelPrefix)
+			}
+			if string(l) == issueSkippedLabel {
+				skipped = true
+			}
+		}
+		if id == "" {
+			if m := issueTitleIDPattern.FindStringSubmatch(issue.Title); m != nil {
+				id = m[1]
+			}
+		}
+		if id == "" {
+			continue
+		}
+
+		switch {
+		case strings.EqualFold(issue.State, "closed") && skipped:
+			statuses[id] = TaskStatusSkipped
+		case strings.EqualFold(issue.State, "closed"):
+			statuses[id] = TaskStatusDone
+		default:
+			statuses[id] = TaskStatusPending
+		}
+	}
+
+	return statuses, nil
+}
+
+// parseJiraStatuses reads statuses from a Jira CSV export. Jira writes one
+// Labels column per label and its own issue ids, so the task ID comes from an
+// opusflow-task label or the summary, and only then from the Issue Id column.
+func parseJiraStatuses(data []byte) (map[string]string, error) {
+	r := csv.NewReader(bytes.NewReader(data))
+	r.FieldsPerRecord = -1
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse Jira CSV: %w", err)
+	}
+	if len(records) == 0 {
+		return map[string]string{}, nil
+	}
+
+	idCol, summaryCol, statusCol := -1, -1, -1
+	var labelCols []int
+	for i, h := range records[0] {
+		switch strings.ToLower(strings.TrimSpace(h)) {
+		case "issue id":
+			idCol = i
+		case "summary":
+			summaryCol = i
+		case "status":
+			statusCol = i
+		case "labels":
+			labelCols = append(labelCols, i)
+		}
+	}
+	if statusCol < 0 || (idCol < 0 && summaryCol < 0 && len(labelCols) == 0) {
+		return nil, fmt.Errorf("jira CSV must have a 'Status' column and a 'Summary', 'Labels' or 'Issue Id' column")
+	}
+
+	field := func(rec []string, col int) string {
+		if col < 0 || col >= len(rec) {
+			return ""
+		}
+		return strings.TrimSpace(rec[col])
+	}
+
+	statuses := make(map[string]string, len(records)-1)
+	for _, rec := range records[1:] {
+		status, ok := taskStatusFromJira(field(rec, statusCol))
+		if !ok {
+			continue
+		}
+
+		id := ""
+		for _, col := range labelCols {
+			for _, l := range strings.Fields(field(rec, col)) {
+				if strings.HasPrefix(l, issueTaskLabelPrefix) && id == "" {
+					id = strings.TrimPrefix(l, issueTaskLabelPrefix)
+				}
+			}
+		}
+		if id == "" {
+			if m := issueTitleIDPattern.FindStringSubmatch(field(rec, summaryCol)); m != nil {
+				id = m[1]
+			}
+		}
+		if id == "" {
+			id = field(rec, idCol)
+		}
+		if id != "" {
+			statuses[id] = status
+		}
+	}
+
+	return statuses, nil
+}
+
+var checklistItemPattern = regexp.MustCompile(`^\s*[-*]\s+\[([ xX])\]\s+\*\*([^*]+)\*\*`)
+
+func parseChecklistStatuses(data []byte) map[string]string {
+	statuses := make(map[string]string)
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		m := checklistItemPattern.FindStringSubmatch(scanner.Text())
+		if m == nil {
+			continue
+		}
+		if m[1] == " " {
+			statuses[m[2]] = TaskStatusPending
+		} else {
+			statuses[m[2]] = TaskStatusDone
+		}
+	}
+
+	return statuses
+}
+
+// recountCompleted recomputes CompletedSteps from task statuses
+func (tq *TaskQueue) recountCompleted() {
+	done := 0
+	for _, t := range tq.Tasks {
+		if t.Status == TaskStatusDone {
+			done++
+		}
+	}
+	tq.CompletedSteps = done
+}
@@ -0,0 +1,269 @@
+package ops
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func sampleIssueQueue() *TaskQueue {
+	return &TaskQueue{
+		PlanRef: "plan-01-auth.md",
+		Tasks: []Task{
+			{ID: "task-1", Title: "Setup", Status: TaskStatusDone, Files: []string{"cmd/main.go"}},
+			{ID: "task-2", Title: "Login", Status: TaskStatusPending, Dependencies: []string{"task-1"}},
+			{ID: "task-3", Title: "Logout", Status: TaskStatusInProgress, Dependencies: []string{"task-2"}},
+		},
+		TotalSteps:     3,
+		CompletedSteps: 1,
+	}
+}
+
+func TestExportTasks_GitHubJSON(t *testing.T) {
+	data, err := ExportTasks(sampleIssueQueue(), IssueFormatGitHubJSON)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var issues []GitHubIssue
+	if err := json.Unmarshal(data, &issues); err != nil {
+		t.Fatalf("Invalid JSON: %v", err)
+	}
+	if len(issues) != 3 {
+		t.Fatalf("Expected 3 issues, got %d", len(issues))
+	}
+	if issues[0].State != "closed" || issues[1].State != "open" {
+		t.Errorf("Unexpected states: %s, %s", issues[0].State, issues[1].State)
+	}
+
+	hasDepLabel := false
+	for _, l := range issues[1].Labels {
+		if l == "depends-on:task-1" {
+			hasDepLabel = true
+		}
+	}
+	if !hasDepLabel {
+		t.Errorf("Expected dependency label, got %v", issues[1].Labels)
+	}
+}
+
+func TestExportTasks_JiraCSV(t *testing.T) {
+	data, err := ExportTasks(sampleIssueQueue(), IssueFormatJiraCSV)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	out := string(data)
+	if !strings.HasPrefix(out, "Issue Id,Summary,Description,Issue Type,Status,Labels,Blocked By") {
+		t.Errorf("Expected Jira header, got: %s", out)
+	}
+	if !strings.Contains(out, "In Progress") || !strings.Contains(out, "task-2") {
+		t.Errorf("Expected statuses and links in CSV, got: %s", out)
+	}
+}
+
+func TestExportTasks_MarkdownChecklist(t *testing.T) {
+	data, err := ExportTasks(sampleIssueQueue(), IssueFormatMarkdownChecklist)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	out := string(data)
+	if !strings.Contains(out, "- [x] **task-1**: Setup") {
+		t.Errorf("Expected checked item, got: %s", out)
+	}
+	if !strings.Contains(out, "- [ ] **task-2**: Login _(depends on task-1)_") {
+		t.Errorf("Expected unchecked item with dependency, got: %s", out)
+	}
+}
+
+func TestExportTasks_UnknownFormat(t *testing.T) {
+	if _, err := ExportTasks(sampleIssueQueue(), "xml"); err == nil {
+		t.Error("Expected error for unknown format")
+	}
+}
+
+func TestImportIssueStatuses_GitHub(t *testing.T) {
+	// gh CLI output uses label objects and upper-case states
+	data := `[
+		{"title": "[task-2] Login", "labels": [{"name": "opusflow-task:task-2"}], "state": "CLOSED"},
+		{"title": "[task-1] Setup", "labels": ["opusflow"], "state": "OPEN"},
+		{"title": "Unrelated", "labels": [], "state": "CLOSED"}
+	]`
+
+	tq := sampleIssueQueue()
+	changes, err := ImportIssueStatuses(tq, []byte(data), IssueFormatGitHubJSON)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(changes) != 2 {
+		t.Fatalf("Expected 2 changes, got %v", changes)
+	}
+	if tq.Tasks[1].Status != TaskStatusDone {
+		t.Errorf("Expected task-2 done, got %s", tq.Tasks[1].Status)
+	}
+	if tq.Tasks[0].Status != TaskStatusPending {
+		t.Errorf("Expected reopened task-1 pending, got %s", tq.Tasks[0].Status)
+	}
+	if tq.CompletedSteps != 1 {
+		t.Errorf("Expected 1 completed step, got %d", tq.CompletedSteps)
+	}
+}
+
+func TestImportIssueStatuses_RoundTrip(t *testing.T) {
+	for _, format := range []string{IssueFormatJiraCSV, IssueFormatMarkdownChecklist} {
+		t.Run(format, func(t *testing.T) {
+			data, err := ExportTasks(sampleIssueQueue(), format)
+			if err != nil {
+				t.Fatalf("Export failed: %v", err)
+			}
+
+			// Close task-3 in the exported file
+			edited := string(data)
+			if format == IssueFormatJiraCSV {
+				edited = strings.Replace(edited, "In Progress", "Done", 1)
+			} else {
+				edited = strings.Replace(edited, "- [ ] **task-3**", "- [x] **task-3**", 1)
+			}
+
+			tq := sampleIssueQueue()
+			changes, err := ImportIssueStatuses(tq, []byte(edited), format)
+			if err != nil {
+				t.Fatalf("Import failed: %v", err)
+			}
+
+			if len(changes) != 1 || changes[0].TaskID != "task-3" || changes[0].To != TaskStatusDone {
+				t.Errorf("Expected task-3 to be marked done, got %v", changes)
+			}
+			if tq.Tasks[1].Status != TaskStatusPending {
+				t.Errorf("Expected untouched task-2 to stay pending, got %s", tq.Tasks[1].Status)
+			}
+		})
+	}
+}
+
+func TestImportIssueStatuses_UnmodifiedExport(t *testing.T) {
+	queue := func() *TaskQueue {
+		tq := sampleIssueQueue()
+		tq.Tasks = append(tq.Tasks, Task{ID: "task-4", Title: "Audit", Status: TaskStatusSkipped})
+		tq.TotalSteps = 4
+		return tq
+	}
+
+	for _, format := range []string{IssueFormatGitHubJSON, IssueFormatJiraCSV, IssueFormatMarkdownChecklist} {
+		t.Run(format, func(t *testing.T) {
+			data, err := ExportTasks(queue(), format)
+			if err != nil {
+				t.Fatalf("Export failed: %v", err)
+			}
+
+			tq := queue()
+			changes, err := ImportIssueStatuses(tq, data, format)
+			if err != nil {
+				t.Fatalf("Import failed: %v", err)
+			}
+			if len(changes) != 0 {
+				t.Errorf("Expected no changes from an unmodified export, got %v", changes)
+			}
+			if tq.Tasks[3].Status != TaskStatusSkipped || tq.CompletedSteps != 1 {
+				t.Errorf("Expected task-4 to stay skipped with 1 completed step, got %s and %d", tq.Tasks[3].Status, tq.CompletedSteps)
+			}
+		})
+	}
+}
+
+func TestImportIssueStatuses_GitHubNotPlanned(t *testing.T) {
+	// Output of gh issue list --json title,labels,state,stateReason; issues
+	// closed as not planned in GitHub and skipped at export are both skipped
+	data := `[
+		{"labels":[{"id":"LA_1","name":"opusflow-task:task-2","description":"","color":"ededed"}],"state":"CLOSED","stateReason":"NOT_PLANNED","title":"[task-2] Login"},
+		{"labels":[{"id":"LA_2","name":"opusflow-task:task-3","description":"","color":"ededed"},{"id":"LA_3","name":"opusflow-status:skipped","description":"","color":"ededed"}],"state":"CLOSED","stateReason":"","title":"[task-3] Logout"},
+		{"labels":[{"id":"LA_4","name":"opusflow-task:task-1","description":"","color":"ededed"}],"state":"CLOSED","stateReason":"COMPLETED","title":"[task-1] Setup"}
+	]`
+
+	tq := sampleIssueQueue()
+	if _, err := ImportIssueStatuses(tq, []byte(data), IssueFormatGitHubJSON); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if tq.Tasks[1].Status != TaskStatusSkipped || tq.Tasks[2].Status != TaskStatusSkipped {
+		t.Errorf("Expected task-2 and task-3 skipped, got %s and %s", tq.Tasks[1].Status, tq.Tasks[2].Status)
+	}
+	if tq.Tasks[0].Status != TaskStatusDone || tq.CompletedSteps != 1 {
+		t.Errorf("Expected task-1 done and 1 completed step, got %s and %d", tq.Tasks[0].Status, tq.CompletedSteps)
+	}
+
+	// The REST API spells the field state_reason
+	rest := `[{"title": "[task-2] Login", "labels": [], "state": "closed", "state_reason": "not_planned"}]`
+	tq = sampleIssueQueue()
+	if _, err := ImportIssueStatuses(tq, []byte(rest), IssueFormatGitHubJSON); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if tq.Tasks[1].Status != TaskStatusSkipped {
+		t.Errorf("Expected task-2 skipped, got %s", tq.Tasks[1].Status)
+	}
+}
+
+func TestImportIssueStatuses_JiraExport(t *testing.T) {
+	// Jira's own export: its issue ids, one Labels column per label
+	data := "Summary,Issue key,Issue id,Issue Type,Status,Labels,Labels,Labels\n" +
+		"[task-3] Logout,AUTH-3,10042,Task,Done,opusflow,opusflow-plan-01-auth,opusflow-task:task-3\n" +
+		"[task-2] Login,AUTH-2,10041,Task,Won't Do,opusflow,,\n" +
+		"Unrelated,AUTH-9,10050,Task,Done,,,\n"
+
+	tq := sampleIssueQueue()
+	changes, err := ImportIssueStatuses(tq, []byte(data), IssueFormatJiraCSV)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(changes) != 2 || tq.Tasks[2].Status != TaskStatusDone || tq.Tasks[1].Status != TaskStatusSkipped {
+		t.Errorf("Expected task-3 done and task-2 skipped, got %v", changes)
+	}
+}
+
+func TestImportIssueStatuses_PlanRevision(t *testing.T) {
+	for _, format := range []string{IssueFormatGitHubJSON, IssueFormatJiraCSV, IssueFormatMarkdownChecklist} {
+		t.Run(format, func(t *testing.T) {
+			exported := sampleIssueQueue()
+			exported.PlanRevision = 2
+			data, err := ExportTasks(exported, format)
+			if err != nil {
+				t.Fatalf("Export failed: %v", err)
+			}
+			if !strings.Contains(string(data), "opusflow-revision:2") {
+				t.Errorf("Expected the export to record the plan revision, got:\n%s", data)
+			}
+
+			// Steps were added or moved since the export, renumbering the tasks
+			tq := sampleIssueQueue()
+			tq.PlanRevision = 3
+			if _, err := ImportIssueStatuses(tq, data, format); err == nil || !strings.Contains(err.Error(), "exported at plan revision 2") {
+				t.Errorf("Expected a revision mismatch error, got %v", err)
+			}
+
+			tq.PlanRevision = 2
+			if _, err := ImportIssueStatuses(tq, data, format); err != nil {
+				t.Errorf("Expected import at the same revision to succeed, got %v", err)
+			}
+		})
+	}
+}
+
+func TestExportFormatFromPath(t *testing.T) {
+	tests := map[string]string{
+		"issues.csv":   IssueFormatJiraCSV,
+		"checklist.md": IssueFormatMarkdownChecklist,
+		"tasks.yaml":   TaskFileFormatYAML,
+		"tasks.json":   TaskFileFormatJSON,
+	}
+
+	for path, want := range tests {
+		got, err := ExportFormatFromPath(path)
+		if err != nil {
+			t.Errorf("ExportFormatFromPath(%q) unexpected error: %v", path, err)
+		}
+		if got != want {
+			t.Errorf("ExportFormatFromPath(%q) = %q; want %q", path, got, want)
+		}
+	}
+}
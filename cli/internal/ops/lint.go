@@ -0,0 +1,301 @@
+package ops
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/tuanpep/oplusflow/internal/manager"
+	"github.com/tuanpep/oplusflow/internal/plan"
+	"github.com/tuanpep/oplusflow/internal/templates"
+)
+
+// LintSeverity constants
+const (
+	LintError   = "error"
+	LintWarning = "warning"
+)
+
+// Lint rule names
+const (
+	RuleParse               = "parse"
+	RulePlaceholder         = "placeholder"
+	RuleTBD                 = "tbd"
+	RuleNoSteps             = "no-steps"
+	RuleStepMissingFile     = "step-missing-file"
+	RuleStepMissingAction   = "step-missing-action"
+	RuleStepMissingVerify   = "step-missing-verification"
+	RuleDuplicateStep       = "duplicate-step"
+	RuleSharedFile          = "shared-file"
+	RuleMissingModifyTarget = "missing-target"
+	RuleMissingGoal         = "missing-goal"
+)
+
+// LintIssue is a single problem found in a plan document
+type LintIssue struct {
+	Line     int    `json:"line"`
+	Severity string `json:"severity"`
+	Rule     string `json:"rule"`
+	Message  string `json:"message"`
+}
+
+// LintResult contains every issue found in a plan
+type LintResult struct {
+	PlanPath string      `json:"plan_path"`
+	Issues   []LintIssue `json:"issues"`
+	Errors   int         `json:"errors"`
+	Warnings int         `json:"warnings"`
+}
+
+var placeholderPattern = regexp.MustCompile(`\[[^\]\n]+\]`)
+
+// templatePlaceholders returns the bracketed placeholders used by the built-in plan templates
+func templatePlaceholders() map[string]bool {
+	placeholders := make(map[string]bool)
+	for _, content := range templates.PlanTemplates {
+		for _, m := range placeholderPattern.FindAllString(content, -1) {
+			// Admonitions, checkboxes and template actions are not placeholders
+			if strings.HasPrefix(m, "[!") || m == "[ ]" || strings.Contains(m, "{{") {
+				continue
+			}
+			placeholders[m] = true
+		}
+	}
+	placeholders["(Repeat for other steps)"] = true
+	return placeholders
+}
+
+// LintPlanFile lints a plan file located with ResolvePlanPath
+func LintPlanFile(ref string) (*LintResult, error) {
+	path, err := ResolvePlanPath(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read plan: %w", err)
+	}
+
+	root, err := manager.FindProjectRoot()
+	if err != nil {
+		return nil, fmt.Errorf("failed to find project root: %w", err)
+	}
+
+	result := LintPlanContent(string(content), root)
+	result.PlanPath = path
+	return result, nil
+}
+
+// LintPlanContent lints plan markdown. File paths are resolved relative to root.
+func LintPlanContent(content, root string) *LintResult {
+	result := &LintResult{Issues: []LintIssue{}}
+
+	p, err := plan.Parse(content)
+	var parseErrs plan.ParseErrors
+	if errors.As(err, &parseErrs) {
+		for _, e := range parseErrs {
+			result.add(e.Line, LintError, RuleParse, e.Msg)
+		}
+	}
+
+	lintPlaceholders(result, content)
+	lintSections(result, p)
+	lintSteps(result, p)
+	lintTargets(result, p, root)
+
+	sort.SliceStable(result.Issues, func(i, j int) bool {
+		return result.Issues[i].Line < result.Issues[j].Line
+	})
+
+	return result
+}
+
+func (r *LintResult) add(line int, severity, rule, msg string) {
+	r.Issues = append(r.Issues, LintIssue{Line: line, Severity: severity, Rule: rule, Message: msg})
+	if severity == LintError {
+		r.Errors++
+	} else {
+		r.Warnings++
+	}
+}
+
+// lintPlaceholders reports unfilled template placeholders outside code fences
+func lintPlaceholders(r *LintResult, content string) {
+	placeholders := templatePlaceholders()
+
+	fence := ""
+	for i, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if fence != "" {
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+			continue
+		}
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			fence = trimmed[:3]
+			continue
+		}
+
+		for _, m := range placeholderPattern.FindAllString(line, -1) {
+			if placeholders[m] {
+				r.add(i+1, LintError, RulePlaceholder, fmt.Sprintf("unfilled template placeholder %q", m))
+			}
+		}
+		if strings.Contains(line, "(Repeat for other steps)") {
+			r.add(i+1, LintError, RulePlaceholder, `unfilled template placeholder "(Repeat for other steps)"`)
+		}
+	}
+}
+
+// lintSections checks the goal and pre-requisites
+func lintSections(r *LintResult, p *plan.Plan) {
+	if s := p.Section(plan.SectionGoal); s == nil || p.Goal == "" {
+		line := 1
+		if s != nil {
+			line = s.Line
+		}
+		r.add(line, LintWarning, RuleMissingGoal, "plan has no goal")
+	}
+
+	for _, f := range p.Prerequisites {
+		if strings.EqualFold(strings.TrimSpace(f.Value), "TBD") {
+			r.add(f.Line, LintError, RuleTBD, fmt.Sprintf("pre-requisite %q is still TBD", f.Key))
+		}
+	}
+}
+
+// lintSteps checks each implementation step and cross-step consistency
+func lintSteps(r *LintResult, p *plan.Plan) {
+	if len(p.Steps) == 0 {
+		r.add(1, LintError, RuleNoSteps, "plan has no implementation steps (### Step N: Title)")
+		return
+	}
+
+	seenNumbers := make(map[int]int)
+	fileSteps := make(map[string][]int)
+	var fileOrder []string
+
+	for _, st := range p.Steps {
+		if first, ok := seenNumbers[st.Number]; ok {
+			r.add(st.Line, LintError, RuleDuplicateStep, fmt.Sprintf("duplicate step number %d (first used on line %d)", st.Number, first))
+		} else {
+			seenNumbers[st.Number] = st.Line
+		}
+
+		if len(st.Files) == 0 {
+			r.add(st.Line, LintError, RuleStepMissingFile, fmt.Sprintf("step %d has no **File**", st.Number))
+		}
+		if len(st.Actions) == 0 {
+			r.add(st.Line, LintError, RuleStepMissingAction, fmt.Sprintf("step %d has no **Action**", st.Number))
+		}
+		if len(st.Verification) == 0 {
+			r.add(st.Line, LintError, RuleStepMissingVerify, fmt.Sprintf("step %d has no **Verification**", st.Number))
+		}
+
+		for _, f := range st.Files {
+			if _, ok := fileSteps[f]; !ok {
+				fileOrder = append(fileOrder, f)
+			}
+			fileSteps[f] = append(fileSteps[f], st.Number)
+		}
+	}
+
+	for _, f := range fileOrder {
+		steps := fileSteps[f]
+		if len(steps) < 2 {
+			continue
+		}
+		nums := make([]string, len(steps))
+		for i, n := range steps {
+			nums[i] = fmt.Sprintf("%d", n)
+		}
+		line := p.Step(steps[1]).Line
+		r.add(line, LintWarning, RuleSharedFile, fmt.Sprintf("file %s is referenced by multiple steps (%s)", f, strings.Join(nums, ", ")))
+	}
+}
+
+// lintTargets checks that files to be modified already exist
+func lintTargets(r *LintResult, p *plan.Plan, root string) {
+	placeholders := templatePlaceholders()
+	checked := make(map[string]bool)
+
+	check := func(path string, line int) {
+		if path == "" || placeholders[path] || checked[path] {
+			return
+		}
+		checked[path] = true
+
+		target := path
+		if !filepath.IsAbs(target) {
+			target = filepath.Join(root, target)
+		}
+		if _, err := os.Stat(target); os.IsNotExist(err) {
+			r.add(line, LintError, RuleMissingModifyTarget, fmt.Sprintf("MODIFY target %s does not exist", path))
+		}
+	}
+
+	for _, c := range p.Changes {
+		if c.Action == plan.ActionModify {
+			check(c.File, c.Line)
+		}
+	}
+
+	for _, st := range p.Steps {
+		modifies := false
+		for _, a := range st.Actions {
+			switch strings.ToLower(a) {
+			case "update", "modify", "edit":
+				modifies = true
+			}
+		}
+		if !modifies {
+			continue
+		}
+		for _, f := range st.Files {
+			check(f, st.Line)
+		}
+	}
+}
+
+// HasErrors reports whether any error-level issue was found
+func (r *LintResult) HasErrors() bool {
+	return r.Errors > 0
+}
+
+// FormatText returns compiler-style, human-readable lint output
+func (r *LintResult) FormatText() string {
+	var sb strings.Builder
+
+	name := r.PlanPath
+	if name == "" {
+		name = "plan"
+	}
+
+	for _, issue := range r.Issues {
+		sb.WriteString(fmt.Sprintf("%s:%d: %s [%s] %s\n", name, issue.Line, issue.Severity, issue.Rule, issue.Message))
+	}
+
+	if len(r.Issues) == 0 {
+		sb.WriteString(fmt.Sprintf("✅ %s: no issues found\n", filepath.Base(name)))
+	} else {
+		sb.WriteString(fmt.Sprintf("\n%d error(s), %d warning(s)\n", r.Errors, r.Warnings))
+	}
+
+	return sb.String()
+}
+
+// FormatJSON returns the lint result as indented JSON
+func (r *LintResult) FormatJSON() (string, error) {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
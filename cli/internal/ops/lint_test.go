@@ -0,0 +1,137 @@
+package ops
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/tuanpep/oplusflow/internal/templates"
+)
+
+func lintRules(r *LintResult) map[string]int {
+	rules := make(map[string]int)
+	for _, issue := range r.Issues {
+		rules[issue.Rule]++
+	}
+	return rules
+}
+
+func TestLintPlanContent_Template(t *testing.T) {
+	tmpl := template.Must(template.New("plan").Funcs(planTemplateFuncs).Parse(templates.PlanTemplate))
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, PlanTemplateData{
+		Title: "Login", Goal: "Add login", Dependencies: "TBD", Context: "TBD", Environment: "TBD",
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	r := LintPlanContent(buf.String(), t.TempDir())
+	rules := lintRules(r)
+
+	if !r.HasErrors() {
+		t.Fatal("Expected a fresh template to fail lint")
+	}
+	if rules[RulePlaceholder] < 5 {
+		t.Errorf("Expected placeholder issues, got %v", r.Issues)
+	}
+	if rules[RuleTBD] != 3 {
+		t.Errorf("Expected 3 TBD pre-requisites, got %d", rules[RuleTBD])
+	}
+	if rules[RuleStepMissingAction] != 1 {
+		t.Errorf("Expected the placeholder action to be reported, got %v", rules)
+	}
+	if rules[RuleMissingModifyTarget] != 0 {
+		t.Errorf("Placeholders should not be checked on disk, got %v", r.Issues)
+	}
+
+	for _, issue := range r.Issues {
+		if issue.Rule == RulePlaceholder && strings.Contains(issue.Message, "[!IMPORTANT]") {
+			t.Error("Admonitions must not be reported as placeholders")
+		}
+	}
+}
+
+func TestLintPlanContent_Steps(t *testing.T) {
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "exists.go"), []byte("package main\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	content := "## Goal\nShip it\n\n" +
+		"## Proposed Changes\n" +
+		"#### [MODIFY] `missing.go`\n" +
+		"#### [MODIFY] `exists.go`\n\n" +
+		"## Implementation Steps\n" +
+		"### Step 1: First\n" +
+		"**File**: `exists.go`\n" +
+		"**Action**: Update\n" +
+		"**Verification**:\n- `go build ./...`\n\n" +
+		"### Step 1: Duplicate\n" +
+		"**File**: `exists.go`\n\n" +
+		"### Step 2: Complete\n" +
+		"**File**: `new.go`\n" +
+		"**Action**: Create\n" +
+		"**Verification**:\n- `go test ./...`\n"
+
+	r := LintPlanContent(content, root)
+	rules := lintRules(r)
+
+	want := map[string]int{
+		RuleMissingModifyTarget: 1,
+		RuleDuplicateStep:       1,
+		RuleStepMissingAction:   1,
+		RuleStepMissingVerify:   1,
+		RuleSharedFile:          1,
+	}
+	for rule, n := range want {
+		if rules[rule] != n {
+			t.Errorf("Expected %d %s issue(s), got %d: %v", n, rule, rules[rule], r.Issues)
+		}
+	}
+	if rules[RuleStepMissingFile] != 0 || rules[RuleMissingGoal] != 0 {
+		t.Errorf("Unexpected issues: %v", r.Issues)
+	}
+
+	for i := 1; i < len(r.Issues); i++ {
+		if r.Issues[i].Line < r.Issues[i-1].Line {
+			t.Errorf("Issues not sorted by line: %v", r.Issues)
+		}
+	}
+	if r.Warnings != 1 || r.Errors != 4 {
+		t.Errorf("Expected 4 errors and 1 warning, got %d/%d", r.Errors, r.Warnings)
+	}
+}
+
+func TestLintPlanContent_Clean(t *testing.T) {
+	content := "## Goal\nShip it\n\n## Implementation Steps\n" +
+		"### Step 1: Add file\n**File**: `new.go`\n**Action**: Create\n**Verification**:\n- `go build ./...`\n"
+
+	r := LintPlanContent(content, t.TempDir())
+	if len(r.Issues) != 0 {
+		t.Fatalf("Expected no issues, got %v", r.Issues)
+	}
+	if !strings.Contains(r.FormatText(), "no issues found") {
+		t.Errorf("Unexpected text output: %s", r.FormatText())
+	}
+
+	out, err := r.FormatJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded LintResult
+	if err := json.Unmarshal([]byte(out), &decoded); err != nil || decoded.Issues == nil {
+		t.Errorf("Expected JSON with empty issues list, got %s (%v)", out, err)
+	}
+}
+
+func TestLintPlanContent_NoSteps(t *testing.T) {
+	r := LintPlanContent("# Empty\n", t.TempDir())
+	rules := lintRules(r)
+	if rules[RuleNoSteps] != 1 || rules[RuleMissingGoal] != 1 {
+		t.Errorf("Unexpected issues: %v", r.Issues)
+	}
+}
@@ -0,0 +1,177 @@
+package ops
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/tuanpep/oplusflow/internal/frontmatter"
+	"github.com/tuanpep/oplusflow/internal/manager"
+	"github.com/tuanpep/oplusflow/internal/orchestrator"
+	"github.com/tuanpep/oplusflow/internal/spec"
+)
+
+// Plan statuses recorded in plan frontmatter
+const (
+	PlanStatusDraft      = "Draft"
+	PlanStatusInProgress = "In Progress"
+	PlanStatusDone       = "Done"
+)
+
+// NewDocumentMeta returns fresh metadata for the document at path
+func NewDocumentMeta(path, kind, status string) *frontmatter.Meta {
+	m := &frontmatter.Meta{
+		ID:         strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)),
+		Kind:       kind,
+		Status:     status,
+		WorkflowID: orchestrator.ActiveWorkflowID(),
+	}
+	m.Touch()
+	return m
+}
+
+// documentKind infers the document kind from its filename
+func documentKind(path string) string {
+	base := filepath.Base(path)
+	switch {
+	case strings.HasPrefix(base, "spec-"):
+		return frontmatter.KindSpec
+	case strings.HasPrefix(base, "plan-"):
+		return frontmatter.KindPlan
+	case strings.HasPrefix(base, "verify-"):
+		return frontmatter.KindVerification
+	case strings.HasPrefix(base, "phase-"):
+		return frontmatter.KindPhase
+	}
+	return ""
+}
+
+// ResolveDocumentPath locates a spec, plan or verification report given a path
+// (absolute or relative to the project root) or a bare filename
+func ResolveDocumentPath(ref string) (string, error) {
+	root, err := manager.FindProjectRoot()
+	if err != nil {
+		return "", fmt.Errorf("failed to find project root: %w", err)
+	}
+
+	candidates := []string{ref}
+	if !filepath.IsAbs(ref) {
+		base := filepath.Base(ref)
+		candidates = []string{
+			filepath.Join(root, ref),
+			filepath.Join(manager.PlansDir(root), base),
+			filepath.Join(manager.ArchivedPlansDir(root), base),
+			filepath.Join(manager.SpecsDir(root), base),
+			filepath.Join(manager.PhasesDir(root), base),
+			filepath.Join(root, "opusflow-planning", "verifications", base),
+		}
+	}
+
+	for _, c := range candidates {
+		if info, err := os.Stat(c); err == nil && !info.IsDir() {
+			return c, nil
+		}
+	}
+
+	return "", fmt.Errorf("document not found: %s", ref)
+}
+
+// ReadDocumentMeta returns the frontmatter of the document at path, or nil if it has none
+func ReadDocumentMeta(path string) (*frontmatter.Meta, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	m, _, err := frontmatter.Parse(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filepath.Base(path), err)
+	}
+	return m, nil
+}
+
+// UpdateDocumentMeta applies fn to the frontmatter of the document at path and
+// bumps its updated timestamp. Documents without frontmatter get one.
+func UpdateDocumentMeta(path string, fn func(*frontmatter.Meta)) (*frontmatter.Meta, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	m, body, err := frontmatter.Parse(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filepath.Base(path), err)
+	}
+	if m == nil {
+		m = NewDocumentMeta(path, documentKind(path), "")
+		if m.Kind == frontmatter.KindSpec {
+			// Carry over the status line of specs written before frontmatter existed
+			m.Status = spec.Parse(body).Status
+		}
+	}
+
+	fn(m)
+	m.Touch()
+
+	content, err := m.Render(body)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return nil, fmt.Errorf("failed to write %s: %w", path, err)
+	}
+
+	return m, nil
+}
+
+// syncPlanStatus moves the plan's frontmatter status along with its task
+// queue: In Progress once decomposed, Done when every task is finished.
+// Plans without frontmatter, or with a status set by hand, are left alone.
+func (tq *TaskQueue) syncPlanStatus() error {
+	if tq.PlanPath == "" {
+		return nil
+	}
+
+	// Queues written before PlanPath was stored absolute hold a root-relative path
+	path := tq.PlanPath
+	if !filepath.IsAbs(path) {
+		root, err := manager.FindProjectRoot()
+		if err != nil {
+			return err
+		}
+		path = filepath.Join(root, path)
+	}
+
+	m, err := ReadDocumentMeta(path)
+	if err != nil || m == nil {
+		return err
+	}
+
+	status := PlanStatusInProgress
+	if tq.allTasksFinished() {
+		status = PlanStatusDone
+	}
+
+	switch m.Status {
+	case status:
+		return nil
+	case PlanStatusDraft, PlanStatusInProgress, PlanStatusDone:
+		_, err = UpdateDocumentMeta(path, func(m *frontmatter.Meta) { m.Status = status })
+		return err
+	}
+	return nil
+}
+
+// allTasksFinished reports whether every task is done or skipped
+func (tq *TaskQueue) allTasksFinished() bool {
+	if len(tq.Tasks) == 0 {
+		return false
+	}
+	for _, t := range tq.Tasks {
+		if t.Status != TaskStatusDone && t.Status != TaskStatusSkipped {
+			return false
+		}
+	}
+	return true
+}
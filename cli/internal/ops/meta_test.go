@@ -0,0 +1,126 @@
+package ops
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tuanpep/oplusflow/internal/frontmatter"
+)
+
+func TestUpdateDocumentMeta_AddsFrontmatter(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "spec-2026-01-10-oauth.md")
+	legacy := "# Feature Specification: OAuth\n\n**Status**: 🔍 In Review\n\n## Goal\nLogin\n"
+	if err := os.WriteFile(path, []byte(legacy), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := UpdateDocumentMeta(path, func(m *frontmatter.Meta) { m.Owner = "alice" })
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if m.ID != "spec-2026-01-10-oauth" || m.Kind != frontmatter.KindSpec || m.Status != "In Review" || m.Owner != "alice" {
+		t.Errorf("Unexpected meta: %+v", m)
+	}
+
+	data, _ := os.ReadFile(path)
+	if !strings.HasPrefix(string(data), "---\n") || !strings.HasSuffix(string(data), legacy) {
+		t.Errorf("Expected frontmatter prepended to untouched body, got:\n%s", data)
+	}
+
+	read, err := ReadDocumentMeta(path)
+	if err != nil || read == nil || read.Owner != "alice" || read.Updated.IsZero() {
+		t.Errorf("Expected metadata to be readable, got %+v (%v)", read, err)
+	}
+}
+
+func TestTaskQueue_SyncPlanStatus(t *testing.T) {
+	dir := t.TempDir()
+	planPath := filepath.Join(dir, "plan-01-x.md")
+	m := NewDocumentMeta(planPath, frontmatter.KindPlan, PlanStatusDraft)
+	content, _ := m.Render("# X\n")
+	if err := os.WriteFile(planPath, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tq := &TaskQueue{
+		PlanPath: planPath,
+		Tasks:    []Task{{ID: "task-1", Status: TaskStatusPending}, {ID: "task-2", Status: TaskStatusSkipped}},
+	}
+
+	status := func() string {
+		m, err := ReadDocumentMeta(planPath)
+		if err != nil || m == nil {
+			t.Fatalf("Failed to read meta: %v", err)
+		}
+		return m.Status
+	}
+
+	if err := tq.syncPlanStatus(); err != nil {
+		t.Fatal(err)
+	}
+	if s := status(); s != PlanStatusInProgress {
+		t.Errorf("Expected In Progress after decompose, got %s", s)
+	}
+
+	tq.Tasks[0].Status = TaskStatusDone
+	if err := tq.syncPlanStatus(); err != nil {
+		t.Fatal(err)
+	}
+	if s := status(); s != PlanStatusDone {
+		t.Errorf("Expected Done when all tasks finished, got %s", s)
+	}
+
+	// Hand-set statuses are left alone
+	if _, err := UpdateDocumentMeta(planPath, func(m *frontmatter.Meta) { m.Status = "Blocked" }); err != nil {
+		t.Fatal(err)
+	}
+	tq.Tasks[0].Status = TaskStatusPending
+	if err := tq.syncPlanStatus(); err != nil {
+		t.Fatal(err)
+	}
+	if s := status(); s != "Blocked" {
+		t.Errorf("Expected custom status to be kept, got %s", s)
+	}
+}
+
+func TestDecomposePlan_RelativePath(t *testing.T) {
+	root := t.TempDir()
+	writeTestFiles(t, root, map[string]string{".agent/.keep": "", "web/.keep": ""})
+	plansDir := filepath.Join(root, "opusflow-planning", "plans")
+	if err := os.MkdirAll(plansDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	planPath := filepath.Join(plansDir, "plan-01-x.md")
+	m := NewDocumentMeta(planPath, frontmatter.KindPlan, PlanStatusDraft)
+	content, _ := m.Render("# X\n\n## Implementation Steps\n\n### Step 1: Do it\n**File**: `a.go`\n")
+	if err := os.WriteFile(planPath, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	oldWd, _ := os.Getwd()
+	defer os.Chdir(oldWd)
+
+	// Decompose from a subdirectory with a root-relative path
+	os.Chdir(filepath.Join(root, "web"))
+	tq, err := QuickDecomposeFromFile(filepath.Join("opusflow-planning", "plans", "plan-01-x.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tq.PlanPath != planPath || tq.PlanRevision == 0 {
+		t.Errorf("Expected the absolute plan path and a revision, got %s, revision %d", tq.PlanPath, tq.PlanRevision)
+	}
+
+	// Finishing the task from another directory still finds the plan
+	os.Chdir(plansDir)
+	tq, _ = LoadTaskQueue("plan-01-x.md")
+	tq.CompleteTask("task-1")
+	if err := tq.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if m, _ := ReadDocumentMeta(planPath); m == nil || m.Status != PlanStatusDone {
+		t.Errorf("Expected the plan to be marked Done, got %+v", m)
+	}
+}
@@ -0,0 +1,490 @@
+package ops
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/tuanpep/oplusflow/internal/frontmatter"
+	"github.com/tuanpep/oplusflow/internal/manager"
+	"github.com/tuanpep/oplusflow/internal/spec"
+)
+
+// Phase statuses, derived from the progress of the phase's plans
+const (
+	PhaseStatusPlanned    = "Planned"
+	PhaseStatusInProgress = "In Progress"
+	PhaseStatusComplete   = "Complete"
+)
+
+// Phase file sections
+const (
+	phaseSectionGoal         = "Goal"
+	phaseSectionPlans        = "Plans"
+	phaseSectionExitCriteria = "Exit Criteria"
+)
+
+var (
+	phaseIndexPattern    = regexp.MustCompile(`^phase-(\d+)-`)
+	phasePlanRefPattern  = regexp.MustCompile(`plan-[A-Za-z0-9_.-]+?\.md`)
+	phaseCriteriaPattern = regexp.MustCompile(`^\s*[-*]\s+\[([ xX])\]\s+(.+?)\s*$`)
+	phaseHeadingPattern  = regexp.MustCompile(`^(#{1,2})\s+(.+?)\s*$`)
+	phaseTitlePattern    = regexp.MustCompile(`^Phase \d+:\s*`)
+)
+
+// Phase is a roadmap milestone grouping an ordered list of plans
+type Phase struct {
+	Index        int               `json:"index"`
+	Filename     string            `json:"filename"`
+	Path         string            `json:"path"`
+	Title        string            `json:"title"`
+	Goal         string            `json:"goal,omitempty"`
+	Plans        []string          `json:"plans"`
+	ExitCriteria []ExitCriterion   `json:"exit_criteria"`
+	Meta         *frontmatter.Meta `json:"meta,omitempty"`
+}
+
+// ExitCriterion is a checklist item that must hold before a phase is complete
+type ExitCriterion struct {
+	Text string `json:"text"`
+	Met  bool   `json:"met"`
+}
+
+// PhaseProgress aggregates task and verification progress across a phase's plans
+type PhaseProgress struct {
+	Phase         *Phase        `json:"phase"`
+	Status        string        `json:"status"`
+	Plans         []PlanSummary `json:"plans"`
+	Missing       []string      `json:"missing,omitempty"` // referenced plans that do not exist
+	TasksDone     int           `json:"tasks_done"`
+	TasksTotal    int           `json:"tasks_total"`
+	PlansDone     int           `json:"plans_done"`
+	PlansVerified int           `json:"plans_verified"`
+	CriteriaMet   int           `json:"criteria_met"`
+}
+
+// CreatePhase writes the next numbered phase file
+func CreatePhase(title, goal string, plans, exitCriteria []string) (*Phase, error) {
+	root, err := manager.FindProjectRoot()
+	if err != nil {
+		return nil, fmt.Errorf("failed to find project root: %w", err)
+	}
+
+	phasesDir := manager.PhasesDir(root)
+	if err := os.MkdirAll(phasesDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create phases directory: %w", err)
+	}
+
+	refs := make([]string, 0, len(plans))
+	for _, p := range plans {
+		path, err := ResolvePlanPath(p)
+		if err != nil {
+			return nil, err
+		}
+		refs = append(refs, filepath.Base(path))
+	}
+
+	idx := nextPhaseIndex(phasesDir)
+	filename := fmt.Sprintf("phase-%02d-%s.md", idx, slugify(title))
+	fullPath := filepath.Join(phasesDir, filename)
+
+	meta := NewDocumentMeta(fullPath, frontmatter.KindPhase, PhaseStatusPlanned)
+	meta.Owner = detectAuthor(root)
+	content, err := meta.Render(generatePhaseContent(idx, title, goal, refs, exitCriteria))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.WriteFile(fullPath, []byte(content), 0644); err != nil {
+		return nil, fmt.Errorf("failed to write phase: %w", err)
+	}
+
+	return LoadPhase(fullPath)
+}
+
+func generatePhaseContent(idx int, title, goal string, plans, exitCriteria []string) string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("# Phase %d: %s\n\n", idx, title))
+
+	sb.WriteString("## Goal\n")
+	if goal == "" {
+		goal = "[What this milestone delivers]"
+	}
+	sb.WriteString(goal + "\n\n")
+
+	sb.WriteString("## Plans\n")
+	sb.WriteString("<!-- Plans are worked on in this order -->\n")
+	for i, p := range plans {
+		sb.WriteString(fmt.Sprintf("%d. %s\n", i+1, p))
+	}
+	sb.WriteString("\n")
+
+	sb.WriteString("## Exit Criteria\n")
+	if len(exitCriteria) == 0 {
+		exitCriteria = []string{"All plans are done and verified"}
+	}
+	for _, c := range exitCriteria {
+		sb.WriteString(fmt.Sprintf("- [ ] %s\n", c))
+	}
+
+	return sb.String()
+}
+
+func nextPhaseIndex(dir string) int {
+	maxIdx := 0
+	matches, _ := filepath.Glob(filepath.Join(dir, "phase-*.md"))
+	for _, m := range matches {
+		if sm := phaseIndexPattern.FindStringSubmatch(filepath.Base(m)); sm != nil {
+			if idx, err := strconv.Atoi(sm[1]); err == nil && idx > maxIdx {
+				maxIdx = idx
+			}
+		}
+	}
+	return maxIdx + 1
+}
+
+// ResolvePhasePath locates a phase file given a path or a bare filename in the phases directory
+func ResolvePhasePath(ref string) (string, error) {
+	root, err := manager.FindProjectRoot()
+	if err != nil {
+		return "", fmt.Errorf("failed to find project root: %w", err)
+	}
+
+	candidates := []string{ref}
+	if !filepath.IsAbs(ref) {
+		candidates = []string{
+			filepath.Join(root, ref),
+			filepath.Join(manager.PhasesDir(root), filepath.Base(ref)),
+		}
+	}
+
+	for _, c := range candidates {
+		if info, err := os.Stat(c); err == nil && !info.IsDir() {
+			return c, nil
+		}
+	}
+
+	// Allow "1" or "01" as shorthand for phase-01-*.md
+	if n, err := strconv.Atoi(ref); err == nil {
+		matches, _ := filepath.Glob(filepath.Join(manager.PhasesDir(root), fmt.Sprintf("phase-%02d-*.md", n)))
+		if len(matches) == 1 {
+			return matches[0], nil
+		}
+	}
+
+	return "", fmt.Errorf("phase not found: %s", ref)
+}
+
+// LoadPhase reads and parses a phase file
+func LoadPhase(ref string) (*Phase, error) {
+	path, err := ResolvePhasePath(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read phase: %w", err)
+	}
+
+	ph := ParsePhase(string(data))
+	ph.Path = path
+	ph.Filename = filepath.Base(path)
+	if m := phaseIndexPattern.FindStringSubmatch(ph.Filename); m != nil {
+		ph.Index, _ = strconv.Atoi(m[1])
+	}
+	if ph.Title == "" {
+		ph.Title = strings.TrimSuffix(ph.Filename, filepath.Ext(ph.Filename))
+	}
+	return ph, nil
+}
+
+// ParsePhase parses a phase document. The Plans section lists plan files in
+// order (one per list item); Exit Criteria is a checklist.
+func ParsePhase(content string) *Phase {
+	ph := &Phase{}
+	ph.Meta, _, _ = frontmatter.Parse(content)
+	_, body, _, _ := frontmatter.Split(content)
+
+	section := ""
+	var goal []string
+	for _, line := range strings.Split(body, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if m := phaseHeadingPattern.FindStringSubmatch(line); m != nil {
+			if len(m[1]) == 1 {
+				if ph.Title == "" {
+					ph.Title = phaseTitlePattern.ReplaceAllString(m[2], "")
+				}
+				section = ""
+				continue
+			}
+			section = m[2]
+			continue
+		}
+
+		switch {
+		case strings.EqualFold(section, phaseSectionGoal):
+			if text := strings.TrimSpace(commentPattern.ReplaceAllString(line, "")); text != "" && !spec.IsPlaceholder(text) {
+				goal = append(goal, text)
+			}
+		case strings.EqualFold(section, phaseSectionPlans):
+			if !isListItem(line) {
+				continue
+			}
+			if ref := phasePlanRefPattern.FindString(line); ref != "" {
+				ph.Plans = append(ph.Plans, ref)
+			}
+		case strings.EqualFold(section, phaseSectionExitCriteria):
+			if m := phaseCriteriaPattern.FindStringSubmatch(line); m != nil {
+				ph.ExitCriteria = append(ph.ExitCriteria, ExitCriterion{Text: m[2], Met: m[1] != " "})
+			}
+		}
+	}
+	ph.Goal = strings.Join(goal, "\n")
+
+	return ph
+}
+
+var (
+	listItemLinePattern = regexp.MustCompile(`^\s*(?:[-*]|\d+[.)])\s+`)
+	commentPattern      = regexp.MustCompile(`<!--.*?-->`)
+)
+
+func isListItem(line string) bool {
+	return listItemLinePattern.MatchString(line)
+}
+
+// AddPlansToPhase appends plans to the end of a phase's Plans section
+func AddPlansToPhase(phaseRef string, plans []string) (*Phase, error) {
+	ph, err := LoadPhase(phaseRef)
+	if err != nil {
+		return nil, err
+	}
+
+	var added []string
+	for _, p := range plans {
+		path, err := ResolvePlanPath(p)
+		if err != nil {
+			return nil, err
+		}
+		ref := filepath.Base(path)
+		if slices.Contains(ph.Plans, ref) || slices.Contains(added, ref) {
+			continue
+		}
+		added = append(added, ref)
+	}
+	if len(added) == 0 {
+		return ph, nil
+	}
+
+	data, err := os.ReadFile(ph.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read phase: %w", err)
+	}
+
+	lines := strings.Split(string(data), "\n")
+	start, end := -1, len(lines)
+	for i, line := range lines {
+		m := phaseHeadingPattern.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		if start >= 0 {
+			end = i
+			break
+		}
+		if len(m[1]) == 2 && strings.EqualFold(m[2], phaseSectionPlans) {
+			start = i
+		}
+	}
+
+	var items []string
+	for i, ref := range added {
+		items = append(items, fmt.Sprintf("%d. %s", len(ph.Plans)+i+1, ref))
+	}
+
+	if start < 0 {
+		// No Plans section yet: add one at the end
+		section := append([]string{"", "## " + phaseSectionPlans}, items...)
+		lines = append(strings.Split(strings.TrimRight(string(data), "\n"), "\n"), append(section, "")...)
+	} else {
+		// Insert after the last non-blank line of the section
+		at := end
+		for at > start+1 && strings.TrimSpace(lines[at-1]) == "" {
+			at--
+		}
+		lines = append(lines[:at], append(items, lines[at:]...)...)
+	}
+
+	if _, err := writeWithMetaTouch(ph.Path, strings.Join(lines, "\n")); err != nil {
+		return nil, err
+	}
+
+	return LoadPhase(ph.Path)
+}
+
+// writeWithMetaTouch writes content, bumping the updated timestamp if it has frontmatter
+func writeWithMetaTouch(path, content string) (string, error) {
+	if m, body, err := frontmatter.Parse(content); err == nil && m != nil {
+		m.Touch()
+		if rendered, err := m.Render(body); err == nil {
+			content = rendered
+		}
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return "", fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return content, nil
+}
+
+// ListPhases returns all phases in roadmap order
+func ListPhases() ([]*Phase, error) {
+	root, err := manager.FindProjectRoot()
+	if err != nil {
+		return nil, fmt.Errorf("failed to find project root: %w", err)
+	}
+
+	matches, _ := filepath.Glob(filepath.Join(manager.PhasesDir(root), "phase-*.md"))
+	phases := make([]*Phase, 0, len(matches))
+	for _, m := range matches {
+		ph, err := LoadPhase(m)
+		if err != nil {
+			return nil, err
+		}
+		phases = append(phases, ph)
+	}
+
+	sort.SliceStable(phases, func(i, j int) bool {
+		if phases[i].Index != phases[j].Index {
+			return phases[i].Index < phases[j].Index
+		}
+		return phases[i].Filename < phases[j].Filename
+	})
+	return phases, nil
+}
+
+// GetPhaseProgress aggregates the status of every plan in a phase. A phase is
+// complete when all its plans are done, their latest verification passed and
+// every exit criterion is checked.
+func GetPhaseProgress(ph *Phase) (*PhaseProgress, error) {
+	progress := &PhaseProgress{Phase: ph, Plans: []PlanSummary{}}
+
+	for _, ref := range ph.Plans {
+		summary, err := GetPlanSummary(ref)
+		if err != nil {
+			progress.Missing = append(progress.Missing, ref)
+			continue
+		}
+		progress.Plans = append(progress.Plans, *summary)
+
+		progress.TasksDone += summary.TasksDone
+		progress.TasksTotal += summary.TasksTotal
+		if summary.Status == PlanStatusDone || summary.Status == PlanStatusArchived {
+			progress.PlansDone++
+		}
+		if summary.Verification != nil && summary.Verification.Status == "passed" {
+			progress.PlansVerified++
+		}
+	}
+
+	for _, c := range ph.ExitCriteria {
+		if c.Met {
+			progress.CriteriaMet++
+		}
+	}
+
+	planCount := len(ph.Plans)
+	switch {
+	case planCount > 0 && len(progress.Missing) == 0 &&
+		progress.PlansDone == planCount && progress.PlansVerified == planCount &&
+		progress.CriteriaMet == len(ph.ExitCriteria):
+		progress.Status = PhaseStatusComplete
+	case progress.TasksDone > 0 || progress.PlansDone > 0 || progress.hasStartedPlan():
+		progress.Status = PhaseStatusInProgress
+	default:
+		progress.Status = PhaseStatusPlanned
+	}
+
+	return progress, nil
+}
+
+func (pp *PhaseProgress) hasStartedPlan() bool {
+	for _, p := range pp.Plans {
+		if p.Status == PlanStatusInProgress || p.Verification != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// FormatText renders the phase progress report
+func (pp *PhaseProgress) FormatText() string {
+	var sb strings.Builder
+	ph := pp.Phase
+
+	sb.WriteString(fmt.Sprintf("# Phase %d: %s (%s)\n\n", ph.Index, ph.Title, pp.Status))
+	if ph.Goal != "" {
+		sb.WriteString(ph.Goal + "\n\n")
+	}
+
+	sb.WriteString(fmt.Sprintf("**Plans**: %d/%d done, %d/%d verified\n", pp.PlansDone, len(ph.Plans), pp.PlansVerified, len(ph.Plans)))
+	sb.WriteString(fmt.Sprintf("**Tasks**: %d/%d done\n", pp.TasksDone, pp.TasksTotal))
+	sb.WriteString(fmt.Sprintf("**Exit Criteria**: %d/%d met\n\n", pp.CriteriaMet, len(ph.ExitCriteria)))
+
+	if len(pp.Plans) > 0 {
+		sb.WriteString("## Plans\n\n")
+		sb.WriteString(FormatPlanTable(pp.Plans))
+		sb.WriteString("\n")
+	}
+	for _, ref := range pp.Missing {
+		sb.WriteString(fmt.Sprintf("⚠️  Plan not found: %s\n", ref))
+	}
+	if len(pp.Missing) > 0 {
+		sb.WriteString("\n")
+	}
+
+	if len(ph.ExitCriteria) > 0 {
+		sb.WriteString("## Exit Criteria\n\n")
+		for _, c := range ph.ExitCriteria {
+			mark := "[ ]"
+			if c.Met {
+				mark = "[x]"
+			}
+			sb.WriteString(fmt.Sprintf("- %s %s\n", mark, c.Text))
+		}
+	}
+
+	return sb.String()
+}
+
+// FormatPhaseRoadmap renders one line per phase with its aggregated status
+func FormatPhaseRoadmap(progress []*PhaseProgress) string {
+	if len(progress) == 0 {
+		return "No phases found. Create one with 'opusflow phase create'.\n"
+	}
+
+	var sb strings.Builder
+	for _, pp := range progress {
+		sb.WriteString(fmt.Sprintf("%02d  %-40s %-12s plans %d/%d  tasks %d/%d  criteria %d/%d\n",
+			pp.Phase.Index, pp.Phase.Title, pp.Status,
+			pp.PlansDone, len(pp.Phase.Plans), pp.TasksDone, pp.TasksTotal,
+			pp.CriteriaMet, len(pp.Phase.ExitCriteria)))
+	}
+	return sb.String()
+}
+
+// FormatPhaseProgressJSON returns phase progress as indented JSON
+func FormatPhaseProgressJSON(progress []*PhaseProgress) (string, error) {
+	data, err := json.MarshalIndent(progress, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
@@ -0,0 +1,137 @@
+package ops
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParsePhase(t *testing.T) {
+	content := `---
+id: phase-01-auth
+kind: phase
+status: Planned
+---
+# Phase 1: Authentication
+
+## Goal
+Users can sign in.
+
+## Plans
+<!-- Plans are worked on in this order -->
+1. plan-02-login.md
+2. [Sessions](../plans/plan-03-sessions.md)
+Not a plan-09-ignored.md item
+
+## Exit Criteria
+- [x] Security review done
+- [ ] Load test passes
+`
+	ph := ParsePhase(content)
+
+	if ph.Title != "Authentication" || ph.Goal != "Users can sign in." {
+		t.Errorf("Unexpected title/goal: %q / %q", ph.Title, ph.Goal)
+	}
+	if strings.Join(ph.Plans, ",") != "plan-02-login.md,plan-03-sessions.md" {
+		t.Errorf("Unexpected plans: %v", ph.Plans)
+	}
+	if len(ph.ExitCriteria) != 2 || !ph.ExitCriteria[0].Met || ph.ExitCriteria[1].Met {
+		t.Errorf("Unexpected exit criteria: %+v", ph.ExitCriteria)
+	}
+	if ph.Meta == nil || ph.Meta.Status != "Planned" {
+		t.Errorf("Expected frontmatter to be parsed, got %+v", ph.Meta)
+	}
+}
+
+func TestPhaseProgress(t *testing.T) {
+	tmpDir := t.TempDir()
+	verifyDir := filepath.Join(tmpDir, "opusflow-planning", "verifications")
+	for _, d := range []string{filepath.Join(tmpDir, "opusflow-planning", "plans"), verifyDir} {
+		if err := os.MkdirAll(d, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	oldWd, _ := os.Getwd()
+	os.Chdir(tmpDir)
+	defer os.Chdir(oldWd)
+
+	first, err := CreatePlan("Login", "Login", PlanOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := CreatePlan("Sessions", "Sessions", PlanOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ph, err := CreatePhase("Authentication", "Users can sign in", []string{first.Filename}, []string{"Security review done"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if ph.Index != 1 || len(ph.Plans) != 1 || len(ph.ExitCriteria) != 1 {
+		t.Fatalf("Unexpected phase: %+v", ph)
+	}
+
+	ph, err = AddPlansToPhase("1", []string{second.Filename, first.Filename})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if strings.Join(ph.Plans, ",") != first.Filename+","+second.Filename {
+		t.Errorf("Expected plans in order without duplicates, got %v", ph.Plans)
+	}
+
+	pp, err := GetPhaseProgress(ph)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pp.Status != PhaseStatusPlanned {
+		t.Errorf("Expected Planned, got %s", pp.Status)
+	}
+
+	// Finish both plans and verify them
+	for _, plan := range []*CreatePlanResult{first, second} {
+		tq, err := QuickDecomposeFromFile(plan.FullPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, task := range tq.Tasks {
+			tq.CompleteTask(task.ID)
+		}
+		if err := tq.Save(); err != nil {
+			t.Fatal(err)
+		}
+		base := strings.TrimSuffix(plan.Filename, ".md")
+		report := "---\nid: verify\nkind: verification\nstatus: passed\n---\n# Report\n"
+		if err := os.WriteFile(filepath.Join(verifyDir, "verify-"+base+"-2026-01-10.md"), []byte(report), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	pp, err = GetPhaseProgress(ph)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pp.Status != PhaseStatusInProgress || pp.PlansDone != 2 || pp.PlansVerified != 2 || pp.TasksDone != pp.TasksTotal {
+		t.Errorf("Expected In Progress until exit criteria are met, got %+v", pp)
+	}
+
+	data, _ := os.ReadFile(ph.Path)
+	checked := strings.Replace(string(data), "- [ ] Security review done", "- [x] Security review done", 1)
+	if err := os.WriteFile(ph.Path, []byte(checked), 0644); err != nil {
+		t.Fatal(err)
+	}
+	ph, _ = LoadPhase(ph.Filename)
+
+	pp, err = GetPhaseProgress(ph)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pp.Status != PhaseStatusComplete {
+		t.Errorf("Expected Complete, got %s", pp.Status)
+	}
+	if text := pp.FormatText(); !strings.Contains(text, "Phase 1: Authentication (Complete)") || !strings.Contains(text, "2/2 verified") {
+		t.Errorf("Unexpected status report:\n%s", text)
+	}
+}
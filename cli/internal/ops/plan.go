@@ -1,15 +1,20 @@
 package ops
 
 import (
+	"bytes"
 	"fmt"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"text/template"
 
+	"github.com/tuanpep/oplusflow/internal/frontmatter"
 	"github.com/tuanpep/oplusflow/internal/manager"
+	"github.com/tuanpep/oplusflow/internal/spec"
 	"github.com/tuanpep/oplusflow/internal/templates"
 )
 
@@ -17,9 +22,48 @@ import (
 type CreatePlanResult struct {
 	Filename string
 	FullPath string
+	Template string
 }
 
-func CreatePlan(rawTitle string, goal string) (*CreatePlanResult, error) {
+// PlanOptions are optional settings for CreatePlan
+type PlanOptions struct {
+	// Template is the plan template name; defaults to templates.DefaultPlanTemplate
+	Template string
+	// SpecPath links the plan to a spec file
+	SpecPath string
+	// Spec pre-populates requirements, constraints and success criteria
+	Spec *spec.Spec
+}
+
+// PlanTemplateData is the data available to plan templates
+type PlanTemplateData struct {
+	Title           string
+	Goal            string
+	Author          string
+	SpecRef         string // spec filename
+	SpecLink        string // spec path relative to the plans directory
+	CodebaseSummary string
+	BuildCommand    string
+	TestCommand     string
+	Dependencies    string
+	Context         string
+	Environment     string
+
+	// Populated when planning from a spec
+	Requirements   []spec.Requirement
+	RequirementIDs string
+	Constraints    []string
+	SpecCriteria   []spec.Requirement
+}
+
+// PlanTemplateInfo describes an available plan or spec template
+type PlanTemplateInfo struct {
+	Name   string
+	Source string // "built-in" or the override file path
+}
+
+// CreatePlan renders a plan template into the next numbered plan file
+func CreatePlan(rawTitle string, goal string, opts PlanOptions) (*CreatePlanResult, error) {
 	title := strings.Join(strings.Fields(rawTitle), "-")
 	// Sanitize title
 	reg, err := regexp.Compile("[^a-zA-Z0-9-]+")
@@ -39,49 +83,259 @@ func CreatePlan(rawTitle string, goal string) (*CreatePlanResult, error) {
 		return nil, fmt.Errorf("failed to get planning directories: %w", err)
 	}
 
-	// Calculate index
-	idx := getNextPlanIndex(plansDir)
-	filename := fmt.Sprintf("plan-%02d-%s.md", idx, title)
-	fullPath := filepath.Join(plansDir, filename)
+	templateName := opts.Template
+	if templateName == "" {
+		templateName = templates.DefaultPlanTemplate
+	}
+	tmplContent, err := LoadPlanTemplate(rootDir, templateName)
+	if err != nil {
+		return nil, err
+	}
 
-	// Create file
-	f, err := os.Create(fullPath)
+	tmpl, err := template.New("plan").Funcs(planTemplateFuncs).Parse(tmplContent)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create file: %w", err)
+		return nil, fmt.Errorf("failed to parse template %s: %w", templateName, err)
 	}
-	defer f.Close()
 
-	// Template data
-	data := struct {
-		Goal         string
-		Dependencies string
-		Context      string
-		Environment  string
-		Strategy     string
-	}{
-		Goal:         goal,
-		Dependencies: "TBD",
-		Context:      "TBD",
-		Environment:  "TBD",
-		Strategy:     "TBD",
+	data := buildPlanTemplateData(rootDir, plansDir, rawTitle, goal, opts)
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("failed to execute template %s: %w", templateName, err)
 	}
 
-	tmpl, err := template.New("plan").Parse(templates.PlanTemplate)
+	// Calculate index; archived plans keep their numbers reserved
+	idx := max(getNextPlanIndex(plansDir), getNextPlanIndex(manager.ArchivedPlansDir(rootDir)))
+	filename := fmt.Sprintf("plan-%02d-%s.md", idx, title)
+	fullPath := filepath.Join(plansDir, filename)
+
+	content, err := withPlanMeta(buf.String(), fullPath, data)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse template: %w", err)
+		return nil, fmt.Errorf("failed to render template %s: %w", templateName, err)
 	}
 
-	err = tmpl.Execute(f, data)
-	if err != nil {
-		return nil, fmt.Errorf("failed to execute template: %w", err)
+	if err := os.WriteFile(fullPath, []byte(content), 0644); err != nil {
+		return nil, fmt.Errorf("failed to create file: %w", err)
 	}
 
 	return &CreatePlanResult{
 		Filename: filename,
 		FullPath: fullPath,
+		Template: templateName,
 	}, nil
 }
 
+// withPlanMeta adds frontmatter to a rendered plan. Fields already set by a
+// project template's own frontmatter are kept.
+func withPlanMeta(rendered, path string, data PlanTemplateData) (string, error) {
+	m, body, err := frontmatter.Parse(rendered)
+	if err != nil {
+		return "", err
+	}
+
+	defaults := NewDocumentMeta(path, frontmatter.KindPlan, PlanStatusDraft)
+	if m == nil {
+		m = defaults
+	}
+
+	fill := func(field *string, value string) {
+		if *field == "" {
+			*field = value
+		}
+	}
+	fill(&m.ID, defaults.ID)
+	fill(&m.Kind, defaults.Kind)
+	fill(&m.Status, defaults.Status)
+	fill(&m.Owner, data.Author)
+	fill(&m.SpecRef, data.SpecRef)
+	fill(&m.WorkflowID, defaults.WorkflowID)
+	m.Touch()
+
+	return m.Render(body)
+}
+
+// planTemplateFuncs are the functions available to plan templates
+var planTemplateFuncs = template.FuncMap{
+	"inc": func(i int) int { return i + 1 },
+}
+
+// CreatePlanFromSpec creates a plan pre-populated from a spec's goal, functional
+// requirements, architecture constraints and success criteria. Specs that
+// have not been approved are refused unless force is set.
+func CreatePlanFromSpec(specRef, title string, opts PlanOptions, force bool) (*CreatePlanResult, error) {
+	s, specPath, err := LoadSpec(specRef)
+	if err != nil {
+		return nil, err
+	}
+
+	status := s.Status
+	if status == "" {
+		status = spec.StatusDraft
+	}
+	if !force && status != spec.StatusApproved {
+		return nil, fmt.Errorf("spec %s is %s; approve it first (opusflow spec approve) or use --force", filepath.Base(specPath), status)
+	}
+
+	if title == "" {
+		title = s.Title
+	}
+	if title == "" {
+		return nil, fmt.Errorf("spec %s has no title; pass one explicitly", filepath.Base(specPath))
+	}
+
+	goal := s.Goal
+	if goal == "" {
+		goal = title
+	}
+
+	opts.SpecPath = specPath
+	opts.Spec = s
+	return CreatePlan(title, goal, opts)
+}
+
+// buildPlanTemplateData gathers everything a plan template can reference
+func buildPlanTemplateData(rootDir, plansDir, title, goal string, opts PlanOptions) PlanTemplateData {
+	cmds := DetectProjectCommands(rootDir)
+	// Prefer configured commands; a broken config falls back to detection
+	if resolved, err := ResolveProjectCommands(rootDir); err == nil {
+		cmds = ProjectCommands{}
+		for _, pc := range resolved {
+			switch pc.Kind {
+			case CommandBuild:
+				cmds.Build = pc.CommandLine()
+			case CommandTest:
+				cmds.Test = pc.CommandLine()
+			}
+		}
+	}
+
+	data := PlanTemplateData{
+		Title:           strings.TrimSpace(title),
+		Goal:            goal,
+		Author:          detectAuthor(rootDir),
+		CodebaseSummary: summarizeCodebase(rootDir),
+		BuildCommand:    cmds.Build,
+		TestCommand:     cmds.Test,
+	}
+
+	if opts.SpecPath != "" {
+		data.SpecRef = filepath.Base(opts.SpecPath)
+		data.SpecLink = filepath.ToSlash(opts.SpecPath)
+		if rel, err := filepath.Rel(plansDir, opts.SpecPath); err == nil && filepath.IsAbs(opts.SpecPath) {
+			data.SpecLink = filepath.ToSlash(rel)
+		}
+		data.Context = fmt.Sprintf("See spec %s", data.SpecRef)
+	}
+
+	if opts.Spec != nil {
+		data.Requirements = opts.Spec.FilledRequirements()
+		data.SpecCriteria = opts.Spec.FilledSuccessCriteria()
+		ids := make([]string, len(data.Requirements))
+		for i, r := range data.Requirements {
+			ids[i] = r.ID
+		}
+		data.RequirementIDs = strings.Join(ids, ", ")
+		for _, c := range opts.Spec.Constraints {
+			if !spec.IsPlaceholder(c.Text) {
+				data.Constraints = append(data.Constraints, c.Text)
+			}
+		}
+	}
+
+	var env []string
+	if cmds.Build != "" {
+		env = append(env, fmt.Sprintf("build with `%s`", cmds.Build))
+	}
+	if cmds.Test != "" {
+		env = append(env, fmt.Sprintf("test with `%s`", cmds.Test))
+	}
+	if len(env) > 0 {
+		data.Environment = strings.Join(env, ", ")
+	}
+
+	return data
+}
+
+// detectAuthor returns the git user name, falling back to the OS user
+func detectAuthor(rootDir string) string {
+	cmd := exec.Command("git", "config", "user.name")
+	cmd.Dir = rootDir
+	if out, err := cmd.Output(); err == nil {
+		if name := strings.TrimSpace(string(out)); name != "" {
+			return name
+		}
+	}
+	if user := os.Getenv("USER"); user != "" {
+		return user
+	}
+	return "unknown"
+}
+
+// summarizeCodebase returns a one-line description of the project's source files
+func summarizeCodebase(rootDir string) string {
+	const maxFiles = 500
+
+	pm, err := GenerateCodebaseMap(rootDir, nil, nil, maxFiles)
+	if err != nil || pm.Statistics.TotalFiles == 0 {
+		return ""
+	}
+
+	files := fmt.Sprintf("%d", pm.Statistics.TotalFiles)
+	if pm.Statistics.TotalFiles >= maxFiles {
+		files += "+"
+	}
+	return fmt.Sprintf("%s source files, %d lines (%s)", files, pm.Statistics.TotalLines, strings.Join(pm.Languages, ", "))
+}
+
+// planTemplateOverridePath returns where a project override for a plan template lives
+func planTemplateOverridePath(rootDir, name string) string {
+	return filepath.Join(manager.TemplatesDir(rootDir), "plan-"+name+".md")
+}
+
+// LoadPlanTemplate returns the named plan template, preferring a project
+// override in .opusflow/templates over the built-in template
+func LoadPlanTemplate(rootDir, name string) (string, error) {
+	if data, err := os.ReadFile(planTemplateOverridePath(rootDir, name)); err == nil {
+		return string(data), nil
+	}
+
+	if content, ok := templates.PlanTemplates[name]; ok {
+		return content, nil
+	}
+
+	names := make([]string, 0)
+	for _, t := range ListPlanTemplates(rootDir) {
+		names = append(names, t.Name)
+	}
+	return "", fmt.Errorf("unknown plan template %q (available: %s)", name, strings.Join(names, ", "))
+}
+
+// ListPlanTemplates returns built-in and project plan templates sorted by name
+func ListPlanTemplates(rootDir string) []PlanTemplateInfo {
+	return listTemplates(rootDir, "plan-", templates.PlanTemplates)
+}
+
+// listTemplates merges built-in templates with the project's <prefix><name>.md overrides
+func listTemplates(rootDir, prefix string, builtin map[string]string) []PlanTemplateInfo {
+	sources := make(map[string]string)
+	for name := range builtin {
+		sources[name] = "built-in"
+	}
+
+	matches, _ := filepath.Glob(filepath.Join(manager.TemplatesDir(rootDir), prefix+"*.md"))
+	for _, m := range matches {
+		name := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(m), prefix), ".md")
+		sources[name] = m
+	}
+
+	infos := make([]PlanTemplateInfo, 0, len(sources))
+	for name, source := range sources {
+		infos = append(infos, PlanTemplateInfo{Name: name, Source: source})
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name < infos[j].Name })
+	return infos
+}
+
 func getNextPlanIndex(dir string) int {
 	files, err := os.ReadDir(dir)
 	if err != nil {
@@ -108,3 +362,29 @@ func getNextPlanIndex(dir string) int {
 	}
 	return maxIdx + 1
 }
+
+// ResolvePlanPath locates a plan file given a path (absolute or relative to the
+// project root) or a bare filename inside the plans directory or its archive
+func ResolvePlanPath(ref string) (string, error) {
+	root, err := manager.FindProjectRoot()
+	if err != nil {
+		return "", fmt.Errorf("failed to find project root: %w", err)
+	}
+
+	candidates := []string{ref}
+	if !filepath.IsAbs(ref) {
+		candidates = []string{
+			filepath.Join(root, ref),
+			filepath.Join(manager.PlansDir(root), filepath.Base(ref)),
+			filepath.Join(manager.ArchivedPlansDir(root), filepath.Base(ref)),
+		}
+	}
+
+	for _, c := range candidates {
+		if info, err := os.Stat(c); err == nil && !info.IsDir() {
+			return c, nil
+		}
+	}
+
+	return "", fmt.Errorf("plan not found: %s", ref)
+}
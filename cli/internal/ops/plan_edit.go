@@ -0,0 +1,256 @@
+package ops
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/tuanpep/oplusflow/internal/plan"
+)
+
+// SectionUpdateResult describes an applied plan section edit
+type SectionUpdateResult struct {
+	PlanPath string        `json:"plan_path"`
+	Target   string        `json:"target"`
+	Mode     plan.EditMode `json:"mode"`
+	Revision int           `json:"revision,omitempty"`
+}
+
+// UpdatePlanSection replaces or appends to one section of a plan ("Observations",
+// "Proposed Changes", "Step 3", ...) and leaves the rest of the document
+// untouched. The edited plan is re-parsed before it is written: the edit must
+// not add or remove sections or steps, nor introduce new parse errors.
+func UpdatePlanSection(planRef, target, content string, mode plan.EditMode) (*SectionUpdateResult, error) {
+	planPath, err := ResolvePlanPath(planRef)
+	if err != nil {
+		return nil, err
+	}
+
+	original, err := os.ReadFile(planPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read plan: %w", err)
+	}
+
+	p, parseErr := plan.Parse(string(original))
+	before := countParseErrors(parseErr)
+
+	if err := p.UpdateSection(target, content, mode); err != nil {
+		return nil, err
+	}
+	updated := p.Markdown()
+
+	reparsed, parseErr := plan.Parse(updated)
+	if countParseErrors(parseErr) > before {
+		return nil, fmt.Errorf("edit would make the plan invalid:\n%w", parseErr)
+	}
+	if reparsed.Structure() != p.Structure() {
+		return nil, fmt.Errorf("content for %q must not contain section (##) or step (### Step) headings; update each section separately", target)
+	}
+
+	// Keep the version being replaced; history is best effort
+	_, _ = SnapshotPlan(planPath, RevisionReasonSectionEdit)
+
+	if err := os.WriteFile(planPath, []byte(updated), 0644); err != nil {
+		return nil, fmt.Errorf("failed to write plan: %w", err)
+	}
+
+	result := &SectionUpdateResult{PlanPath: planPath, Target: target, Mode: mode}
+	if rev, err := SnapshotPlan(planPath, RevisionReasonSectionEdit); err == nil {
+		result.Revision = rev.Number
+	}
+	return result, nil
+}
+
+func countParseErrors(err error) int {
+	var errs plan.ParseErrors
+	if errors.As(err, &errs) {
+		return len(errs)
+	}
+	if err != nil {
+		return 1
+	}
+	return 0
+}
+
+// PlanRewriteResult describes a structural plan edit (fmt, step add, step move)
+type PlanRewriteResult struct {
+	PlanPath string
+	Changed  bool
+	// Diff is a unified diff of the edit
+	Diff string
+	// TasksUpdated is set when the plan's task queue was renumbered to match
+	TasksUpdated bool
+}
+
+// NewStep describes a step added with AddPlanStep
+type NewStep struct {
+	Title        string
+	File         string
+	Action       string
+	Description  string
+	Verification string
+}
+
+// FormatPlanFile normalizes a plan's headings and renumbers its steps. With
+// dryRun the plan is left untouched and only the diff is returned.
+func FormatPlanFile(ref string, dryRun bool) (*PlanRewriteResult, error) {
+	return rewritePlan(ref, RevisionReasonFormat, dryRun, func(p *plan.Plan) ([]plan.StepMove, error) {
+		return p.Format(), nil
+	})
+}
+
+// AddPlanStep inserts a new step after step position after (0 for the start)
+func AddPlanStep(ref string, after int, step NewStep) (*PlanRewriteResult, error) {
+	if step.Title == "" {
+		return nil, fmt.Errorf("step title required")
+	}
+	return rewritePlan(ref, RevisionReasonStepAdd, false, func(p *plan.Plan) ([]plan.StepMove, error) {
+		return p.InsertStep(after, step.Title, step.body())
+	})
+}
+
+// MovePlanStep moves step from so it becomes step to
+func MovePlanStep(ref string, from, to int) (*PlanRewriteResult, error) {
+	return rewritePlan(ref, RevisionReasonStepMove, false, func(p *plan.Plan) ([]plan.StepMove, error) {
+		return p.MoveStep(from, to)
+	})
+}
+
+// body renders the step in the layout used by the plan templates
+func (s NewStep) body() string {
+	file := s.File
+	if file == "" {
+		file = "[Absolute Path]"
+	}
+	action := s.Action
+	if action == "" {
+		action = "[Create/Update/Delete]"
+	}
+	description := s.Description
+	if description == "" {
+		description = "[Detailed description of what to do]"
+	}
+	verification := s.Verification
+	if verification == "" {
+		verification = "[Command]"
+	}
+
+	return fmt.Sprintf("**File**: `%s`\n**Action**: %s\n\n**Description**:\n%s\n\n**Verification**:\n- [ ] Automated: `%s`\n\n---\n\n",
+		file, action, description, verification)
+}
+
+// rewritePlan applies a structural edit, writes the plan (snapshotting the
+// revisions on either side) and renumbers the plan's task queue to match
+func rewritePlan(ref, reason string, dryRun bool, edit func(p *plan.Plan) ([]plan.StepMove, error)) (*PlanRewriteResult, error) {
+	planPath, err := ResolvePlanPath(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	original, err := os.ReadFile(planPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read plan: %w", err)
+	}
+
+	p, parseErr := plan.Parse(string(original))
+	if parseErr != nil {
+		return nil, fmt.Errorf("invalid plan %s:\n%w", planPath, parseErr)
+	}
+
+	moves, err := edit(p)
+	if err != nil {
+		return nil, err
+	}
+
+	updated := p.Markdown()
+	name := filepath.Base(planPath)
+	result := &PlanRewriteResult{
+		PlanPath: planPath,
+		Changed:  updated != string(original),
+		Diff:     UnifiedDiff(string(original), updated, name, name+" (formatted)"),
+	}
+	if dryRun || !result.Changed {
+		return result, nil
+	}
+
+	if _, err := plan.Parse(updated); err != nil {
+		return nil, fmt.Errorf("edit would make the plan invalid:\n%w", err)
+	}
+
+	_, _ = SnapshotPlan(planPath, reason)
+	if err := os.WriteFile(planPath, []byte(updated), 0644); err != nil {
+		return nil, fmt.Errorf("failed to write plan: %w", err)
+	}
+	rev, _ := SnapshotPlan(planPath, reason)
+
+	result.TasksUpdated, err = renumberTaskQueue(planPath, updated, moves, rev)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// renumberTaskQueue rebuilds a plan's task queue after its steps moved,
+// carrying status and agent over to each step's new task. Queues that were
+// not decomposed from the plan's steps (e.g. loaded with --from) are left alone.
+func renumberTaskQueue(planPath, content string, moves []plan.StepMove, rev *PlanRevision) (bool, error) {
+	tq, err := LoadTaskQueue(filepath.Base(planPath))
+	if err != nil {
+		return false, nil
+	}
+
+	old := make(map[int]Task, len(tq.Tasks))
+	var acceptance []Task
+	for _, t := range tq.Tasks {
+		if strings.HasPrefix(t.ID, acceptanceTaskPrefix) {
+			acceptance = append(acceptance, t)
+			continue
+		}
+		if t.ID != fmt.Sprintf("task-%d", t.StepNumber) {
+			return false, nil
+		}
+		old[t.StepNumber] = t
+	}
+
+	tasks, err := extractTasksFromPlan(content)
+	if err != nil {
+		return false, err
+	}
+
+	completed := 0
+	for _, m := range moves {
+		prev, ok := old[m.Old]
+		if m.Old == 0 || !ok || m.New > len(tasks) {
+			continue
+		}
+		tasks[m.New-1].Status = prev.Status
+		tasks[m.New-1].Agent = prev.Agent
+		if prev.Status == TaskStatusDone {
+			completed++
+		}
+	}
+
+	// Acceptance test stubs stay at the front of the queue
+	for _, t := range acceptance {
+		if t.Status == TaskStatusDone {
+			completed++
+		}
+	}
+	tasks = append(acceptance, tasks...)
+	for i := range tasks {
+		tasks[i].Order = i + 1
+	}
+
+	tq.Tasks = tasks
+	tq.TotalSteps = len(tasks)
+	tq.CompletedSteps = completed
+	if rev != nil {
+		tq.PlanRevision = rev.Number
+	}
+	if err := tq.Save(); err != nil {
+		return false, fmt.Errorf("failed to update task queue: %w", err)
+	}
+	return true, nil
+}
@@ -0,0 +1,139 @@
+package ops
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tuanpep/oplusflow/internal/plan"
+)
+
+func TestUpdatePlanSection(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(tmpDir, "opusflow-planning", "plans"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	oldWd, _ := os.Getwd()
+	os.Chdir(tmpDir)
+	defer os.Chdir(oldWd)
+
+	created, err := CreatePlan("Add auth", "Add auth", PlanOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := UpdatePlanSection(created.Filename, "Observations", "- **Current State**: no auth yet", plan.EditReplace)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result.Revision != 2 {
+		t.Errorf("Expected the edit to be recorded as revision 2, got %d", result.Revision)
+	}
+
+	data, _ := os.ReadFile(created.FullPath)
+	if !strings.Contains(string(data), "## Observations\n- **Current State**: no auth yet\n\n## Proposed Changes") {
+		t.Errorf("Expected updated Observations, got:\n%s", data)
+	}
+	if !strings.HasPrefix(string(data), "---\n") {
+		t.Errorf("Expected frontmatter to be preserved")
+	}
+
+	// Content that would add headings is rejected and the plan is left alone
+	_, err = UpdatePlanSection(created.Filename, "Observations", "notes\n\n## Sneaky\n", plan.EditAppend)
+	if err == nil || !strings.Contains(err.Error(), "headings") {
+		t.Errorf("Expected heading error, got %v", err)
+	}
+	// So is content that breaks the document
+	_, err = UpdatePlanSection(created.Filename, "Step 1", "```go\nunterminated", plan.EditAppend)
+	if err == nil || !strings.Contains(err.Error(), "invalid") {
+		t.Errorf("Expected parse error, got %v", err)
+	}
+	if after, _ := os.ReadFile(created.FullPath); string(after) != string(data) {
+		t.Errorf("Expected rejected edits not to touch the plan")
+	}
+}
+
+func TestPlanStepEdits_UpdateTaskQueue(t *testing.T) {
+	tmpDir := t.TempDir()
+	plansDir := filepath.Join(tmpDir, "opusflow-planning", "plans")
+	if err := os.MkdirAll(plansDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	oldWd, _ := os.Getwd()
+	os.Chdir(tmpDir)
+	defer os.Chdir(oldWd)
+
+	content := "# Gaps\n\n## Implementation Steps\n\n" +
+		"### Step 1: First\n**File**: `a.go`\n\n---\n\n" +
+		"### Step 3: Second\n**File**: `b.go`\n\n---\n\n" +
+		"### Step 7: Third\n**File**: `c.go`\n**Depends on**: Step 1\n\n---\n"
+	planPath := filepath.Join(plansDir, "plan-01-gaps.md")
+	if err := os.WriteFile(planPath, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tq, err := QuickDecomposeFromFile(planPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deps := tq.Tasks[2].Dependencies; len(deps) != 1 || deps[0] != "task-1" {
+		t.Errorf("Expected declared dependency on task-1, got %v", deps)
+	}
+	tq.CompleteTask("task-2")
+	if err := tq.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	check, err := FormatPlanFile("plan-01-gaps.md", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !check.Changed || !strings.Contains(check.Diff, "+### Step 2: Second") {
+		t.Errorf("Expected dry run to report renumbering, got:\n%s", check.Diff)
+	}
+	if data, _ := os.ReadFile(planPath); string(data) != content {
+		t.Errorf("Expected dry run to leave the plan alone")
+	}
+
+	if _, err := FormatPlanFile("plan-01-gaps.md", false); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := AddPlanStep("plan-01-gaps.md", 0, NewStep{Title: "Setup", File: "setup.go", Action: "Create"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !result.TasksUpdated {
+		t.Errorf("Expected task queue to be updated")
+	}
+
+	tq, err = LoadTaskQueue("plan-01-gaps.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, task := range tq.Tasks {
+		got = append(got, task.ID+":"+task.Title+":"+task.Status)
+	}
+	want := "task-1:Setup:pending,task-2:First:pending,task-3:Second:done,task-4:Third:pending"
+	if strings.Join(got, ",") != want {
+		t.Errorf("Expected %s, got %s", want, strings.Join(got, ","))
+	}
+	if deps := tq.Tasks[3].Dependencies; len(deps) != 1 || deps[0] != "task-2" {
+		t.Errorf("Expected Depends on to follow the renumbered step, got %v", deps)
+	}
+
+	if _, err := MovePlanStep("plan-01-gaps.md", 4, 1); err != nil {
+		t.Fatal(err)
+	}
+	tq, _ = LoadTaskQueue("plan-01-gaps.md")
+	if tq.Tasks[0].Title != "Third" || tq.Tasks[3].Title != "Second" || tq.Tasks[3].Status != TaskStatusDone {
+		t.Errorf("Unexpected tasks after move: %+v", tq.Tasks)
+	}
+	if tq.CompletedSteps != 1 || tq.PlanRevision == 0 {
+		t.Errorf("Expected progress and revision to be kept, got %d done, revision %d", tq.CompletedSteps, tq.PlanRevision)
+	}
+}
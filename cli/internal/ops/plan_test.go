@@ -3,6 +3,7 @@ package ops
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -97,7 +98,7 @@ func TestCreatePlan_Integration(t *testing.T) {
 	os.Chdir(tmpDir)
 	defer os.Chdir(oldWd)
 
-	result, err := CreatePlan("Add Authentication", "Implement OAuth2 login")
+	result, err := CreatePlan("Add Authentication", "Implement OAuth2 login", PlanOptions{})
 	if err != nil {
 		t.Fatalf("CreatePlan failed: %v", err)
 	}
@@ -113,4 +114,114 @@ func TestCreatePlan_Integration(t *testing.T) {
 	if _, err := os.Stat(result.FullPath); os.IsNotExist(err) {
 		t.Error("Plan file was not created")
 	}
+
+	// Unknown pre-requisites are left blank for the author, not marked TBD
+	lint, err := LintPlanFile(result.FullPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rules := lintRules(lint); rules[RuleTBD] != 0 {
+		t.Errorf("Expected no TBD pre-requisites in a new plan, got %v", lint.Issues)
+	}
+}
+
+func TestCreatePlan_Templates(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(tmpDir, ".agent"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "go.mod"), []byte("module example.com/x\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	oldWd, _ := os.Getwd()
+	os.Chdir(tmpDir)
+	defer os.Chdir(oldWd)
+
+	result, err := CreatePlan("Fix crash", "Stop crashing on empty config", PlanOptions{Template: "bugfix"})
+	if err != nil {
+		t.Fatalf("CreatePlan failed: %v", err)
+	}
+	content, _ := os.ReadFile(result.FullPath)
+	for _, want := range []string{"# Fix crash", "Add a failing regression test", "`go test -short ./...`", "**Author**:"} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("Expected bugfix plan to contain %q, got:\n%s", want, content)
+		}
+	}
+
+	// Project templates override built-ins
+	templatesDir := filepath.Join(tmpDir, ".opusflow", "templates")
+	if err := os.MkdirAll(templatesDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	custom := "# {{ .Title }}\n\n## Goal\n{{ .Goal }}\n\nTest with {{ .TestCommand }}\n"
+	if err := os.WriteFile(filepath.Join(templatesDir, "plan-feature.md"), []byte(custom), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err = CreatePlan("Add cache", "Cache responses", PlanOptions{})
+	if err != nil {
+		t.Fatalf("CreatePlan failed: %v", err)
+	}
+	content, _ = os.ReadFile(result.FullPath)
+	if !strings.HasSuffix(string(content), "---\n# Add cache\n\n## Goal\nCache responses\n\nTest with go test -short ./...\n") {
+		t.Errorf("Expected project template to be used, got:\n%s", content)
+	}
+
+	if _, err := CreatePlan("Nope", "Nope", PlanOptions{Template: "missing"}); err == nil || !strings.Contains(err.Error(), "available: bugfix, feature, refactor") {
+		t.Errorf("Expected unknown template error, got %v", err)
+	}
+}
+
+func TestCreatePlanFromSpec(t *testing.T) {
+	tmpDir := t.TempDir()
+	specsDir := filepath.Join(tmpDir, "opusflow-planning", "specs")
+	if err := os.MkdirAll(specsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	oldWd, _ := os.Getwd()
+	os.Chdir(tmpDir)
+	defer os.Chdir(oldWd)
+
+	draft := "# Feature Specification: OAuth Login\n\n**Status**: 📝 Draft\n\n" +
+		"## Goal\n\n> Let users sign in with Google\n\n" +
+		"### Functional Requirements\n\n- [ ] **FR1**: Redirect to the provider\n- [ ] **FR2**: Store the token\n\n" +
+		"## Architecture Constraints\n\n- Must use golang.org/x/oauth2\n\n" +
+		"## Success Criteria\n\n- [ ] **SC1**: Login works end to end\n"
+	specPath := filepath.Join(specsDir, "spec-oauth.md")
+	if err := os.WriteFile(specPath, []byte(draft), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := CreatePlanFromSpec("spec-oauth.md", "", PlanOptions{}, false); err == nil || !strings.Contains(err.Error(), "Draft") {
+		t.Fatalf("Expected draft spec to be refused, got %v", err)
+	}
+
+	approved := strings.Replace(draft, "📝 Draft", "✅ Approved", 1)
+	if err := os.WriteFile(specPath, []byte(approved), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := CreatePlanFromSpec("spec-oauth.md", "", PlanOptions{}, false)
+	if err != nil {
+		t.Fatalf("CreatePlanFromSpec failed: %v", err)
+	}
+	if !strings.HasSuffix(result.Filename, "-oauth-login.md") {
+		t.Errorf("Expected title from spec, got %s", result.Filename)
+	}
+
+	content, _ := os.ReadFile(result.FullPath)
+	for _, want := range []string{
+		"**Spec**: [spec-oauth.md](../specs/spec-oauth.md)",
+		"## Goal\nLet users sign in with Google\n",
+		"### Step 1: Redirect to the provider\n**Implements**: FR1\n",
+		"### Step 2: Store the token\n**Implements**: FR2\n",
+		"## Architecture Constraints\n- Must use golang.org/x/oauth2\n",
+		"- [ ] **SC1**: Login works end to end\n",
+	} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("Expected plan to contain %q, got:\n%s", want, content)
+		}
+	}
 }
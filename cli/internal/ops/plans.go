@@ -0,0 +1,386 @@
+package ops
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tuanpep/oplusflow/internal/frontmatter"
+	"github.com/tuanpep/oplusflow/internal/manager"
+	"github.com/tuanpep/oplusflow/internal/plan"
+	"github.com/tuanpep/oplusflow/internal/spec"
+)
+
+// PlanStatusArchived marks a plan moved to the archive folder
+const PlanStatusArchived = "Archived"
+
+var (
+	planIndexPattern    = regexp.MustCompile(`^plan-(\d+)-`)
+	reportStatusPattern = regexp.MustCompile(`(?m)^\*\*Status\*\*:\s*(.*?)\s*$`)
+)
+
+// PlanSummary is one row of the plan inventory
+type PlanSummary struct {
+	Index    int               `json:"index"`
+	Filename string            `json:"filename"`
+	Path     string            `json:"path"`
+	Title    string            `json:"title"`
+	Status   string            `json:"status"`
+	Archived bool              `json:"archived"`
+	Meta     *frontmatter.Meta `json:"meta,omitempty"`
+
+	// Task progress from the plan's task queue, if it was decomposed
+	HasTasks   bool `json:"has_tasks"`
+	TasksDone  int  `json:"tasks_done"`
+	TasksTotal int  `json:"tasks_total"`
+
+	// Most recent verification report, if any
+	Verification *VerificationSummary `json:"verification,omitempty"`
+}
+
+// VerificationSummary describes a saved verification report
+type VerificationSummary struct {
+	Path       string    `json:"path"`
+	Status     string    `json:"status"`
+	VerifiedAt time.Time `json:"verified_at"`
+}
+
+// ListPlans returns every plan in the plans directory, ordered by index.
+// Archived plans are included when includeArchived is set.
+func ListPlans(includeArchived bool) ([]PlanSummary, error) {
+	root, err := manager.FindProjectRoot()
+	if err != nil {
+		return nil, fmt.Errorf("failed to find project root: %w", err)
+	}
+
+	dirs := []string{manager.PlansDir(root)}
+	if includeArchived {
+		dirs = append(dirs, manager.ArchivedPlansDir(root))
+	}
+
+	var plans []PlanSummary
+	for _, dir := range dirs {
+		matches, _ := filepath.Glob(filepath.Join(dir, "plan-*.md"))
+		for _, path := range matches {
+			summary, err := summarizePlan(root, path)
+			if err != nil {
+				return nil, err
+			}
+			plans = append(plans, *summary)
+		}
+	}
+
+	sort.SliceStable(plans, func(i, j int) bool {
+		if plans[i].Index != plans[j].Index {
+			return plans[i].Index < plans[j].Index
+		}
+		return plans[i].Filename < plans[j].Filename
+	})
+
+	return plans, nil
+}
+
+// GetPlanSummary returns the inventory entry for a single plan
+func GetPlanSummary(ref string) (*PlanSummary, error) {
+	root, err := manager.FindProjectRoot()
+	if err != nil {
+		return nil, fmt.Errorf("failed to find project root: %w", err)
+	}
+
+	path, err := ResolvePlanPath(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	return summarizePlan(root, path)
+}
+
+// summarizePlan collects metadata, task progress and verification status for a plan
+func summarizePlan(root, path string) (*PlanSummary, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read plan: %w", err)
+	}
+
+	filename := filepath.Base(path)
+	summary := &PlanSummary{
+		Filename: filename,
+		Path:     path,
+		Archived: filepath.Dir(path) == manager.ArchivedPlansDir(root),
+	}
+
+	if m := planIndexPattern.FindStringSubmatch(filename); m != nil {
+		summary.Index, _ = strconv.Atoi(m[1])
+	}
+
+	// A broken frontmatter block should not hide the plan from the inventory
+	summary.Meta, _, _ = frontmatter.Parse(string(content))
+	if summary.Meta != nil {
+		summary.Status = summary.Meta.Status
+	}
+
+	p, _ := plan.Parse(string(content))
+	summary.Title = p.Title
+	if summary.Title == "" {
+		summary.Title = strings.TrimSuffix(filename, filepath.Ext(filename))
+	}
+
+	if tq, err := LoadTaskQueue(filename); err == nil {
+		summary.HasTasks = true
+		summary.TasksTotal = len(tq.Tasks)
+		for _, t := range tq.Tasks {
+			if t.Status == TaskStatusDone {
+				summary.TasksDone++
+			}
+		}
+	}
+
+	summary.Verification = latestVerification(root, filename)
+
+	return summary, nil
+}
+
+// latestVerification finds the most recent verification report for a plan
+func latestVerification(root, planFilename string) *VerificationSummary {
+	base := strings.TrimSuffix(planFilename, filepath.Ext(planFilename))
+	pattern := filepath.Join(root, "opusflow-planning", "verifications", "verify-"+base+"-*.md")
+	matches, _ := filepath.Glob(pattern)
+
+	var latest *VerificationSummary
+	for _, path := range matches {
+		vs := readVerificationSummary(path)
+		if vs != nil && (latest == nil || vs.VerifiedAt.After(latest.VerifiedAt)) {
+			latest = vs
+		}
+	}
+	return latest
+}
+
+// readVerificationSummary reads a report's status from its frontmatter, or
+// from the "**Status**:" line of reports written before frontmatter existed
+func readVerificationSummary(path string) *VerificationSummary {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+
+	vs := &VerificationSummary{Path: path}
+	if m, _, err := frontmatter.Parse(string(data)); err == nil && m != nil {
+		vs.Status = m.Status
+		vs.VerifiedAt = m.Updated
+	}
+	if vs.Status == "" {
+		if m := reportStatusPattern.FindStringSubmatch(string(data)); m != nil {
+			vs.Status = strings.ToLower(spec.NormalizeStatus(m[1]))
+		}
+	}
+	if vs.VerifiedAt.IsZero() {
+		if info, err := os.Stat(path); err == nil {
+			vs.VerifiedAt = info.ModTime()
+		}
+	}
+
+	return vs
+}
+
+// ArchivePlan moves a plan to the archive folder and marks it Archived.
+// The task queue is keyed by filename, so it keeps working; its plan path is updated.
+func ArchivePlan(ref string) (*PlanSummary, error) {
+	root, err := manager.FindProjectRoot()
+	if err != nil {
+		return nil, fmt.Errorf("failed to find project root: %w", err)
+	}
+
+	path, err := ResolvePlanPath(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	archiveDir := manager.ArchivedPlansDir(root)
+	if filepath.Dir(path) == archiveDir {
+		return nil, fmt.Errorf("plan %s is already archived", filepath.Base(path))
+	}
+
+	// Move before updating the status so a failed move leaves the plan untouched
+	newPath, err := movePlan(path, archiveDir)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := UpdateDocumentMeta(newPath, func(m *frontmatter.Meta) { m.Status = PlanStatusArchived }); err != nil {
+		return nil, err
+	}
+
+	return summarizePlan(root, newPath)
+}
+
+// ReopenPlan moves an archived plan back to the plans directory. Its status is
+// recomputed from the task queue (Draft if it was never decomposed).
+func ReopenPlan(ref string) (*PlanSummary, error) {
+	root, err := manager.FindProjectRoot()
+	if err != nil {
+		return nil, fmt.Errorf("failed to find project root: %w", err)
+	}
+
+	path, err := ResolvePlanPath(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	if filepath.Dir(path) != manager.ArchivedPlansDir(root) {
+		return nil, fmt.Errorf("plan %s is not archived", filepath.Base(path))
+	}
+
+	status := PlanStatusDraft
+	if tq, err := LoadTaskQueue(filepath.Base(path)); err == nil {
+		status = PlanStatusInProgress
+		if tq.allTasksFinished() {
+			status = PlanStatusDone
+		}
+	}
+
+	newPath, err := movePlan(path, manager.PlansDir(root))
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := UpdateDocumentMeta(newPath, func(m *frontmatter.Meta) { m.Status = status }); err != nil {
+		return nil, err
+	}
+
+	return summarizePlan(root, newPath)
+}
+
+// movePlan moves a plan file into dir and points its task queue at the new location
+func movePlan(path, dir string) (string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+
+	filename := filepath.Base(path)
+	newPath := filepath.Join(dir, filename)
+	if _, err := os.Stat(newPath); err == nil {
+		return "", fmt.Errorf("%s already exists", newPath)
+	}
+
+	if err := os.Rename(path, newPath); err != nil {
+		return "", fmt.Errorf("failed to move plan: %w", err)
+	}
+
+	if tq, err := LoadTaskQueue(filename); err == nil {
+		tq.PlanPath = newPath
+		if err := tq.Save(); err != nil {
+			return "", fmt.Errorf("failed to update task queue: %w", err)
+		}
+	}
+
+	return newPath, nil
+}
+
+// FormatPlanTable renders plan summaries as an aligned text table
+func FormatPlanTable(plans []PlanSummary) string {
+	if len(plans) == 0 {
+		return "No plans found.\n"
+	}
+
+	rows := [][]string{{"#", "TITLE", "STATUS", "TASKS", "VERIFIED"}}
+	for _, p := range plans {
+		rows = append(rows, []string{
+			fmt.Sprintf("%02d", p.Index),
+			p.Title,
+			valueOrDash(p.Status),
+			p.taskProgress(),
+			p.verificationLabel(),
+		})
+	}
+
+	widths := make([]int, len(rows[0]))
+	for _, row := range rows {
+		for i, cell := range row {
+			widths[i] = max(widths[i], len([]rune(cell)))
+		}
+	}
+
+	var sb strings.Builder
+	for _, row := range rows {
+		for i, cell := range row {
+			if i == len(row)-1 {
+				sb.WriteString(cell)
+				break
+			}
+			sb.WriteString(cell)
+			sb.WriteString(strings.Repeat(" ", widths[i]-len([]rune(cell))+2))
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// FormatDetails renders a single plan summary for 'plans show'
+func (p *PlanSummary) FormatDetails() string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("# %s\n\n", p.Title))
+	sb.WriteString(fmt.Sprintf("**File**: %s\n", p.Path))
+	sb.WriteString(fmt.Sprintf("**Status**: %s\n", valueOrDash(p.Status)))
+	if p.Meta != nil {
+		if p.Meta.Owner != "" {
+			sb.WriteString(fmt.Sprintf("**Owner**: %s\n", p.Meta.Owner))
+		}
+		if len(p.Meta.Tags) > 0 {
+			sb.WriteString(fmt.Sprintf("**Tags**: %s\n", strings.Join(p.Meta.Tags, ", ")))
+		}
+		if p.Meta.SpecRef != "" {
+			sb.WriteString(fmt.Sprintf("**Spec**: %s\n", p.Meta.SpecRef))
+		}
+		if !p.Meta.Updated.IsZero() {
+			sb.WriteString(fmt.Sprintf("**Updated**: %s\n", p.Meta.Updated.Format("2006-01-02 15:04")))
+		}
+	}
+	sb.WriteString(fmt.Sprintf("**Tasks**: %s\n", p.taskProgress()))
+	sb.WriteString(fmt.Sprintf("**Last Verification**: %s\n", p.verificationLabel()))
+	if p.Verification != nil {
+		sb.WriteString(fmt.Sprintf("**Report**: %s\n", p.Verification.Path))
+	}
+
+	return sb.String()
+}
+
+// FormatPlansJSON returns plan summaries as indented JSON
+func FormatPlansJSON(plans []PlanSummary) (string, error) {
+	if plans == nil {
+		plans = []PlanSummary{}
+	}
+	data, err := json.MarshalIndent(plans, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+func (p *PlanSummary) taskProgress() string {
+	if !p.HasTasks {
+		return "-"
+	}
+	return fmt.Sprintf("%d/%d", p.TasksDone, p.TasksTotal)
+}
+
+func (p *PlanSummary) verificationLabel() string {
+	if p.Verification == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%s (%s)", valueOrDash(p.Verification.Status), p.Verification.VerifiedAt.Format("2006-01-02"))
+}
+
+func valueOrDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,142 @@
+package ops
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPlanLifecycle(t *testing.T) {
+	tmpDir := t.TempDir()
+	plansDir := filepath.Join(tmpDir, "opusflow-planning", "plans")
+	verifyDir := filepath.Join(tmpDir, "opusflow-planning", "verifications")
+	for _, d := range []string{plansDir, verifyDir} {
+		if err := os.MkdirAll(d, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	oldWd, _ := os.Getwd()
+	os.Chdir(tmpDir)
+	defer os.Chdir(oldWd)
+
+	first, err := CreatePlan("Add auth", "Add auth", PlanOptions{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	second, err := CreatePlan("Fix crash", "Fix crash", PlanOptions{Template: "bugfix"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	tq, err := DecomposePlan(first.FullPath)
+	if err != nil {
+		t.Fatalf("Failed to decompose: %v", err)
+	}
+	tq.Tasks[0].Status = TaskStatusDone
+	tq.Tasks = append(tq.Tasks, Task{ID: "task-extra", Title: "Follow-up", Status: TaskStatusPending})
+	if err := tq.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	report := "# Verification Report\n\n**Status**: ❌ Failed\n"
+	if err := os.WriteFile(filepath.Join(verifyDir, "verify-plan-01-add-auth-2026-01-10.md"), []byte(report), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	plans, err := ListPlans(false)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(plans) != 2 || plans[0].Index != 1 || plans[1].Index != 2 {
+		t.Fatalf("Expected plans 01 and 02, got %+v", plans)
+	}
+	if plans[0].Title != "Add auth" || plans[0].Status != PlanStatusInProgress {
+		t.Errorf("Unexpected plan summary: %+v", plans[0])
+	}
+	if !plans[0].HasTasks || plans[0].TasksDone != 1 || plans[0].TasksTotal != len(tq.Tasks) {
+		t.Errorf("Unexpected task progress: %d/%d", plans[0].TasksDone, plans[0].TasksTotal)
+	}
+	if plans[0].Verification == nil || plans[0].Verification.Status != "failed" {
+		t.Errorf("Expected last verification 'failed', got %+v", plans[0].Verification)
+	}
+	if plans[1].HasTasks || plans[1].Verification != nil {
+		t.Errorf("Expected no tasks or verification for plan 02, got %+v", plans[1])
+	}
+
+	table := FormatPlanTable(plans)
+	for _, want := range []string{"TITLE", "Add auth", "In Progress", "1/", "failed"} {
+		if !strings.Contains(table, want) {
+			t.Errorf("Expected table to contain %q, got:\n%s", want, table)
+		}
+	}
+
+	// Archive keeps the task queue usable
+	archived, err := ArchivePlan(first.Filename)
+	if err != nil {
+		t.Fatalf("Failed to archive: %v", err)
+	}
+	if !archived.Archived || archived.Status != PlanStatusArchived {
+		t.Errorf("Expected archived plan, got %+v", archived)
+	}
+	if _, err := os.Stat(first.FullPath); !os.IsNotExist(err) {
+		t.Errorf("Expected plan to be moved out of the plans directory")
+	}
+	if _, err := ArchivePlan(first.Filename); err == nil {
+		t.Errorf("Expected error archiving an archived plan")
+	}
+
+	loaded, err := LoadTaskQueue(first.Filename)
+	if err != nil {
+		t.Fatalf("Expected task queue to survive archiving: %v", err)
+	}
+	if loaded.PlanPath != archived.Path {
+		t.Errorf("Expected task queue plan path %s, got %s", archived.Path, loaded.PlanPath)
+	}
+	if err := loaded.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if m, _ := ReadDocumentMeta(archived.Path); m == nil || m.Status != PlanStatusArchived {
+		t.Errorf("Expected task updates not to overwrite Archived status, got %+v", m)
+	}
+
+	if plans, _ := ListPlans(false); len(plans) != 1 || plans[0].Filename != second.Filename {
+		t.Errorf("Expected only the active plan, got %+v", plans)
+	}
+	if plans, _ := ListPlans(true); len(plans) != 2 {
+		t.Errorf("Expected archived plan with --all, got %+v", plans)
+	}
+
+	// New plans do not reuse an archived plan's index
+	third, err := CreatePlan("Next", "Next", PlanOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(third.Filename, "plan-03-") {
+		t.Errorf("Expected plan-03, got %s", third.Filename)
+	}
+
+	reopened, err := ReopenPlan(first.Filename)
+	if err != nil {
+		t.Fatalf("Failed to reopen: %v", err)
+	}
+	if reopened.Archived || reopened.Path != first.FullPath || reopened.Status != PlanStatusInProgress {
+		t.Errorf("Unexpected reopened plan: %+v", reopened)
+	}
+	if _, err := ReopenPlan(first.Filename); err == nil {
+		t.Errorf("Expected error reopening an active plan")
+	}
+
+	// A failed move leaves the plan's status untouched
+	conflict := filepath.Join(plansDir, "archive", second.Filename)
+	if err := os.WriteFile(conflict, []byte("# Old\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ArchivePlan(second.Filename); err == nil {
+		t.Fatalf("Expected archiving onto an existing file to fail")
+	}
+	if m, _ := ReadDocumentMeta(second.FullPath); m == nil || m.Status == PlanStatusArchived {
+		t.Errorf("Expected the plan not to be marked Archived, got %+v", m)
+	}
+}
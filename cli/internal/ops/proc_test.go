@@ -0,0 +1,45 @@
+package ops
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestShellQuote(t *testing.T) {
+	// The quoted path must reach the command as one argument, unquoted
+	dir := filepath.Join(t.TempDir(), "it's a dir")
+	if out, err := shellCommand(context.Background(), "mkdir "+shellQuote(dir)).CombinedOutput(); err != nil {
+		t.Fatalf("mkdir failed: %v\n%s", err, out)
+	}
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		t.Errorf("Expected %s to be created, got %v", dir, err)
+	}
+}
+
+func TestIsScriptCheck(t *testing.T) {
+	windows := runtime.GOOS == "windows"
+	tests := []struct {
+		name string
+		mode os.FileMode
+		want bool
+	}{
+		{"lint.sh", 0644, true},
+		{"lint", 0755, !windows},
+		{"README.md", 0644, false},
+		{"notes.txt", 0755, !windows},
+		{"lint.ps1", 0644, windows},
+		{"lint.CMD", 0644, windows},
+		{"lint.exe", 0644, windows},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isScriptCheck(tt.name, tt.mode); got != tt.want {
+				t.Errorf("isScriptCheck(%s, %v) = %v; want %v", tt.name, tt.mode, got, tt.want)
+			}
+		})
+	}
+}
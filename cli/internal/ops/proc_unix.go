@@ -0,0 +1,46 @@
+//go:build !windows
+
+package ops
+
+import (
+	"context"
+	"io/fs"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"syscall"
+)
+
+// shellCommand runs script through sh
+func shellCommand(ctx context.Context, script string) *exec.Cmd {
+	return exec.CommandContext(ctx, "sh", "-c", script)
+}
+
+// shellQuote quotes s as a single sh word
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// isScriptCheck reports whether a file in the checks directory is a check:
+// .sh scripts and files with an executable bit
+func isScriptCheck(name string, mode fs.FileMode) bool {
+	return filepath.Ext(name) == ".sh" || mode&0111 != 0
+}
+
+// scriptCommand runs a script check; .sh scripts go through sh so they need
+// no executable bit
+func scriptCommand(ctx context.Context, path string) *exec.Cmd {
+	if filepath.Ext(path) == ".sh" {
+		return exec.CommandContext(ctx, "sh", path)
+	}
+	return exec.CommandContext(ctx, path)
+}
+
+// killProcessGroupOnCancel runs cmd in its own process group so that a
+// timeout kills the shell together with everything it started
+func killProcessGroupOnCancel(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+}
@@ -0,0 +1,50 @@
+//go:build windows
+
+package ops
+
+import (
+	"context"
+	"io/fs"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// shellCommand runs script through cmd.exe
+func shellCommand(ctx context.Context, script string) *exec.Cmd {
+	return exec.CommandContext(ctx, "cmd", "/C", script)
+}
+
+// shellQuote quotes s as a single cmd.exe word; Windows paths cannot contain
+// double quotes
+func shellQuote(s string) string {
+	return `"` + s + `"`
+}
+
+// isScriptCheck reports whether a file in the checks directory is a check.
+// Windows has no executable bit, so checks are picked by extension: .exe,
+// .bat, .cmd, .ps1, and .sh when an sh (such as Git Bash's) is on the PATH.
+func isScriptCheck(name string, mode fs.FileMode) bool {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".exe", ".bat", ".cmd", ".ps1", ".sh":
+		return true
+	}
+	return false
+}
+
+// scriptCommand runs a script check with the interpreter its extension needs
+func scriptCommand(ctx context.Context, path string) *exec.Cmd {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".sh":
+		return exec.CommandContext(ctx, "sh", path)
+	case ".ps1":
+		return exec.CommandContext(ctx, "powershell", "-NoProfile", "-ExecutionPolicy", "Bypass", "-File", path)
+	case ".bat", ".cmd":
+		return exec.CommandContext(ctx, "cmd", "/C", path)
+	}
+	return exec.CommandContext(ctx, path)
+}
+
+// killProcessGroupOnCancel is a no-op on Windows, where only the command
+// itself is killed on timeout
+func killProcessGroupOnCancel(cmd *exec.Cmd) {}
@@ -0,0 +1,172 @@
+package ops
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ProjectCommands are the build, test, lint and typecheck commands for a project
+type ProjectCommands struct {
+	Build     string `json:"build,omitempty"`
+	Test      string `json:"test,omitempty"`
+	Lint      string `json:"lint,omitempty"`
+	Typecheck string `json:"typecheck,omitempty"`
+}
+
+// Get returns the command of the given kind
+func (pc ProjectCommands) Get(kind string) string {
+	switch kind {
+	case CommandBuild:
+		return pc.Build
+	case CommandTest:
+		return pc.Test
+	case CommandLint:
+		return pc.Lint
+	case CommandTypecheck:
+		return pc.Typecheck
+	}
+	return ""
+}
+
+// DetectProjectCommands guesses project commands from the manifest files in
+// root (go.mod, Cargo.toml, package.json, pyproject.toml, Makefile). Only
+// manifests are consulted, never the tools installed on PATH. The first
+// manifest providing a command wins.
+func DetectProjectCommands(root string) ProjectCommands {
+	var cmds ProjectCommands
+
+	detectors := []func(string) ProjectCommands{
+		detectGoCommands,
+		detectCargoCommands,
+		detectNodeCommands,
+		detectPythonCommands,
+		detectMakeCommands,
+	}
+
+	for _, detect := range detectors {
+		found := detect(root)
+		if cmds.Build == "" {
+			cmds.Build = found.Build
+		}
+		if cmds.Test == "" {
+			cmds.Test = found.Test
+		}
+		if cmds.Lint == "" {
+			cmds.Lint = found.Lint
+		}
+		if cmds.Typecheck == "" {
+			cmds.Typecheck = found.Typecheck
+		}
+	}
+
+	return cmds
+}
+
+func manifestExists(root, name string) bool {
+	info, err := os.Stat(filepath.Join(root, name))
+	return err == nil && !info.IsDir()
+}
+
+func detectGoCommands(root string) ProjectCommands {
+	if !manifestExists(root, "go.mod") {
+		return ProjectCommands{}
+	}
+	// -short keeps verification fast, skipping tests that opt out of short mode
+	return ProjectCommands{Build: "go build ./...", Test: "go test -short ./...", Lint: "go vet ./..."}
+}
+
+func detectCargoCommands(root string) ProjectCommands {
+	if !manifestExists(root, "Cargo.toml") {
+		return ProjectCommands{}
+	}
+	return ProjectCommands{Build: "cargo build", Test: "cargo test", Typecheck: "cargo check"}
+}
+
+func detectNodeCommands(root string) ProjectCommands {
+	data, err := os.ReadFile(filepath.Join(root, "package.json"))
+	if err != nil {
+		return ProjectCommands{}
+	}
+
+	var pkg struct {
+		Scripts map[string]string `json:"scripts"`
+	}
+	if err := json.Unmarshal(data, &pkg); err != nil {
+		return ProjectCommands{}
+	}
+
+	// Use the package manager the lockfile belongs to
+	runner := "npm"
+	switch {
+	case manifestExists(root, "pnpm-lock.yaml"):
+		runner = "pnpm"
+	case manifestExists(root, "yarn.lock"):
+		runner = "yarn"
+	}
+
+	var cmds ProjectCommands
+	if _, ok := pkg.Scripts["build"]; ok {
+		cmds.Build = runner + " run build"
+	}
+	if _, ok := pkg.Scripts["test"]; ok {
+		cmds.Test = runner + " test"
+	}
+	if _, ok := pkg.Scripts["lint"]; ok {
+		cmds.Lint = runner + " run lint"
+	}
+	for _, script := range []string{"typecheck", "type-check", "check-types"} {
+		if _, ok := pkg.Scripts[script]; ok {
+			cmds.Typecheck = runner + " run " + script
+			break
+		}
+	}
+	if cmds.Typecheck == "" && manifestExists(root, "tsconfig.json") {
+		cmds.Typecheck = "npx tsc --noEmit"
+	}
+	return cmds
+}
+
+func detectPythonCommands(root string) ProjectCommands {
+	if !manifestExists(root, "pyproject.toml") && !manifestExists(root, "setup.py") {
+		return ProjectCommands{}
+	}
+
+	// Linters and type checkers only run when the project configures them
+	pyproject, _ := os.ReadFile(filepath.Join(root, "pyproject.toml"))
+	cmds := ProjectCommands{Test: "pytest"}
+	if manifestExists(root, "ruff.toml") || strings.Contains(string(pyproject), "[tool.ruff") {
+		cmds.Lint = "ruff check ."
+	}
+	if manifestExists(root, "mypy.ini") || strings.Contains(string(pyproject), "[tool.mypy") {
+		cmds.Typecheck = "mypy ."
+	}
+	return cmds
+}
+
+func detectMakeCommands(root string) ProjectCommands {
+	f, err := os.Open(filepath.Join(root, "Makefile"))
+	if err != nil {
+		return ProjectCommands{}
+	}
+	defer f.Close()
+
+	var cmds ProjectCommands
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "build:"):
+			cmds.Build = "make build"
+		case strings.HasPrefix(line, "test:"):
+			cmds.Test = "make test"
+		case strings.HasPrefix(line, "lint:"):
+			cmds.Lint = "make lint"
+		case strings.HasPrefix(line, "typecheck:"):
+			cmds.Typecheck = "make typecheck"
+		}
+	}
+	return cmds
+}
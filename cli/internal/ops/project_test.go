@@ -0,0 +1,50 @@
+package ops
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDetectProjectCommands(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  ProjectCommands
+	}{
+		{"empty", nil, ProjectCommands{}},
+		{"go", map[string]string{"go.mod": "module x\n"}, ProjectCommands{Build: "go build ./...", Test: "go test -short ./...", Lint: "go vet ./..."}},
+		{"node scripts", map[string]string{"package.json": `{"scripts": {"test": "jest"}}`}, ProjectCommands{Test: "npm test"}},
+		{"node lint and typecheck", map[string]string{
+			"package.json":   `{"scripts": {"test": "vitest", "lint": "eslint .", "typecheck": "tsc"}}`,
+			"pnpm-lock.yaml": "",
+		}, ProjectCommands{Test: "pnpm test", Lint: "pnpm run lint", Typecheck: "pnpm run typecheck"}},
+		{"typescript without script", map[string]string{
+			"package.json":  `{"scripts": {"build": "tsc -p ."}}`,
+			"tsconfig.json": "{}",
+		}, ProjectCommands{Build: "npm run build", Typecheck: "npx tsc --noEmit"}},
+		{"cargo", map[string]string{"Cargo.toml": "[package]\n"}, ProjectCommands{Build: "cargo build", Test: "cargo test", Typecheck: "cargo check"}},
+		{"makefile fills gaps", map[string]string{
+			"package.json": `{"scripts": {"test": "jest"}}`,
+			"Makefile":     "build:\n\tgo build\ntest:\n\tgo test\n",
+		}, ProjectCommands{Build: "make build", Test: "npm test"}},
+		{"python", map[string]string{"pyproject.toml": "[project]\n"}, ProjectCommands{Test: "pytest"}},
+		{"python tools", map[string]string{"pyproject.toml": "[tool.ruff]\n[tool.mypy]\n"}, ProjectCommands{Test: "pytest", Lint: "ruff check .", Typecheck: "mypy ."}},
+		{"makefile lint", map[string]string{"Makefile": "lint:\n\tgolangci-lint run\n"}, ProjectCommands{Lint: "make lint"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, content := range tt.files {
+				if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if got := DetectProjectCommands(dir); got != tt.want {
+				t.Errorf("DetectProjectCommands() = %+v; want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -2,13 +2,24 @@ package ops
 
 import (
 	"fmt"
+	"os"
 	"path/filepath"
+	"strings"
+
+	"github.com/tuanpep/oplusflow/internal/plan"
 )
 
 func GeneratePrompt(action, file string) (string, error) {
-	// Ideally we resolve full path or relative to project root
-	// For now we assume standard paths
+	prompt, err := basePrompt(action, file)
+	if err != nil {
+		return "", err
+	}
 
+	return prompt + planPromptContext(action, file), nil
+}
+
+// basePrompt returns the persona prompt for an action
+func basePrompt(action, file string) (string, error) {
 	switch action {
 	case "plan":
 		return fmt.Sprintf(`You are The Commander - an expert software architect and technical project manager.
@@ -31,6 +42,7 @@ TOOLS TO USE:
 1.  `+"`opusflow map --compact`"+`: To get a high-level overview of the project structure.
 2.  `+"`search_codebase`"+`: To find specific calls, definitions, or patterns.
 3.  `+"`list_files`"+`: To explore specific directories.
+4.  `+"`update_plan_section`"+`: To write your findings into the plan without rewriting the whole document.
 
 DELIVERABLE:
 Update the 'Observations' section of the plan with:
@@ -67,3 +79,43 @@ Use the `+"`verify`"+` tool to run automated checks. Report any issues found.`,
 		return "", fmt.Errorf("unknown action: %s", action)
 	}
 }
+
+// planPromptContext summarizes the parsed plan (if it exists yet) so the agent
+// starts from its structure rather than re-reading the document.
+func planPromptContext(action, file string) string {
+	path, err := ResolvePlanPath(file)
+	if err != nil {
+		return ""
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+
+	p, err := plan.Parse(string(content))
+	if err != nil {
+		return fmt.Sprintf("\n\nWARNING: The plan has structural problems. Fix them first:\n%v\n", err)
+	}
+
+	var sb strings.Builder
+	if p.Goal != "" {
+		sb.WriteString(fmt.Sprintf("\n\nPLAN GOAL:\n%s\n", p.Goal))
+	}
+
+	// The plan prompt is about writing the steps, so only the goal is useful there
+	if action == "plan" || len(p.Steps) == 0 {
+		return sb.String()
+	}
+
+	sb.WriteString("\nPLAN STEPS:\n")
+	for _, step := range p.Steps {
+		sb.WriteString(fmt.Sprintf("%d. %s", step.Number, step.Title))
+		if len(step.Files) > 0 {
+			sb.WriteString(fmt.Sprintf(" (%s)", strings.Join(step.Files, ", ")))
+		}
+		sb.WriteString("\n")
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,365 @@
+package ops
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/tuanpep/oplusflow/internal/frontmatter"
+	"github.com/tuanpep/oplusflow/internal/manager"
+)
+
+// Revision reasons recorded with plan snapshots
+const (
+	RevisionReasonDecompose   = "decompose"
+	RevisionReasonExec        = "exec"
+	RevisionReasonWriteFile   = "write_file"
+	RevisionReasonBeforeWrite = "before write_file"
+	RevisionReasonSectionEdit = "update_plan_section"
+	RevisionReasonFormat      = "plan fmt"
+	RevisionReasonStepAdd     = "plan step add"
+	RevisionReasonStepMove    = "plan step move"
+)
+
+// PlanRevision is a snapshot of a plan's content at a point in time
+type PlanRevision struct {
+	Number    int       `json:"number"`
+	Hash      string    `json:"hash"`
+	Reason    string    `json:"reason"`
+	CreatedAt time.Time `json:"created_at"`
+	Lines     int       `json:"lines"`
+}
+
+// planRevisionDir returns where the snapshots of a plan are kept. Revisions are
+// keyed by plan filename so they survive archiving.
+func planRevisionDir(root, planRef string) string {
+	base := strings.TrimSuffix(filepath.Base(planRef), filepath.Ext(planRef))
+	return filepath.Join(manager.RevisionsDir(root), base)
+}
+
+func revisionFile(dir string, n int) string {
+	return filepath.Join(dir, fmt.Sprintf("rev-%03d.md", n))
+}
+
+// SnapshotPlan records the plan's current content as a new revision. If the
+// content is unchanged since the latest revision, that revision is returned.
+// Frontmatter is ignored when comparing, so status and timestamp updates do
+// not create revisions.
+func SnapshotPlan(planPath, reason string) (*PlanRevision, error) {
+	root, err := manager.FindProjectRoot()
+	if err != nil {
+		return nil, fmt.Errorf("failed to find project root: %w", err)
+	}
+
+	content, err := os.ReadFile(planPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read plan: %w", err)
+	}
+
+	dir := planRevisionDir(root, planPath)
+	revisions, err := loadPlanRevisions(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	hash := contentHash(content)
+	if n := len(revisions); n > 0 && revisions[n-1].Hash == hash {
+		return &revisions[n-1], nil
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create revisions directory: %w", err)
+	}
+
+	rev := PlanRevision{
+		Number:    len(revisions) + 1,
+		Hash:      hash,
+		Reason:    reason,
+		CreatedAt: time.Now(),
+		Lines:     strings.Count(string(content), "\n"),
+	}
+	if err := os.WriteFile(revisionFile(dir, rev.Number), content, 0644); err != nil {
+		return nil, fmt.Errorf("failed to write revision: %w", err)
+	}
+
+	revisions = append(revisions, rev)
+	data, err := json.MarshalIndent(revisions, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal revisions: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "index.json"), data, 0644); err != nil {
+		return nil, fmt.Errorf("failed to write revision index: %w", err)
+	}
+
+	return &rev, nil
+}
+
+// SnapshotIfPlan snapshots path when it is a plan file inside the plans
+// directory (or its archive); other files are ignored
+func SnapshotIfPlan(path, reason string) (*PlanRevision, error) {
+	root, err := manager.FindProjectRoot()
+	if err != nil {
+		return nil, fmt.Errorf("failed to find project root: %w", err)
+	}
+
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(root, path)
+	}
+	dir := filepath.Dir(filepath.Clean(path))
+	base := filepath.Base(path)
+	if !strings.HasPrefix(base, "plan-") || filepath.Ext(base) != ".md" ||
+		(dir != manager.PlansDir(root) && dir != manager.ArchivedPlansDir(root)) {
+		return nil, nil
+	}
+	if _, err := os.Stat(path); err != nil {
+		return nil, nil
+	}
+
+	return SnapshotPlan(path, reason)
+}
+
+// ListPlanRevisions returns the recorded revisions of a plan, oldest first
+func ListPlanRevisions(planRef string) ([]PlanRevision, error) {
+	root, err := manager.FindProjectRoot()
+	if err != nil {
+		return nil, fmt.Errorf("failed to find project root: %w", err)
+	}
+	return loadPlanRevisions(planRevisionDir(root, planRef))
+}
+
+// ReadPlanRevision returns the content of revision n of a plan
+func ReadPlanRevision(planRef string, n int) (string, error) {
+	root, err := manager.FindProjectRoot()
+	if err != nil {
+		return "", fmt.Errorf("failed to find project root: %w", err)
+	}
+
+	data, err := os.ReadFile(revisionFile(planRevisionDir(root, planRef), n))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", fmt.Errorf("revision %d of %s not found", n, filepath.Base(planRef))
+		}
+		return "", fmt.Errorf("failed to read revision: %w", err)
+	}
+	return string(data), nil
+}
+
+// DiffPlanRevision returns a unified diff of the changes introduced by
+// revision rev. With rev 0 it diffs the latest revision against the plan as
+// it is now.
+func DiffPlanRevision(planRef string, rev int) (string, error) {
+	revisions, err := ListPlanRevisions(planRef)
+	if err != nil {
+		return "", err
+	}
+	if len(revisions) == 0 {
+		return "", fmt.Errorf("no revisions recorded for %s", filepath.Base(planRef))
+	}
+
+	name := filepath.Base(planRef)
+	if rev == 0 {
+		latest := revisions[len(revisions)-1].Number
+		before, err := ReadPlanRevision(planRef, latest)
+		if err != nil {
+			return "", err
+		}
+		planPath, err := ResolvePlanPath(planRef)
+		if err != nil {
+			return "", err
+		}
+		after, err := ReadFile(planPath)
+		if err != nil {
+			return "", fmt.Errorf("failed to read plan: %w", err)
+		}
+		return UnifiedDiff(before, after, fmt.Sprintf("%s@rev%d", name, latest), name+" (current)"), nil
+	}
+
+	if rev < 1 || rev > len(revisions) {
+		return "", fmt.Errorf("revision %d of %s not found (have 1-%d)", rev, name, len(revisions))
+	}
+
+	after, err := ReadPlanRevision(planRef, rev)
+	if err != nil {
+		return "", err
+	}
+	before := ""
+	if rev > 1 {
+		if before, err = ReadPlanRevision(planRef, rev-1); err != nil {
+			return "", err
+		}
+	}
+	return UnifiedDiff(before, after, fmt.Sprintf("%s@rev%d", name, rev-1), fmt.Sprintf("%s@rev%d", name, rev)), nil
+}
+
+// FormatPlanHistory renders a plan's revisions. decomposedFrom marks the
+// revision the task queue was built from (0 if unknown).
+func FormatPlanHistory(planRef string, revisions []PlanRevision, decomposedFrom int) string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("# Plan History: %s\n\n", filepath.Base(planRef)))
+	if len(revisions) == 0 {
+		sb.WriteString("No revisions recorded yet. Revisions are taken on decompose, exec and MCP write_file.\n")
+		return sb.String()
+	}
+
+	for _, r := range revisions {
+		marker := ""
+		if r.Number == decomposedFrom {
+			marker = "  ← task queue"
+		}
+		sb.WriteString(fmt.Sprintf("- **rev %d** %s  %-18s %d lines  %s%s\n",
+			r.Number, r.CreatedAt.Format("2006-01-02 15:04"), r.Reason, r.Lines, r.Hash, marker))
+	}
+	return sb.String()
+}
+
+func loadPlanRevisions(dir string) ([]PlanRevision, error) {
+	data, err := os.ReadFile(filepath.Join(dir, "index.json"))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read revision index: %w", err)
+	}
+
+	var revisions []PlanRevision
+	if err := json.Unmarshal(data, &revisions); err != nil {
+		return nil, fmt.Errorf("failed to parse revision index: %w", err)
+	}
+	return revisions, nil
+}
+
+// contentHash hashes a document's body, excluding its frontmatter
+func contentHash(content []byte) string {
+	_, body, _, _ := frontmatter.Split(string(content))
+	sum := sha256.Sum256([]byte(body))
+	return hex.EncodeToString(sum[:])[:12]
+}
+
+// UnifiedDiff returns a line-based unified diff of a and b with three lines of
+// context. It returns an empty string when the inputs are identical.
+func UnifiedDiff(a, b, fromName, toName string) string {
+	if a == b {
+		return ""
+	}
+
+	ops := diffLines(splitLines(a), splitLines(b))
+
+	const context = 3
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("--- %s\n+++ %s\n", fromName, toName))
+
+	for i := 0; i < len(ops); {
+		// Find the next change
+		for i < len(ops) && ops[i].kind == ' ' {
+			i++
+		}
+		if i == len(ops) {
+			break
+		}
+
+		// Extend the hunk while changes are within 2*context lines of each other
+		start := max(i-context, 0)
+		end := i
+		for end < len(ops) {
+			if ops[end].kind != ' ' {
+				end++
+				continue
+			}
+			run := end
+			for run < len(ops) && ops[run].kind == ' ' {
+				run++
+			}
+			if run == len(ops) || run-end > 2*context {
+				end = min(end+context, len(ops))
+				break
+			}
+			end = run
+		}
+
+		oldStart, newStart, oldCount, newCount := ops[start].oldLine, ops[start].newLine, 0, 0
+		for _, op := range ops[start:end] {
+			if op.kind != '+' {
+				oldCount++
+			}
+			if op.kind != '-' {
+				newCount++
+			}
+		}
+		sb.WriteString(fmt.Sprintf("@@ -%s +%s @@\n", hunkRange(oldStart, oldCount), hunkRange(newStart, newCount)))
+		for _, op := range ops[start:end] {
+			sb.WriteString(fmt.Sprintf("%c%s\n", op.kind, op.text))
+		}
+
+		i = end
+	}
+
+	return sb.String()
+}
+
+// diffOp is one line of an edit script; oldLine/newLine are the 1-based
+// positions the line has (or would have) in each input
+type diffOp struct {
+	kind    byte // ' ', '-' or '+'
+	text    string
+	oldLine int
+	newLine int
+}
+
+// diffLines computes a minimal edit script using the longest common subsequence
+func diffLines(a, b []string) []diffOp {
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var ops []diffOp
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			ops = append(ops, diffOp{' ', a[i], i + 1, j + 1})
+			i++
+			j++
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			ops = append(ops, diffOp{'-', a[i], i + 1, j + 1})
+			i++
+		default:
+			ops = append(ops, diffOp{'+', b[j], i + 1, j + 1})
+			j++
+		}
+	}
+	return ops
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+// hunkRange formats a "start,count" hunk range; empty ranges start one line earlier
+func hunkRange(start, count int) string {
+	if count == 0 {
+		start--
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", start)
+	}
+	return fmt.Sprintf("%d,%d", start, count)
+}
@@ -0,0 +1,124 @@
+package ops
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want string
+	}{
+		{
+			name: "identical",
+			a:    "a\nb\n",
+			b:    "a\nb\n",
+			want: "",
+		},
+		{
+			name: "changed line",
+			a:    "a\nb\nc\n",
+			b:    "a\nB\nc\n",
+			want: "--- old\n+++ new\n@@ -1,3 +1,3 @@\n a\n-b\n+B\n c\n",
+		},
+		{
+			name: "from empty",
+			a:    "",
+			b:    "x\ny\n",
+			want: "--- old\n+++ new\n@@ -0,0 +1,2 @@\n+x\n+y\n",
+		},
+		{
+			name: "separate hunks",
+			a:    "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n",
+			b:    "one\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\ntwelve\n",
+			want: "--- old\n+++ new\n@@ -1,4 +1,4 @@\n-1\n+one\n 2\n 3\n 4\n@@ -9,4 +9,4 @@\n 9\n 10\n 11\n-12\n+twelve\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := UnifiedDiff(tt.a, tt.b, "old", "new"); got != tt.want {
+				t.Errorf("UnifiedDiff() =\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlanRevisions(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(tmpDir, "opusflow-planning", "plans"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	oldWd, _ := os.Getwd()
+	os.Chdir(tmpDir)
+	defer os.Chdir(oldWd)
+
+	result, err := CreatePlan("Add auth", "Add auth", PlanOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tq, err := QuickDecomposeFromFile(result.FullPath)
+	if err != nil {
+		t.Fatalf("Failed to decompose: %v", err)
+	}
+	if tq.PlanRevision != 1 {
+		t.Errorf("Expected task queue to record revision 1, got %d", tq.PlanRevision)
+	}
+
+	// Unchanged content does not create a revision
+	if rev, err := SnapshotPlan(result.FullPath, RevisionReasonExec); err != nil || rev.Number != 1 {
+		t.Errorf("Expected revision 1 to be reused, got %+v (%v)", rev, err)
+	}
+
+	original, _ := os.ReadFile(result.FullPath)
+	edited := strings.Replace(string(original), "## Observations", "## Observations\n- Found the login handler", 1)
+	if err := WriteFile(result.FullPath, edited); err != nil {
+		t.Fatal(err)
+	}
+
+	diff, err := DiffPlanRevision(result.Filename, 0)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.Contains(diff, "+- Found the login handler") {
+		t.Errorf("Expected working diff to show the edit, got:\n%s", diff)
+	}
+
+	rev, err := SnapshotIfPlan(filepath.Join("opusflow-planning", "plans", result.Filename), RevisionReasonWriteFile)
+	if err != nil || rev == nil || rev.Number != 2 {
+		t.Fatalf("Expected revision 2, got %+v (%v)", rev, err)
+	}
+	if rev, err := SnapshotIfPlan("README.md", RevisionReasonWriteFile); rev != nil || err != nil {
+		t.Errorf("Expected non-plan files to be ignored, got %+v (%v)", rev, err)
+	}
+
+	revisions, err := ListPlanRevisions(result.Filename)
+	if err != nil || len(revisions) != 2 {
+		t.Fatalf("Expected 2 revisions, got %+v (%v)", revisions, err)
+	}
+	if revisions[0].Reason != RevisionReasonDecompose || revisions[1].Reason != RevisionReasonWriteFile {
+		t.Errorf("Unexpected reasons: %+v", revisions)
+	}
+
+	diff, err = DiffPlanRevision(result.Filename, 2)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.Contains(diff, "@rev1") || !strings.Contains(diff, "+- Found the login handler") {
+		t.Errorf("Expected rev 2 diff against rev 1, got:\n%s", diff)
+	}
+	if _, err := DiffPlanRevision(result.Filename, 5); err == nil {
+		t.Errorf("Expected error for unknown revision")
+	}
+
+	history := FormatPlanHistory(result.Filename, revisions, tq.PlanRevision)
+	if !strings.Contains(history, "rev 1") || !strings.Contains(history, "← task queue") {
+		t.Errorf("Unexpected history:\n%s", history)
+	}
+}
@@ -1,13 +1,20 @@
 package ops
 
 import (
+	"bytes"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
+	"text/template"
 	"time"
+	"unicode/utf8"
 
+	"github.com/tuanpep/oplusflow/internal/frontmatter"
 	"github.com/tuanpep/oplusflow/internal/manager"
+	"github.com/tuanpep/oplusflow/internal/spec"
+	"github.com/tuanpep/oplusflow/internal/templates"
 )
 
 // SpecResult contains the result of creating a spec
@@ -16,22 +23,48 @@ type SpecResult struct {
 	Filename string
 	FullPath string
 	Query    string
+	Type     string
+}
+
+// SpecOptions are optional settings for CreateSpec
+type SpecOptions struct {
+	// Type is the spec template (work type); defaults to templates.DefaultSpecTemplate
+	Type string
+	// ContextFiles are files, directories or globs included in the spec's
+	// Additional Context section
+	ContextFiles []string
+	// ContextBudget caps the context in bytes; defaults to DefaultSpecContextBudget
+	ContextBudget int
+}
+
+// SpecTemplateData is the data available to spec templates
+type SpecTemplateData struct {
+	Title           string
+	Query           string
+	Type            string
+	Author          string
+	CodebaseSummary string
+	Context         string
 }
 
 // CreateSpec creates a new feature specification from a user query
-func CreateSpec(title, query string, contextFiles []string) (*SpecResult, error) {
+func CreateSpec(title, query string, opts SpecOptions) (*SpecResult, error) {
 	root, err := manager.FindProjectRoot()
 	if err != nil {
 		return nil, fmt.Errorf("failed to find project root: %w", err)
 	}
 
-	planDir, _, err := manager.GetPlanningDirs(root)
+	specType := opts.Type
+	if specType == "" {
+		specType = templates.DefaultSpecTemplate
+	}
+	tmplContent, err := LoadSpecTemplate(root, specType)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get planning dirs: %w", err)
+		return nil, err
 	}
 
 	// Create specs directory if it doesn't exist
-	specsDir := filepath.Join(filepath.Dir(planDir), "specs")
+	specsDir := manager.SpecsDir(root)
 	if err := os.MkdirAll(specsDir, 0755); err != nil {
 		return nil, fmt.Errorf("failed to create specs directory: %w", err)
 	}
@@ -49,17 +82,28 @@ func CreateSpec(title, query string, contextFiles []string) (*SpecResult, error)
 		codebaseSummary = codebaseMap.FormatSummary()
 	}
 
-	// Read context files
-	var contextContent strings.Builder
-	for _, cf := range contextFiles {
-		content, err := ReadFile(cf)
-		if err == nil {
-			contextContent.WriteString(fmt.Sprintf("\n### %s\n```\n%s\n```\n", cf, truncateContent(content, 500)))
-		}
+	contextContent, err := collectSpecContext(root, opts.ContextFiles, opts.ContextBudget)
+	if err != nil {
+		return nil, err
+	}
+
+	data := SpecTemplateData{
+		Title:           title,
+		Query:           query,
+		Type:            specType,
+		Author:          detectAuthor(root),
+		CodebaseSummary: codebaseSummary,
+		Context:         contextContent,
+	}
+	rendered, err := generateSpecContent(specType, tmplContent, data)
+	if err != nil {
+		return nil, err
 	}
 
-	// Generate spec content
-	content := generateSpecContent(title, query, codebaseSummary, contextContent.String())
+	content, err := withSpecMeta(rendered, fullPath, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render spec template %s: %w", specType, err)
+	}
 
 	// Write spec file
 	if err := os.WriteFile(fullPath, []byte(content), 0644); err != nil {
@@ -71,102 +115,81 @@ func CreateSpec(title, query string, contextFiles []string) (*SpecResult, error)
 		Filename: filename,
 		FullPath: fullPath,
 		Query:    query,
+		Type:     specType,
 	}, nil
 }
 
-// generateSpecContent generates the SPEC.md content
-func generateSpecContent(title, query, codebaseSummary, contextContent string) string {
-	var sb strings.Builder
-
-	sb.WriteString(fmt.Sprintf("# Feature Specification: %s\n\n", title))
-	sb.WriteString(fmt.Sprintf("**Created**: %s\n", time.Now().Format("2006-01-02 15:04")))
-	sb.WriteString("**Status**: 📝 Draft\n\n")
-
-	sb.WriteString("---\n\n")
-
-	// Goal section
-	sb.WriteString("## Goal\n\n")
-	sb.WriteString(fmt.Sprintf("> %s\n\n", query))
-	sb.WriteString("<!-- Describe the high-level objective. What are we building and why? -->\n\n")
-	sb.WriteString("[TODO: Expand the goal description here with clear business context]\n\n")
-
-	// User Stories
-	sb.WriteString("## User Stories\n\n")
-	sb.WriteString("<!-- Define user stories in the format: As a [role], I want [feature] so that [benefit] -->\n\n")
-	sb.WriteString("- [ ] As a **[User Role]**, I want **[Feature Description]** so that **[Benefit/Value]**\n")
-	sb.WriteString("- [ ] As a **[User Role]**, I want **[Feature Description]** so that **[Benefit/Value]**\n\n")
-
-	// Requirements
-	sb.WriteString("## Requirements\n\n")
-	sb.WriteString("### Functional Requirements\n\n")
-	sb.WriteString("<!-- What must the system do? -->\n\n")
-	sb.WriteString("- [ ] **FR1**: [Description of Functional Requirement]\n")
-	sb.WriteString("- [ ] **FR2**: [Description of Functional Requirement]\n")
-	sb.WriteString("- [ ] **FR3**: [Description of Functional Requirement]\n\n")
-
-	sb.WriteString("### Non-Functional Requirements\n\n")
-	sb.WriteString("<!-- Performance, security, scalability constraints -->\n\n")
-	sb.WriteString("| Category | Requirement |\n")
-	sb.WriteString("|----------|-------------|\n")
-	sb.WriteString("| **Performance** | [e.g., Response time < 200ms] |\n")
-	sb.WriteString("| **Security** | [e.g., Input validation required] |\n")
-	sb.WriteString("| **Scalability** | [e.g., Support 1000 concurrent users] |\n\n")
-
-	// Architecture Constraints
-	sb.WriteString("## Architecture Constraints\n\n")
-	sb.WriteString("<!-- What existing patterns, services, or constraints must be followed? -->\n\n")
-	sb.WriteString("- Must integrate with: [existing service/component]\n")
-	sb.WriteString("- Must follow pattern: [e.g., repository pattern, DI]\n")
-	sb.WriteString("- Must use: [specific technology/library]\n\n")
-
-	// Codebase Context
-	if codebaseSummary != "" {
-		sb.WriteString("## Codebase Context\n\n")
-		sb.WriteString("```\n")
-		sb.WriteString(codebaseSummary)
-		sb.WriteString("```\n\n")
-	}
-
-	// Additional Context
-	if contextContent != "" {
-		sb.WriteString("## Additional Context\n\n")
-		sb.WriteString(contextContent)
-		sb.WriteString("\n")
-	}
-
-	// Edge Cases
-	sb.WriteString("## Edge Cases\n\n")
-	sb.WriteString("<!-- Document edge cases and expected behavior -->\n\n")
-	sb.WriteString("| Edge Case | Expected Behavior |\n")
-	sb.WriteString("|-----------|-------------------|\n")
-	sb.WriteString("| [Empty input] | [Return validation error with specific message] |\n")
-	sb.WriteString("| [Duplicate entry] | [Return 409 Conflict with resource location] |\n")
-	sb.WriteString("| [Unauthorized access] | [Return 401/403 with auth challenge] |\n\n")
-
-	// Out of Scope
-	sb.WriteString("## Out of Scope\n\n")
-	sb.WriteString("<!-- Explicitly list what is NOT included in this feature -->\n\n")
-	sb.WriteString("- ❌ [Feature/capability not included]\n")
-	sb.WriteString("- ❌ [Another exclusion]\n\n")
-
-	// Success Criteria
-	sb.WriteString("## Success Criteria\n\n")
-	sb.WriteString("<!-- Measurable criteria to verify the feature is complete -->\n\n")
-	sb.WriteString("- [ ] **SC1**: [e.g., All unit tests pass with >80% coverage]\n")
-	sb.WriteString("- [ ] **SC2**: [e.g., API returns 200 OK with correct payload]\n")
-	sb.WriteString("- [ ] **SC3**: [e.g., Error cases return appropriate status codes (400, 4xx, 5xx)]\n")
-	sb.WriteString("- [ ] **SC4**: [e.g., Documentation updated in internal wiki]\n\n")
-
-	// Open Questions
-	sb.WriteString("## Open Questions\n\n")
-	sb.WriteString("<!-- Questions that need answers before implementation -->\n\n")
-	sb.WriteString("1. [Question about requirement/design]\n")
-	sb.WriteString("2. [Question about integration]\n\n")
-
-	sb.WriteString("---\n\n")
-	sb.WriteString("> ⚠️ **Note**: This spec must be reviewed and approved before generating a PLAN.md\n")
-
-	return sb.String()
+// generateSpecContent renders a spec template into the SPEC.md content
+func generateSpecContent(name, tmplContent string, data SpecTemplateData) (string, error) {
+	tmpl, err := template.New("spec").Parse(tmplContent)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse spec template %s: %w", name, err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to execute spec template %s: %w", name, err)
+	}
+	return buf.String(), nil
+}
+
+// withSpecMeta adds frontmatter to a rendered spec, tagged with its type.
+// Fields already set by a project template's own frontmatter are kept.
+func withSpecMeta(rendered, path string, data SpecTemplateData) (string, error) {
+	m, body, err := frontmatter.Parse(rendered)
+	if err != nil {
+		return "", err
+	}
+
+	defaults := NewDocumentMeta(path, frontmatter.KindSpec, spec.StatusDraft)
+	if m == nil {
+		m = defaults
+	}
+
+	fill := func(field *string, value string) {
+		if *field == "" {
+			*field = value
+		}
+	}
+	fill(&m.ID, defaults.ID)
+	fill(&m.Kind, defaults.Kind)
+	fill(&m.Status, defaults.Status)
+	fill(&m.Owner, data.Author)
+	fill(&m.WorkflowID, defaults.WorkflowID)
+	if !slices.Contains(m.Tags, data.Type) {
+		m.Tags = append(m.Tags, data.Type)
+	}
+	m.Touch()
+
+	return m.Render(body)
+}
+
+// specTemplateOverridePath returns where a project override for a spec template lives
+func specTemplateOverridePath(rootDir, name string) string {
+	return filepath.Join(manager.TemplatesDir(rootDir), "spec-"+name+".md")
+}
+
+// LoadSpecTemplate returns the named spec template, preferring a project
+// override in .opusflow/templates over the built-in template
+func LoadSpecTemplate(rootDir, name string) (string, error) {
+	if data, err := os.ReadFile(specTemplateOverridePath(rootDir, name)); err == nil {
+		return string(data), nil
+	}
+
+	if content, ok := templates.SpecTemplates[name]; ok {
+		return content, nil
+	}
+
+	names := make([]string, 0)
+	for _, t := range ListSpecTemplates(rootDir) {
+		names = append(names, t.Name)
+	}
+	return "", fmt.Errorf("unknown spec type %q (available: %s)", name, strings.Join(names, ", "))
+}
+
+// ListSpecTemplates returns built-in and project spec templates sorted by name
+func ListSpecTemplates(rootDir string) []PlanTemplateInfo {
+	return listTemplates(rootDir, "spec-", templates.SpecTemplates)
 }
 
 // slugify converts a title to a URL-friendly slug
@@ -188,12 +211,60 @@ func slugify(title string) string {
 	return slug
 }
 
-// truncateContent truncates content to a maximum length
+// truncateContent truncates content to a maximum length, preferring to cut
+// at a line break and never inside a UTF-8 sequence
 func truncateContent(content string, maxLen int) string {
 	if len(content) <= maxLen {
 		return content
 	}
-	return content[:maxLen] + "\n... (truncated)"
+	cut := maxLen
+	if nl := strings.LastIndexByte(content[:maxLen], '\n'); nl > maxLen/2 {
+		cut = nl
+	}
+	for cut > 0 && !utf8.RuneStart(content[cut]) {
+		cut--
+	}
+	return content[:cut] + "\n... (truncated)"
+}
+
+// ResolveSpecPath locates a spec file given a path (absolute or relative to the
+// project root) or a bare filename inside the specs directory
+func ResolveSpecPath(ref string) (string, error) {
+	root, err := manager.FindProjectRoot()
+	if err != nil {
+		return "", fmt.Errorf("failed to find project root: %w", err)
+	}
+
+	candidates := []string{ref}
+	if !filepath.IsAbs(ref) {
+		candidates = []string{
+			filepath.Join(root, ref),
+			filepath.Join(manager.SpecsDir(root), filepath.Base(ref)),
+		}
+	}
+
+	for _, c := range candidates {
+		if info, err := os.Stat(c); err == nil && !info.IsDir() {
+			return c, nil
+		}
+	}
+
+	return "", fmt.Errorf("spec not found: %s", ref)
+}
+
+// LoadSpec resolves and parses a spec file
+func LoadSpec(ref string) (*spec.Spec, string, error) {
+	path, err := ResolveSpecPath(ref)
+	if err != nil {
+		return nil, "", err
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read spec: %w", err)
+	}
+
+	return spec.Parse(string(content)), path, nil
 }
 
 // GenerateSpecPrompt generates a prompt for an AI to fill in the spec
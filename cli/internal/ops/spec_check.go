@@ -0,0 +1,359 @@
+package ops
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/tuanpep/oplusflow/internal/frontmatter"
+	"github.com/tuanpep/oplusflow/internal/spec"
+)
+
+// DefaultSpecMinScore is the completeness score a spec needs before planning
+const DefaultSpecMinScore = 80
+
+// specSection is a section that counts towards the completeness score
+type specSection struct {
+	title    string
+	required bool
+}
+
+// scoredSections lists the sections of the spec template that are scored.
+// Optional sections are only scored when present.
+var scoredSections = []specSection{
+	{spec.SectionGoal, true},
+	{spec.SectionUserStories, false},
+	{spec.SectionFunctionalReqs, true},
+	{spec.SectionNonFunctionalReqs, false},
+	{spec.SectionConstraints, false},
+	{spec.SectionEdgeCases, true},
+	{spec.SectionOutOfScope, false},
+	{spec.SectionSuccessCriteria, true},
+}
+
+// vagueTerms are words that describe a quality without a measurable threshold
+var vagueTerms = []string{
+	"fast", "quickly", "responsive", "efficient", "performant", "scalable",
+	"robust", "reliable", "secure", "user-friendly", "intuitive", "easy",
+	"simple", "seamless", "appropriate", "properly", "reasonable", "adequate",
+	"as needed", "etc",
+}
+
+var (
+	vagueTermPattern = regexp.MustCompile(`(?i)\b(` + strings.Join(vagueTerms, "|") + `)\b`)
+	// quantityPattern matches numbers, code spans and quoted literals
+	quantityPattern = regexp.MustCompile("\\d|`[^`]+`|\"[^\"]+\"")
+)
+
+// SectionScore is the completeness of one spec section
+type SectionScore struct {
+	Title    string `json:"title"`
+	Line     int    `json:"line,omitempty"`
+	Required bool   `json:"required"`
+	Missing  bool   `json:"missing,omitempty"`
+	Filled   int    `json:"filled"`
+	Total    int    `json:"total"`
+	Score    int    `json:"score"`
+}
+
+// SpecFinding is a line of the spec that needs attention
+type SpecFinding struct {
+	Line    int    `json:"line"`
+	Section string `json:"section,omitempty"`
+	Text    string `json:"text"`
+}
+
+// SpecCheckResult is the outcome of checking a spec for completeness
+type SpecCheckResult struct {
+	SpecPath string         `json:"spec_path"`
+	Score    int            `json:"score"`
+	MinScore int            `json:"min_score"`
+	Sections []SectionScore `json:"sections"`
+	// Placeholders are unfilled template placeholders such as "[User Role]"
+	Placeholders []SpecFinding `json:"placeholders"`
+	// OpenQuestions are questions not yet checked off or struck through
+	OpenQuestions []SpecFinding `json:"open_questions"`
+	// Unmeasurable are requirements and criteria that use vague terms without a threshold
+	Unmeasurable []SpecFinding `json:"unmeasurable"`
+}
+
+// CheckSpecFile checks a spec located with ResolveSpecPath. A minScore of 0
+// uses DefaultSpecMinScore.
+func CheckSpecFile(ref string, minScore int) (*SpecCheckResult, error) {
+	path, err := ResolveSpecPath(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read spec: %w", err)
+	}
+
+	result := CheckSpecContent(string(content), minScore)
+	result.SpecPath = path
+	return result, nil
+}
+
+// CheckSpecContent scores each section of a spec by the share of its entries
+// that no longer contain template placeholders, and lists the placeholders,
+// open questions and requirements without measurable criteria
+func CheckSpecContent(content string, minScore int) *SpecCheckResult {
+	if minScore <= 0 {
+		minScore = DefaultSpecMinScore
+	}
+	result := &SpecCheckResult{
+		MinScore:      minScore,
+		Sections:      []SectionScore{},
+		Placeholders:  []SpecFinding{},
+		OpenQuestions: []SpecFinding{},
+		Unmeasurable:  []SpecFinding{},
+	}
+
+	s := spec.Parse(content)
+	stats := scanSpecSections(content, result)
+
+	total := 0
+	for _, ss := range scoredSections {
+		sec := s.Section(ss.title)
+		if sec == nil && !ss.required {
+			continue
+		}
+		score := SectionScore{Title: ss.title, Required: ss.required, Missing: sec == nil}
+		if sec != nil {
+			score.Line = sec.Line
+			st := stats[sec.Line]
+			score.Filled, score.Total = st.filled, st.total
+			if st.total > 0 {
+				score.Score = st.filled * 100 / st.total
+			}
+		}
+		result.Sections = append(result.Sections, score)
+		total += score.Score
+	}
+	if len(result.Sections) > 0 {
+		result.Score = total / len(result.Sections)
+	}
+
+	for _, q := range s.OpenQuestions {
+		if q.Checked || strings.HasPrefix(q.Text, "~~") || spec.IsPlaceholder(q.Text) {
+			continue
+		}
+		result.OpenQuestions = append(result.OpenQuestions, SpecFinding{Line: q.Line, Section: spec.SectionOpenQuestions, Text: q.Text})
+	}
+
+	checkMeasurable(result, s.FunctionalRequirements, spec.SectionFunctionalReqs)
+	checkMeasurable(result, s.SuccessCriteria, spec.SectionSuccessCriteria)
+
+	return result
+}
+
+// sectionStats counts the entries of a section and how many are filled in
+type sectionStats struct {
+	filled int
+	total  int
+}
+
+// scanSpecSections walks the spec body outside code fences and comments,
+// recording placeholders and counting entries per section (keyed by heading line)
+func scanSpecSections(content string, r *SpecCheckResult) map[int]*sectionStats {
+	stats := make(map[int]*sectionStats)
+	_, _, bodyLine, _ := frontmatter.Split(content)
+
+	var current *sectionStats
+	section := ""
+	fence := ""
+	inComment := false
+	tableHeader := false
+
+	for i, raw := range strings.Split(content, "\n") {
+		number := i + 1
+		if number < bodyLine {
+			continue
+		}
+		text := strings.TrimSpace(raw)
+
+		if fence != "" {
+			if strings.HasPrefix(text, fence) {
+				fence = ""
+			}
+			continue
+		}
+		if strings.HasPrefix(text, "```") || strings.HasPrefix(text, "~~~") {
+			fence = text[:3]
+			continue
+		}
+
+		if inComment {
+			end := strings.Index(text, "-->")
+			if end < 0 {
+				continue
+			}
+			inComment = false
+			text = strings.TrimSpace(text[end+3:])
+		}
+		text = strings.TrimSpace(commentPattern.ReplaceAllString(text, ""))
+		if start := strings.Index(text, "<!--"); start >= 0 {
+			inComment = true
+			text = strings.TrimSpace(text[:start])
+		}
+
+		if strings.HasPrefix(text, "#") {
+			level := len(text) - len(strings.TrimLeft(text, "#"))
+			title := strings.TrimSpace(strings.Trim(text, "# "))
+			if level == 1 {
+				continue
+			}
+			section = title
+			current = &sectionStats{}
+			stats[number] = current
+			tableHeader = true
+			continue
+		}
+
+		if text == "" || text == "---" || strings.HasPrefix(text, "**Status**:") {
+			continue
+		}
+
+		for _, ph := range spec.Placeholders(text) {
+			r.Placeholders = append(r.Placeholders, SpecFinding{Line: number, Section: section, Text: ph})
+		}
+
+		if current == nil {
+			continue
+		}
+		if strings.HasPrefix(text, "|") {
+			if tableHeader || strings.Trim(text, "|-: ") == "" {
+				tableHeader = false
+				continue
+			}
+		}
+		current.total++
+		if !spec.IsPlaceholder(text) {
+			current.filled++
+		}
+	}
+
+	return stats
+}
+
+// checkMeasurable flags filled requirements that rely on vague terms
+// without a number, code span or quoted value to measure them by
+func checkMeasurable(r *SpecCheckResult, reqs []spec.Requirement, section string) {
+	for _, req := range reqs {
+		if spec.IsPlaceholder(req.Text) || quantityPattern.MatchString(req.Text) {
+			continue
+		}
+		if m := vagueTermPattern.FindString(req.Text); m != "" {
+			r.Unmeasurable = append(r.Unmeasurable, SpecFinding{
+				Line:    req.Line,
+				Section: section,
+				Text:    fmt.Sprintf("%s: %q has no measurable threshold (%s)", req.ID, m, req.Text),
+			})
+		}
+	}
+}
+
+// Ready reports whether the spec can move on to planning: the score meets
+// the minimum and no placeholders or open questions remain
+func (r *SpecCheckResult) Ready() bool {
+	return r.Score >= r.MinScore && len(r.Placeholders) == 0 && len(r.OpenQuestions) == 0
+}
+
+// Blockers describes why the spec is not ready for planning
+func (r *SpecCheckResult) Blockers() []string {
+	var blockers []string
+	if r.Score < r.MinScore {
+		blockers = append(blockers, fmt.Sprintf("completeness %d%% is below %d%%", r.Score, r.MinScore))
+	}
+	if n := len(r.Placeholders); n > 0 {
+		blockers = append(blockers, fmt.Sprintf("%d unresolved placeholder(s)", n))
+	}
+	if n := len(r.OpenQuestions); n > 0 {
+		blockers = append(blockers, fmt.Sprintf("%d open question(s)", n))
+	}
+	return blockers
+}
+
+// FormatText returns a human-readable completeness report
+func (r *SpecCheckResult) FormatText() string {
+	var sb strings.Builder
+
+	name := r.SpecPath
+	if name == "" {
+		name = "spec"
+	}
+
+	sb.WriteString(fmt.Sprintf("# Spec Check: %s\n\n", filepath.Base(name)))
+	sb.WriteString(fmt.Sprintf("**Completeness**: %d%% (minimum %d%%)\n\n", r.Score, r.MinScore))
+
+	sb.WriteString("## Sections\n")
+	for _, s := range r.Sections {
+		icon := "✅"
+		switch {
+		case s.Score == 0:
+			icon = "❌"
+		case s.Score < 100:
+			icon = "⚠️"
+		}
+		detail := fmt.Sprintf("%3d%%  (%d/%d filled)", s.Score, s.Filled, s.Total)
+		if s.Missing {
+			detail = "missing"
+		} else if s.Total == 0 {
+			detail = "  0%  (empty)"
+		}
+		sb.WriteString(fmt.Sprintf("- %s %-28s %s\n", icon, s.Title, detail))
+	}
+
+	writeFindings := func(title string, findings []SpecFinding, withSection bool) {
+		if len(findings) == 0 {
+			return
+		}
+		sb.WriteString(fmt.Sprintf("\n## %s (%d)\n", title, len(findings)))
+		for _, f := range findings {
+			line := fmt.Sprintf("%s:%d: %s", name, f.Line, f.Text)
+			if withSection && f.Section != "" {
+				line += fmt.Sprintf(" (%s)", f.Section)
+			}
+			sb.WriteString(line + "\n")
+		}
+	}
+	writeFindings("Unresolved Placeholders", r.Placeholders, true)
+	writeFindings("Open Questions", r.OpenQuestions, false)
+	writeFindings("Requirements Without Measurable Criteria", r.Unmeasurable, false)
+
+	if r.Ready() {
+		sb.WriteString("\n✅ Ready for planning\n")
+	} else {
+		sb.WriteString(fmt.Sprintf("\n❌ Not ready for planning: %s\n", strings.Join(r.Blockers(), ", ")))
+	}
+
+	return sb.String()
+}
+
+// FormatJSON returns the check result as indented JSON
+func (r *SpecCheckResult) FormatJSON() (string, error) {
+	data, err := json.MarshalIndent(struct {
+		*SpecCheckResult
+		Ready bool `json:"ready"`
+	}{r, r.Ready()}, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// CheckSpecGate returns an error when the spec is not ready for planning
+func CheckSpecGate(ref string, minScore int) (*SpecCheckResult, error) {
+	result, err := CheckSpecFile(ref, minScore)
+	if err != nil {
+		return nil, err
+	}
+	if !result.Ready() {
+		return result, fmt.Errorf("spec %s is not ready for planning: %s", filepath.Base(result.SpecPath), strings.Join(result.Blockers(), ", "))
+	}
+	return result, nil
+}
@@ -0,0 +1,124 @@
+package ops
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/tuanpep/oplusflow/internal/templates"
+)
+
+const filledSpec = "# Feature Specification: OAuth Login\n" +
+	"\n" +
+	"## Goal\n" +
+	"\n" +
+	"> Let users sign in with Google\n" +
+	"\n" +
+	"<!-- Describe the high-level objective -->\n" +
+	"\n" +
+	"## Requirements\n" +
+	"\n" +
+	"### Functional Requirements\n" +
+	"\n" +
+	"- [ ] **FR1**: Redirect to the provider\n" +
+	"- [ ] **FR2**: Token refresh must be fast\n" +
+	"- [ ] **FR3**: Login completes in under 2s\n" +
+	"\n" +
+	"## Edge Cases\n" +
+	"\n" +
+	"| Edge Case | Expected Behavior |\n" +
+	"|-----------|-------------------|\n" +
+	"| Denied consent | Show an error |\n" +
+	"\n" +
+	"## Success Criteria\n" +
+	"\n" +
+	"- [ ] **SC1**: `GET /login` returns 302\n" +
+	"\n" +
+	"## Open Questions\n" +
+	"\n" +
+	"1. [x] Which scopes? Email only\n" +
+	"2. ~~Support GitHub?~~ Out of scope\n"
+
+func TestCheckSpecContent_Template(t *testing.T) {
+	content, err := generateSpecContent("feature", templates.SpecTemplate, SpecTemplateData{Title: "OAuth", Query: "Let users sign in"})
+	if err != nil {
+		t.Fatalf("generateSpecContent failed: %v", err)
+	}
+	result := CheckSpecContent(content, 0)
+
+	if result.Ready() {
+		t.Fatal("Expected the untouched template not to be ready")
+	}
+	if result.MinScore != DefaultSpecMinScore {
+		t.Errorf("Expected default minimum score, got %d", result.MinScore)
+	}
+
+	scores := make(map[string]SectionScore)
+	for _, s := range result.Sections {
+		scores[s.Title] = s
+	}
+	if goal := scores["Goal"]; goal.Filled != 1 || goal.Total != 2 || goal.Score != 50 {
+		t.Errorf("Expected the goal to be half filled, got %+v", goal)
+	}
+	if fr := scores["Functional Requirements"]; fr.Score != 0 || fr.Total != 3 {
+		t.Errorf("Expected unfilled requirements, got %+v", fr)
+	}
+
+	found := false
+	for _, p := range result.Placeholders {
+		if p.Text == "[User Role]" && p.Section == "User Stories" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Expected [User Role] to be reported, got %+v", result.Placeholders)
+	}
+	if len(result.OpenQuestions) != 0 {
+		t.Errorf("Expected placeholder questions to count as placeholders, got %+v", result.OpenQuestions)
+	}
+}
+
+func TestCheckSpecContent_Filled(t *testing.T) {
+	result := CheckSpecContent(filledSpec, 0)
+
+	if result.Score != 100 {
+		t.Errorf("Expected full score, got %d: %+v", result.Score, result.Sections)
+	}
+	if len(result.Placeholders) != 0 || len(result.OpenQuestions) != 0 {
+		t.Errorf("Expected resolved questions and no placeholders, got %+v %+v", result.Placeholders, result.OpenQuestions)
+	}
+	if !result.Ready() {
+		t.Errorf("Expected spec to be ready, blockers: %v", result.Blockers())
+	}
+
+	if len(result.Unmeasurable) != 1 || !strings.HasPrefix(result.Unmeasurable[0].Text, `FR2: "fast"`) {
+		t.Errorf("Expected only FR2 to be flagged, got %+v", result.Unmeasurable)
+	}
+}
+
+func TestCheckSpecContent_BracketsInProse(t *testing.T) {
+	content := strings.Replace(filledSpec, "- [ ] **FR1**: Redirect to the provider\n",
+		"- [X] **FR1**: Redirect to the provider; reject `items[0]` and clamp retries to [0, 5]\n", 1)
+
+	result := CheckSpecContent(content, 0)
+	if len(result.Placeholders) != 0 || !result.Ready() {
+		t.Errorf("Expected code spans, ranges and checked boxes not to count as placeholders, got %+v", result.Placeholders)
+	}
+}
+
+func TestCheckSpecContent_Blockers(t *testing.T) {
+	content := strings.Replace(filledSpec, "## Success Criteria\n\n- [ ] **SC1**: `GET /login` returns 302\n\n", "", 1) +
+		"3. Do we need refresh tokens?\n"
+
+	result := CheckSpecContent(content, 90)
+	if result.Ready() {
+		t.Fatal("Expected spec with a missing section and an open question not to be ready")
+	}
+
+	blockers := strings.Join(result.Blockers(), ", ")
+	if !strings.Contains(blockers, "below 90%") || !strings.Contains(blockers, "1 open question(s)") {
+		t.Errorf("Unexpected blockers: %s", blockers)
+	}
+	if out := result.FormatText(); !strings.Contains(out, "Success Criteria") || !strings.Contains(out, "missing") {
+		t.Errorf("Expected missing section in report:\n%s", out)
+	}
+}
@@ -0,0 +1,310 @@
+package ops
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// DefaultSpecContextBudget is the total number of bytes of --context material
+// included in a new spec
+const DefaultSpecContextBudget = 12000
+
+// minContextShare is the smallest slice of the budget worth giving a single
+// entry; below it, entries are listed as omitted instead of included
+const minContextShare = 300
+
+// dirMapMaxFiles caps the codebase map generated for a context directory
+const dirMapMaxFiles = 200
+
+// contextItem is one file or directory map collected for a spec's context
+type contextItem struct {
+	Path    string // relative to the project root; directories end in "/"
+	Content string
+	IsMap   bool
+}
+
+// collectSpecContext expands --context entries (files, directories and globs
+// such as "src/**/*.go") and renders them within a shared byte budget.
+// Directories contribute a codebase map slice instead of file contents.
+func collectSpecContext(root string, refs []string, budget int) (string, error) {
+	if len(refs) == 0 {
+		return "", nil
+	}
+	if budget <= 0 {
+		budget = DefaultSpecContextBudget
+	}
+
+	items, err := expandContextRefs(root, refs)
+	if err != nil {
+		return "", err
+	}
+	return formatContextItems(items, budget), nil
+}
+
+// expandContextRefs resolves every entry to files and directory maps, in
+// order and without duplicates
+func expandContextRefs(root string, refs []string) ([]contextItem, error) {
+	var items []contextItem
+	seen := make(map[string]bool)
+
+	addFile := func(path string) {
+		rel := relToRoot(root, path)
+		if seen[rel] {
+			return
+		}
+		seen[rel] = true
+		if isBinary(path) {
+			return
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return
+		}
+		items = append(items, contextItem{Path: rel, Content: string(data)})
+	}
+
+	for _, ref := range refs {
+		candidates := contextPaths(root, ref)
+
+		if strings.ContainsAny(ref, "*?[") {
+			var matches []string
+			for _, c := range candidates {
+				var err error
+				if matches, err = globFiles(root, c); err != nil {
+					return nil, err
+				}
+				if len(matches) > 0 {
+					break
+				}
+			}
+			if len(matches) == 0 {
+				return nil, fmt.Errorf("context %s: no files match", ref)
+			}
+			for _, m := range matches {
+				addFile(m)
+			}
+			continue
+		}
+
+		var path string
+		var info os.FileInfo
+		var err error
+		for _, path = range candidates {
+			if info, err = os.Stat(path); err == nil {
+				break
+			}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("context %s: %w", ref, err)
+		}
+		if !info.IsDir() {
+			addFile(path)
+			continue
+		}
+
+		rel := relToRoot(root, path) + "/"
+		if seen[rel] {
+			continue
+		}
+		seen[rel] = true
+		pm, err := GenerateCodebaseMap(path, nil, nil, dirMapMaxFiles)
+		if err != nil {
+			return nil, fmt.Errorf("context %s: %w", ref, err)
+		}
+		items = append(items, contextItem{Path: rel, Content: formatDirMap(pm), IsMap: true})
+	}
+
+	return items, nil
+}
+
+// contextPaths returns where a --context entry may live: relative entries are
+// tried against the working directory first, then the project root
+func contextPaths(root, ref string) []string {
+	if filepath.IsAbs(ref) {
+		return []string{ref}
+	}
+	fromRoot := filepath.Join(root, ref)
+	if cwd, err := filepath.Abs(ref); err == nil && cwd != fromRoot {
+		return []string{cwd, fromRoot}
+	}
+	return []string{fromRoot}
+}
+
+// globFiles returns the files under root matching pattern, which may use "**"
+// to match any number of directories. Ignored paths are skipped.
+func globFiles(root, pattern string) ([]string, error) {
+	re, err := globPattern(filepath.ToSlash(pattern))
+	if err != nil {
+		return nil, fmt.Errorf("invalid context pattern %s: %w", pattern, err)
+	}
+
+	ignore := NewIgnoreHandler(root)
+	var matches []string
+	err = filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		if info.IsDir() {
+			ignore.TrackDirectory(path)
+		}
+		if path != root && ignore.ShouldIgnore(path, info.IsDir()) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !info.IsDir() && re.MatchString(filepath.ToSlash(path)) {
+			matches = append(matches, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk directory: %w", err)
+	}
+
+	sort.Strings(matches)
+	return matches, nil
+}
+
+// globPattern converts a slash-separated glob into a regular expression.
+// "**/" matches zero or more directories, "*" and "?" stay within one.
+func globPattern(glob string) (*regexp.Regexp, error) {
+	var sb strings.Builder
+	sb.WriteString("^")
+	for i := 0; i < len(glob); i++ {
+		c := glob[i]
+		switch {
+		case strings.HasPrefix(glob[i:], "**/"):
+			sb.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(glob[i:], "**"):
+			sb.WriteString(".*")
+			i++
+		case c == '*':
+			sb.WriteString("[^/]*")
+		case c == '?':
+			sb.WriteString("[^/]")
+		case c == '[':
+			end := strings.IndexByte(glob[i:], ']')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated character class")
+			}
+			class := glob[i+1 : i+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			sb.WriteString("[" + class + "]")
+			i += end
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	sb.WriteString("$")
+	return regexp.Compile(sb.String())
+}
+
+// formatDirMap renders a directory's codebase map as a plain symbol listing
+func formatDirMap(pm *ProjectMap) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%d files, %d lines (%s)\n", pm.Statistics.TotalFiles, pm.Statistics.TotalLines, strings.Join(pm.Languages, ", ")))
+	for _, f := range pm.Files {
+		sb.WriteString(fmt.Sprintf("\n%s (%d lines)\n", filepath.ToSlash(f.Path), f.LineCount))
+		for _, sym := range f.Symbols {
+			if sym.Signature != "" {
+				sb.WriteString(fmt.Sprintf("  %s\n", sym.Signature))
+			} else {
+				sb.WriteString(fmt.Sprintf("  %s %s\n", sym.Kind, sym.Name))
+			}
+		}
+	}
+	return sb.String()
+}
+
+// allocateContextBudget splits budget across contents so that small entries
+// are included whole and the remainder is shared evenly by the larger ones.
+// Entries beyond what the budget can meaningfully hold get a zero share.
+func allocateContextBudget(sizes []int, budget int) []int {
+	shares := make([]int, len(sizes))
+	if len(sizes) == 0 {
+		return shares
+	}
+
+	// Keep the first entries when there are too many to give each a useful share
+	n := len(sizes)
+	if budget/n < minContextShare {
+		n = max(budget/minContextShare, 1)
+	}
+
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return sizes[order[a]] < sizes[order[b]] })
+
+	remaining := budget
+	for k, i := range order {
+		share := remaining / (n - k)
+		shares[i] = min(sizes[i], share)
+		remaining -= shares[i]
+	}
+	return shares
+}
+
+// formatContextItems renders the collected items within the byte budget
+func formatContextItems(items []contextItem, budget int) string {
+	sizes := make([]int, len(items))
+	for i, item := range items {
+		sizes[i] = len(item.Content)
+	}
+	shares := allocateContextBudget(sizes, budget)
+
+	var sb strings.Builder
+	var omitted []string
+	for i, item := range items {
+		if shares[i] == 0 && sizes[i] > 0 {
+			omitted = append(omitted, item.Path)
+			continue
+		}
+
+		content := truncateContent(item.Content, shares[i])
+		heading, lang := item.Path, strings.TrimPrefix(filepath.Ext(item.Path), ".")
+		if item.IsMap {
+			heading, lang = item.Path+" (codebase map)", ""
+		}
+		fence := codeFence(content)
+		sb.WriteString(fmt.Sprintf("\n### %s\n%s%s\n%s\n%s\n", heading, fence, lang, strings.TrimRight(content, "\n"), fence))
+	}
+
+	if len(omitted) > 0 {
+		sb.WriteString(fmt.Sprintf("\n*Omitted (context budget of %d bytes reached)*: %s\n", budget, strings.Join(omitted, ", ")))
+	}
+	return sb.String()
+}
+
+// codeFence returns a backtick fence longer than any backtick run in content
+func codeFence(content string) string {
+	longest, run := 0, 0
+	for _, r := range content {
+		if r == '`' {
+			run++
+			longest = max(longest, run)
+		} else {
+			run = 0
+		}
+	}
+	return strings.Repeat("`", max(3, longest+1))
+}
+
+// relToRoot returns path relative to root with forward slashes, or path itself
+// when it lies outside the root
+func relToRoot(root, path string) string {
+	rel, err := filepath.Rel(root, path)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return filepath.ToSlash(path)
+	}
+	return filepath.ToSlash(rel)
+}
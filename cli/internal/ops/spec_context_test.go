@@ -0,0 +1,149 @@
+package ops
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGlobPattern(t *testing.T) {
+	tests := []struct {
+		glob  string
+		path  string
+		match bool
+	}{
+		{"src/*.go", "src/main.go", true},
+		{"src/*.go", "src/pkg/main.go", false},
+		{"src/**/*.go", "src/main.go", true},
+		{"src/**/*.go", "src/a/b/main.go", true},
+		{"src/**/*.go", "other/main.go", false},
+		{"src/**", "src/a/b.txt", true},
+		{"file?.md", "file1.md", true},
+		{"file?.md", "file10.md", false},
+		{"[ab].txt", "a.txt", true},
+		{"[!ab].txt", "a.txt", false},
+		{"a.b", "axb", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.glob+" "+tt.path, func(t *testing.T) {
+			re, err := globPattern(tt.glob)
+			if err != nil {
+				t.Fatalf("globPattern(%q) failed: %v", tt.glob, err)
+			}
+			if got := re.MatchString(tt.path); got != tt.match {
+				t.Errorf("globPattern(%q) match %q = %v; want %v", tt.glob, tt.path, got, tt.match)
+			}
+		})
+	}
+
+	if _, err := globPattern("src/[ab"); err == nil {
+		t.Error("Expected error for unterminated character class")
+	}
+}
+
+func TestAllocateContextBudget(t *testing.T) {
+	tests := []struct {
+		name   string
+		sizes  []int
+		budget int
+		want   []int
+	}{
+		{"all fit", []int{100, 200}, 1000, []int{100, 200}},
+		{"small files kept whole", []int{5000, 100, 5000}, 3000, []int{1450, 100, 1450}},
+		{"even split", []int{4000, 4000}, 3000, []int{1500, 1500}},
+		{"too many entries", []int{1000, 1000, 1000, 1000}, 900, []int{300, 300, 300, 0}},
+		{"empty", nil, 1000, []int{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := allocateContextBudget(tt.sizes, tt.budget)
+			if len(got) != len(tt.want) {
+				t.Fatalf("allocateContextBudget(%v, %d) = %v; want %v", tt.sizes, tt.budget, got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("allocateContextBudget(%v, %d) = %v; want %v", tt.sizes, tt.budget, got, tt.want)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestCollectSpecContext(t *testing.T) {
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		"go.mod":              "module example.com/x\n",
+		"src/api/handler.go":  "package api\n\nfunc Handle() error { return nil }\n",
+		"src/api/routes.go":   "package api\n\nfunc Routes() {}\n",
+		"docs/notes.md":       "Some ```quoted``` notes\n",
+		"docs/big.txt":        strings.Repeat("line of text\n", 2000),
+		"node_modules/x/a.go": "package x\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(tmpDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.MkdirAll(filepath.Join(tmpDir, ".agent"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	oldWd, _ := os.Getwd()
+	os.Chdir(tmpDir)
+	defer os.Chdir(oldWd)
+
+	got, err := collectSpecContext(tmpDir, []string{"src/api/", "docs/*", "**/*.go", "go.mod"}, 2000)
+	if err != nil {
+		t.Fatalf("collectSpecContext failed: %v", err)
+	}
+
+	for _, want := range []string{
+		"### src/api/ (codebase map)",
+		"handler.go (4 lines)",
+		"  Handle() error\n",
+		"### docs/notes.md\n````md\nSome ```quoted``` notes\n````",
+		"### docs/big.txt\n```txt\nline of text\n",
+		"... (truncated)",
+		"### src/api/handler.go\n```go\n",
+		"### go.mod\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Expected %q in context, got:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "node_modules") {
+		t.Errorf("Expected ignored directories to be skipped, got:\n%s", got)
+	}
+	if strings.Count(got, "### go.mod") != 1 {
+		t.Errorf("Expected files matched twice to be included once, got:\n%s", got)
+	}
+	if len(got) > 2000+1000 {
+		t.Errorf("Expected context to stay near the budget, got %d bytes", len(got))
+	}
+
+	for _, ref := range []string{"missing.go", "src/**/*.rs"} {
+		if _, err := collectSpecContext(tmpDir, []string{ref}, 0); err == nil {
+			t.Errorf("Expected error for context %s", ref)
+		}
+	}
+
+	// From a subdirectory, entries resolve against it first, then the root
+	os.Chdir(filepath.Join(tmpDir, "src"))
+	got, err = collectSpecContext(tmpDir, []string{"api/routes.go", "api/*.go", "docs/notes.md"}, 0)
+	if err != nil {
+		t.Fatalf("collectSpecContext from a subdirectory failed: %v", err)
+	}
+	for _, want := range []string{"### src/api/routes.go", "### src/api/handler.go", "### docs/notes.md"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Expected %q in context, got:\n%s", want, got)
+		}
+	}
+}
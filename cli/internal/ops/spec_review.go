@@ -0,0 +1,188 @@
+package ops
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/tuanpep/oplusflow/internal/frontmatter"
+	"github.com/tuanpep/oplusflow/internal/manager"
+	"github.com/tuanpep/oplusflow/internal/spec"
+)
+
+// Spec review actions
+const (
+	ReviewSubmit  = "submit"
+	ReviewApprove = "approve"
+	ReviewReject  = "reject"
+)
+
+// reviewTransitions maps each review action to the statuses it may be taken
+// from and the status it leads to. Rejected specs are revised and resubmitted.
+var reviewTransitions = map[string]struct {
+	from []string
+	to   string
+}{
+	ReviewSubmit:  {[]string{spec.StatusDraft, spec.StatusRejected}, spec.StatusInReview},
+	ReviewApprove: {[]string{spec.StatusInReview}, spec.StatusApproved},
+	ReviewReject:  {[]string{spec.StatusInReview}, spec.StatusRejected},
+}
+
+// AuditEntry is one line of the review audit log
+type AuditEntry struct {
+	Time     time.Time `json:"time"`
+	Document string    `json:"document"`
+	Action   string    `json:"action"`
+	By       string    `json:"by"`
+	Comment  string    `json:"comment,omitempty"`
+	From     string    `json:"from"`
+	To       string    `json:"to"`
+}
+
+// SpecReviewResult describes an applied review decision
+type SpecReviewResult struct {
+	SpecPath string
+	From     string
+	To       string
+	Review   frontmatter.Review
+}
+
+// ReviewSpec records a review decision on a spec: the status moves
+// Draft → In Review → Approved or Rejected, the decision is appended to the
+// spec's frontmatter and to the project audit log. by defaults to the git user.
+func ReviewSpec(ref, action, by, comment string) (*SpecReviewResult, error) {
+	transition, ok := reviewTransitions[action]
+	if !ok {
+		return nil, fmt.Errorf("unknown review action %q (use submit, approve or reject)", action)
+	}
+	if action == ReviewReject && strings.TrimSpace(comment) == "" {
+		return nil, fmt.Errorf("a comment explaining the rejection is required")
+	}
+
+	root, err := manager.FindProjectRoot()
+	if err != nil {
+		return nil, fmt.Errorf("failed to find project root: %w", err)
+	}
+
+	s, path, err := LoadSpec(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	from := s.Status
+	if from == "" {
+		from = spec.StatusDraft
+	}
+	if !slices.Contains(transition.from, from) {
+		return nil, fmt.Errorf("cannot %s spec %s: it is %s (expected %s)", action, filepath.Base(path), from, strings.Join(transition.from, " or "))
+	}
+
+	if by == "" {
+		by = detectAuthor(root)
+	}
+	review := frontmatter.Review{
+		Action:  action,
+		By:      by,
+		Comment: strings.TrimSpace(comment),
+		At:      time.Now().UTC().Truncate(time.Second),
+	}
+
+	if _, err := UpdateDocumentMeta(path, func(m *frontmatter.Meta) {
+		m.Status = transition.to
+		m.Reviews = append(m.Reviews, review)
+	}); err != nil {
+		return nil, err
+	}
+
+	entry := AuditEntry{
+		Time:     review.At,
+		Document: filepath.Base(path),
+		Action:   action,
+		By:       by,
+		Comment:  review.Comment,
+		From:     from,
+		To:       transition.to,
+	}
+	if err := appendAuditLog(root, entry); err != nil {
+		return nil, err
+	}
+
+	return &SpecReviewResult{SpecPath: path, From: from, To: transition.to, Review: review}, nil
+}
+
+// appendAuditLog appends an entry to the project audit log
+func appendAuditLog(root string, entry AuditEntry) error {
+	path := manager.AuditLogPath(root)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit entry: %w", err)
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+	return nil
+}
+
+// ReadAuditLog returns the audit entries for document (a filename), or all
+// entries when document is empty
+func ReadAuditLog(document string) ([]AuditEntry, error) {
+	root, err := manager.FindProjectRoot()
+	if err != nil {
+		return nil, fmt.Errorf("failed to find project root: %w", err)
+	}
+
+	data, err := os.ReadFile(manager.AuditLogPath(root))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read audit log: %w", err)
+	}
+
+	var entries []AuditEntry
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		var e AuditEntry
+		if err := json.Unmarshal([]byte(line), &e); err != nil {
+			return nil, fmt.Errorf("failed to parse audit log: %w", err)
+		}
+		if document == "" || e.Document == filepath.Base(document) {
+			entries = append(entries, e)
+		}
+	}
+	return entries, nil
+}
+
+// FormatReviewHistory renders the review entries of a document
+func FormatReviewHistory(document string, entries []AuditEntry) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("# Reviews: %s\n\n", filepath.Base(document)))
+	if len(entries) == 0 {
+		sb.WriteString("No reviews recorded.\n")
+		return sb.String()
+	}
+	for _, e := range entries {
+		sb.WriteString(fmt.Sprintf("- %s  %-8s %-10s %s → %s\n", e.Time.Local().Format("2006-01-02 15:04"), e.Action, e.By, e.From, e.To))
+		if e.Comment != "" {
+			sb.WriteString(fmt.Sprintf("  > %s\n", e.Comment))
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,80 @@
+package ops
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tuanpep/oplusflow/internal/spec"
+)
+
+func TestReviewSpec(t *testing.T) {
+	tmpDir := t.TempDir()
+	specsDir := filepath.Join(tmpDir, "opusflow-planning", "specs")
+	if err := os.MkdirAll(specsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	oldWd, _ := os.Getwd()
+	os.Chdir(tmpDir)
+	defer os.Chdir(oldWd)
+
+	specPath := filepath.Join(specsDir, "spec-oauth.md")
+	legacy := "# Feature Specification: OAuth\n\n**Status**: 📝 Draft\n\n## Goal\nLogin\n"
+	if err := os.WriteFile(specPath, []byte(legacy), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	steps := []struct {
+		action  string
+		by      string
+		comment string
+		want    string
+		wantErr string
+	}{
+		{action: ReviewApprove, by: "alice", wantErr: "it is Draft"},
+		{action: ReviewSubmit, by: "carol", want: spec.StatusInReview},
+		{action: ReviewReject, by: "bob", wantErr: "comment"},
+		{action: ReviewReject, by: "bob", comment: "FR2 is vague", want: spec.StatusRejected},
+		{action: ReviewSubmit, by: "carol", comment: "Clarified FR2", want: spec.StatusInReview},
+		{action: ReviewApprove, by: "alice", want: spec.StatusApproved},
+		{action: ReviewSubmit, by: "carol", wantErr: "it is Approved"},
+		{action: "merge", wantErr: "unknown review action"},
+	}
+
+	for _, st := range steps {
+		result, err := ReviewSpec("spec-oauth.md", st.action, st.by, st.comment)
+		if st.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), st.wantErr) {
+				t.Fatalf("%s: expected error %q, got %v", st.action, st.wantErr, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", st.action, err)
+		}
+		if result.To != st.want {
+			t.Errorf("%s: expected %s, got %s", st.action, st.want, result.To)
+		}
+	}
+
+	s, _, err := LoadSpec("spec-oauth.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Status != spec.StatusApproved || s.Meta == nil || len(s.Meta.Reviews) != 4 {
+		t.Fatalf("Expected approved spec with 4 reviews, got %q %+v", s.Status, s.Meta)
+	}
+	if r := s.Meta.Reviews[1]; r.Action != ReviewReject || r.By != "bob" || r.Comment != "FR2 is vague" {
+		t.Errorf("Unexpected rejection record: %+v", r)
+	}
+
+	entries, err := ReadAuditLog("spec-oauth.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 4 || entries[3].From != spec.StatusInReview || entries[3].To != spec.StatusApproved || entries[3].By != "alice" {
+		t.Errorf("Unexpected audit log: %+v", entries)
+	}
+}
@@ -1,8 +1,13 @@
 package ops
 
 import (
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+
+	"github.com/tuanpep/oplusflow/internal/spec"
+	"github.com/tuanpep/oplusflow/internal/templates"
 )
 
 func TestSlugify(t *testing.T) {
@@ -50,6 +55,8 @@ func TestTruncateContent(t *testing.T) {
 		{"exact length", "hello", 5, "hello"},
 		{"needs truncation", "hello world", 5, "hello\n... (truncated)"},
 		{"empty", "", 10, ""},
+		{"cuts at line break", "line one\nline two", 12, "line one\n... (truncated)"},
+		{"keeps runes whole", "héllo", 2, "h\n... (truncated)"},
 	}
 
 	for _, tt := range tests {
@@ -63,12 +70,15 @@ func TestTruncateContent(t *testing.T) {
 }
 
 func TestGenerateSpecContent(t *testing.T) {
-	content := generateSpecContent(
-		"Add OAuth2",
-		"Add OAuth2 authentication to the API",
-		"10 files, 500 lines",
-		"### config.yaml\n```yaml\nkey: value\n```",
-	)
+	content, err := generateSpecContent("api", templates.APISpecTemplate, SpecTemplateData{
+		Title:           "Add OAuth2",
+		Query:           "Add OAuth2 authentication to the API",
+		CodebaseSummary: "10 files, 500 lines",
+		Context:         "### config.yaml\n```yaml\nkey: value\n```",
+	})
+	if err != nil {
+		t.Fatalf("generateSpecContent failed: %v", err)
+	}
 
 	// Check key sections exist
 	sections := []string{
@@ -101,6 +111,98 @@ func TestGenerateSpecContent(t *testing.T) {
 	}
 }
 
+func TestSpecTemplates(t *testing.T) {
+	tests := []struct {
+		specType string
+		sections []string
+		excluded string
+	}{
+		{"feature", []string{"## User Stories", "## Non-Functional Requirements"}, "409 Conflict"},
+		{"api", []string{"## API Contract", "409 Conflict"}, ""},
+		{"cli", []string{"## Command Interface", "Exit Codes"}, "409 Conflict"},
+		{"library", []string{"## Public API", "## Compatibility"}, "401/403"},
+		{"ui", []string{"## User Flows", "**Accessibility**"}, "409 Conflict"},
+		{"bugfix", []string{"## Current Behavior", "## Expected Behavior", "## Reproduction Steps"}, "## User Stories"},
+		{"migration", []string{"## Rollback Plan", "## Data Validation"}, "## User Stories"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.specType, func(t *testing.T) {
+			content, err := generateSpecContent(tt.specType, templates.SpecTemplates[tt.specType], SpecTemplateData{Title: "X", Query: "Do X"})
+			if err != nil {
+				t.Fatalf("generateSpecContent failed: %v", err)
+			}
+
+			// Every type keeps the sections spec check scores and planning reads
+			required := []string{"# Feature Specification: X", "## Goal", "### Functional Requirements", "## Edge Cases", "## Success Criteria", "## Open Questions"}
+			for _, want := range append(required, tt.sections...) {
+				if !strings.Contains(content, want) {
+					t.Errorf("Expected %q in %s spec", want, tt.specType)
+				}
+			}
+			if tt.excluded != "" && strings.Contains(content, tt.excluded) {
+				t.Errorf("Did not expect %q in %s spec", tt.excluded, tt.specType)
+			}
+
+			s := spec.Parse(content)
+			if s.Title != "X" || len(s.FunctionalRequirements) != 3 || len(s.EdgeCases) != 3 || len(s.SuccessCriteria) == 0 {
+				t.Errorf("Expected %s spec to parse, got title %q, %d requirements, %d edge cases, %d criteria", tt.specType, s.Title, len(s.FunctionalRequirements), len(s.EdgeCases), len(s.SuccessCriteria))
+			}
+		})
+	}
+}
+
+func TestCreateSpec_Types(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(tmpDir, ".agent"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	oldWd, _ := os.Getwd()
+	os.Chdir(tmpDir)
+	defer os.Chdir(oldWd)
+
+	result, err := CreateSpec("Export command", "Add an export subcommand", SpecOptions{Type: "cli"})
+	if err != nil {
+		t.Fatalf("CreateSpec failed: %v", err)
+	}
+	content, _ := os.ReadFile(result.FullPath)
+	if !strings.Contains(string(content), "## Command Interface") || !strings.Contains(string(content), "- cli\n") {
+		t.Errorf("Expected a cli spec tagged with its type, got:\n%s", content)
+	}
+
+	if _, err := CreateSpec("X", "Y", SpecOptions{Type: "pipeline"}); err == nil || !strings.Contains(err.Error(), "available: api, bugfix, cli") {
+		t.Errorf("Expected unknown type error listing templates, got %v", err)
+	}
+
+	// Project templates override built-ins and add new types
+	templatesDir := filepath.Join(tmpDir, ".opusflow", "templates")
+	if err := os.MkdirAll(templatesDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	custom := "# Feature Specification: {{ .Title }}\n\n## Goal\n\n> {{ .Query }} ({{ .Type }})\n"
+	if err := os.WriteFile(filepath.Join(templatesDir, "spec-pipeline.md"), []byte(custom), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err = CreateSpec("Nightly ETL", "Load events", SpecOptions{Type: "pipeline"})
+	if err != nil {
+		t.Fatalf("CreateSpec failed: %v", err)
+	}
+	content, _ = os.ReadFile(result.FullPath)
+	if !strings.HasSuffix(string(content), "---\n# Feature Specification: Nightly ETL\n\n## Goal\n\n> Load events (pipeline)\n") {
+		t.Errorf("Expected project template to be used, got:\n%s", content)
+	}
+
+	var names []string
+	for _, info := range ListSpecTemplates(tmpDir) {
+		names = append(names, info.Name)
+	}
+	if strings.Join(names, ",") != "api,bugfix,cli,feature,library,migration,pipeline,ui" {
+		t.Errorf("Unexpected spec templates: %v", names)
+	}
+}
+
 func TestGenerateSpecPrompt_Error(t *testing.T) {
 	// Test with non-existent file
 	_, err := GenerateSpecPrompt("nonexistent-file.md")
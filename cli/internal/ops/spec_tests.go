@@ -0,0 +1,440 @@
+package ops
+
+import (
+	"fmt"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/tuanpep/oplusflow/internal/manager"
+	"github.com/tuanpep/oplusflow/internal/plan"
+	"github.com/tuanpep/oplusflow/internal/spec"
+)
+
+// Acceptance test output languages
+const (
+	TestLangGo      = "go"
+	TestLangGherkin = "gherkin"
+)
+
+// acceptanceTaskPrefix marks queue tasks created for acceptance test stubs
+const acceptanceTaskPrefix = "test-"
+
+// AcceptanceTestOptions are the settings for GenerateAcceptanceTests
+type AcceptanceTestOptions struct {
+	// Lang is TestLangGo (default) or TestLangGherkin
+	Lang string
+	// OutDir is the output directory relative to the project root;
+	// defaults to "acceptance" for Go and "features" for Gherkin
+	OutDir string
+	// Package is the Go package name; defaults to the package already in
+	// OutDir, or the directory name
+	Package string
+	// Plan is the plan whose task queue receives the stubs; defaults to the
+	// only decomposed plan linked to the spec
+	Plan string
+	// Force overwrites an existing test file
+	Force bool
+}
+
+// AcceptanceCase is one generated test case
+type AcceptanceCase struct {
+	ID       string `json:"id"`   // SC1, or EC1 for the first edge case row
+	Name     string `json:"name"` // test case name, starting with the ID
+	Text     string `json:"text"`
+	Expected string `json:"expected,omitempty"` // edge cases only
+	// Implements lists the spec requirement IDs the case checks
+	Implements []string `json:"implements,omitempty"`
+	Run        string   `json:"run"` // command or scenario that runs the case
+}
+
+// AcceptanceTestResult describes generated acceptance test skeletons
+type AcceptanceTestResult struct {
+	SpecPath   string           `json:"spec_path"`
+	FilePath   string           `json:"file_path"`
+	Lang       string           `json:"lang"`
+	Cases      []AcceptanceCase `json:"cases"`
+	PlanRef    string           `json:"plan_ref,omitempty"`
+	TasksAdded int              `json:"tasks_added"`
+}
+
+var nonIdentPattern = regexp.MustCompile(`[^A-Za-z0-9]+`)
+
+// GenerateAcceptanceTests writes test skeletons for a spec: one case per
+// success criterion and per edge case row, named after its requirement ID.
+// The stubs are added at the front of the linked plan's task queue so they
+// are implemented before the feature itself.
+func GenerateAcceptanceTests(specRef string, opts AcceptanceTestOptions) (*AcceptanceTestResult, error) {
+	root, err := manager.FindProjectRoot()
+	if err != nil {
+		return nil, fmt.Errorf("failed to find project root: %w", err)
+	}
+
+	if opts.Lang == "" {
+		opts.Lang = TestLangGo
+	}
+	if opts.Lang != TestLangGo && opts.Lang != TestLangGherkin {
+		return nil, fmt.Errorf("unknown test language %q (use go or gherkin)", opts.Lang)
+	}
+	if opts.OutDir == "" {
+		opts.OutDir = "acceptance"
+		if opts.Lang == TestLangGherkin {
+			opts.OutDir = "features"
+		}
+	}
+
+	s, specPath, err := LoadSpec(specRef)
+	if err != nil {
+		return nil, err
+	}
+	title := s.Title
+	if title == "" {
+		title = strings.TrimSuffix(filepath.Base(specPath), ".md")
+	}
+
+	cases := acceptanceCases(s)
+	if len(cases) == 0 {
+		return nil, fmt.Errorf("spec %s has no filled-in success criteria or edge cases", filepath.Base(specPath))
+	}
+
+	outDir := opts.OutDir
+	if !filepath.IsAbs(outDir) {
+		outDir = filepath.Join(root, outDir)
+	}
+	slug := strings.Trim(nonIdentPattern.ReplaceAllString(strings.ToLower(title), "_"), "_")
+
+	var filePath, content string
+	switch opts.Lang {
+	case TestLangGo:
+		pkg := opts.Package
+		if pkg == "" {
+			pkg = goPackageName(outDir)
+		}
+		filePath = filepath.Join(outDir, slug+"_acceptance_test.go")
+		content, err = renderGoAcceptanceTests(pkg, goIdentifier(title), filepath.Base(specPath), title, relToRoot(root, outDir), cases)
+		if err != nil {
+			return nil, err
+		}
+	case TestLangGherkin:
+		filePath = filepath.Join(outDir, slug+".feature")
+		content = renderGherkinFeature(title, s.Goal, filepath.Base(specPath), relToRoot(root, filePath), cases)
+	}
+
+	// Resolve the plan first so a bad --plan leaves no test file behind
+	planRef, err := acceptanceTaskPlan(opts.Plan, filepath.Base(specPath))
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := os.Stat(filePath); err == nil && !opts.Force {
+		return nil, fmt.Errorf("%s already exists; use --force to overwrite it", relToRoot(root, filePath))
+	}
+	if err := os.MkdirAll(outDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create output directory: %w", err)
+	}
+	if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+		return nil, fmt.Errorf("failed to write tests: %w", err)
+	}
+
+	result := &AcceptanceTestResult{SpecPath: specPath, FilePath: filePath, Lang: opts.Lang, Cases: cases}
+	if planRef != "" {
+		added, err := addAcceptanceTasks(planRef, relToRoot(root, filePath), cases)
+		if err != nil {
+			return nil, err
+		}
+		result.PlanRef = planRef
+		result.TasksAdded = added
+	}
+
+	return result, nil
+}
+
+// acceptanceCases lists the spec's filled-in success criteria and edge case rows
+func acceptanceCases(s *spec.Spec) []AcceptanceCase {
+	var cases []AcceptanceCase
+	for _, sc := range s.FilledSuccessCriteria() {
+		cases = append(cases, AcceptanceCase{
+			ID:         sc.ID,
+			Name:       caseName(sc.ID, sc.Text),
+			Text:       sc.Text,
+			Implements: []string{sc.ID},
+		})
+	}
+
+	n := 0
+	for _, ec := range s.EdgeCases {
+		if spec.IsPlaceholder(ec.Case) || spec.IsPlaceholder(ec.Expected) {
+			continue
+		}
+		n++
+		id := fmt.Sprintf("EC%d", n)
+
+		// Edge cases implement the requirements they mention
+		var implements []string
+		for _, ref := range plan.RequirementIDs(ec.Case + " " + ec.Expected) {
+			if s.Requirement(ref) != nil && !slices.Contains(implements, ref) {
+				implements = append(implements, ref)
+			}
+		}
+		cases = append(cases, AcceptanceCase{
+			ID:         id,
+			Name:       caseName(id, ec.Case),
+			Text:       ec.Case,
+			Expected:   ec.Expected,
+			Implements: implements,
+		})
+	}
+	return cases
+}
+
+// caseName builds a test case name from an ID and the first words of its text
+func caseName(id, text string) string {
+	words := strings.Fields(strings.ToLower(nonIdentPattern.ReplaceAllString(text, " ")))
+	if len(words) > 6 {
+		words = words[:6]
+	}
+	return strings.Join(append([]string{id}, words...), "_")
+}
+
+// goIdentifier converts a title to an exported Go identifier ("OAuth login" → "OAuthLogin")
+func goIdentifier(title string) string {
+	var sb strings.Builder
+	for _, w := range strings.Fields(nonIdentPattern.ReplaceAllString(title, " ")) {
+		r := []rune(w)
+		r[0] = unicode.ToUpper(r[0])
+		sb.WriteString(string(r))
+	}
+	id := sb.String()
+	if id == "" || unicode.IsDigit(rune(id[0])) {
+		id = "Spec" + id
+	}
+	return id
+}
+
+// goPackageName returns the package declared by Go files already in dir,
+// or a name derived from the directory
+func goPackageName(dir string) string {
+	matches, _ := filepath.Glob(filepath.Join(dir, "*.go"))
+	for _, m := range matches {
+		f, err := parser.ParseFile(token.NewFileSet(), m, nil, parser.PackageClauseOnly)
+		if err == nil {
+			return strings.TrimSuffix(f.Name.Name, "_test")
+		}
+	}
+
+	name := strings.ToLower(nonIdentPattern.ReplaceAllString(filepath.Base(dir), ""))
+	if name == "" || unicode.IsDigit(rune(name[0])) {
+		return "acceptance"
+	}
+	return name
+}
+
+// renderGoAcceptanceTests renders table-driven Go tests whose cases skip until implemented
+func renderGoAcceptanceTests(pkg, ident, specName, title, relDir string, cases []AcceptanceCase) (string, error) {
+	var criteria, edges []AcceptanceCase
+	for _, c := range cases {
+		if c.Expected == "" {
+			criteria = append(criteria, c)
+		} else {
+			edges = append(edges, c)
+		}
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("package %s\n\n", pkg))
+	sb.WriteString("import \"testing\"\n\n")
+	sb.WriteString(fmt.Sprintf("// Acceptance tests for %s (%s), generated by opusflow spec tests.\n", title, specName))
+	sb.WriteString("// Replace each skip with a real check; cases are named after their requirement ID.\n")
+
+	pkgPath := "./" + relDir
+	if relDir == "." {
+		pkgPath = "."
+	}
+
+	if len(criteria) > 0 {
+		fn := "Test" + ident + "SuccessCriteria"
+		sb.WriteString(fmt.Sprintf("\nfunc %s(t *testing.T) {\n\ttests := []struct {\n\t\tname      string\n\t\tcriterion string\n\t}{\n", fn))
+		for _, c := range criteria {
+			sb.WriteString(fmt.Sprintf("\t\t{%q, %q},\n", c.Name, c.Text))
+		}
+		sb.WriteString("\t}\n\n\tfor _, tt := range tests {\n\t\tt.Run(tt.name, func(t *testing.T) {\n")
+		sb.WriteString("\t\t\tt.Skipf(\"not implemented: %s\", tt.criterion)\n\t\t})\n\t}\n}\n")
+		for i := range cases {
+			if cases[i].Expected == "" {
+				cases[i].Run = fmt.Sprintf("go test %s -run '%s/%s'", pkgPath, fn, cases[i].ID+"_")
+			}
+		}
+	}
+
+	if len(edges) > 0 {
+		fn := "Test" + ident + "EdgeCases"
+		sb.WriteString(fmt.Sprintf("\nfunc %s(t *testing.T) {\n\ttests := []struct {\n\t\tname     string\n\t\tinput    string\n\t\texpected string\n\t}{\n", fn))
+		for _, c := range edges {
+			sb.WriteString(fmt.Sprintf("\t\t{%q, %q, %q},\n", c.Name, c.Text, c.Expected))
+		}
+		sb.WriteString("\t}\n\n\tfor _, tt := range tests {\n\t\tt.Run(tt.name, func(t *testing.T) {\n")
+		sb.WriteString("\t\t\tt.Skipf(\"not implemented: %s: %s\", tt.input, tt.expected)\n\t\t})\n\t}\n}\n")
+		for i := range cases {
+			if cases[i].Expected != "" {
+				cases[i].Run = fmt.Sprintf("go test %s -run '%s/%s'", pkgPath, fn, cases[i].ID+"_")
+			}
+		}
+	}
+
+	out, err := format.Source([]byte(sb.String()))
+	if err != nil {
+		return "", fmt.Errorf("failed to format generated tests: %w", err)
+	}
+	return string(out), nil
+}
+
+// renderGherkinFeature renders a feature file with one scenario per case
+func renderGherkinFeature(title, goal, specName, relPath string, cases []AcceptanceCase) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("# Generated by opusflow spec tests from %s\n", specName))
+	sb.WriteString(fmt.Sprintf("Feature: %s\n", title))
+	if goal != "" {
+		for _, line := range strings.Split(goal, "\n") {
+			sb.WriteString(fmt.Sprintf("  %s\n", strings.TrimSpace(line)))
+		}
+	}
+
+	for i, c := range cases {
+		scenario := fmt.Sprintf("%s - %s", c.ID, c.Text)
+		sb.WriteString(fmt.Sprintf("\n  @%s\n  Scenario: %s\n", c.ID, scenario))
+		if c.Expected == "" {
+			sb.WriteString("    Given TODO: the preconditions\n")
+			sb.WriteString("    When TODO: the action\n")
+			sb.WriteString(fmt.Sprintf("    Then %s\n", c.Text))
+		} else {
+			sb.WriteString(fmt.Sprintf("    Given %s\n", c.Text))
+			sb.WriteString("    When TODO: the action\n")
+			sb.WriteString(fmt.Sprintf("    Then %s\n", c.Expected))
+		}
+		cases[i].Run = fmt.Sprintf("scenario @%s in %s", c.ID, relPath)
+	}
+	return sb.String()
+}
+
+// acceptanceTaskPlan picks the plan whose queue receives the test tasks:
+// the requested one, or the only decomposed plan linked to the spec.
+// It returns "" when no plan qualifies.
+func acceptanceTaskPlan(planRef, specName string) (string, error) {
+	if planRef != "" {
+		planPath, err := ResolvePlanPath(planRef)
+		if err != nil {
+			return "", err
+		}
+		if _, err := LoadTaskQueue(filepath.Base(planPath)); err != nil {
+			return "", fmt.Errorf("plan %s has no task queue; decompose it first", filepath.Base(planPath))
+		}
+		return filepath.Base(planPath), nil
+	}
+
+	summaries, err := ListPlans(false)
+	if err != nil {
+		return "", err
+	}
+	var linked []string
+	for _, summary := range summaries {
+		if !planLinkedToSpec(summary, specName) {
+			continue
+		}
+		if _, err := LoadTaskQueue(summary.Filename); err == nil {
+			linked = append(linked, summary.Filename)
+		}
+	}
+
+	switch len(linked) {
+	case 0:
+		return "", nil
+	case 1:
+		return linked[0], nil
+	}
+	return "", fmt.Errorf("several plans implement this spec (%s); choose one with --plan", strings.Join(linked, ", "))
+}
+
+// addAcceptanceTasks puts one pending task per case at the front of a plan's
+// task queue. Tasks from an earlier run are replaced, keeping their status.
+func addAcceptanceTasks(planRef, relPath string, cases []AcceptanceCase) (int, error) {
+	tq, err := LoadTaskQueue(planRef)
+	if err != nil {
+		return 0, err
+	}
+
+	previous := make(map[string]Task)
+	var rest []Task
+	for _, t := range tq.Tasks {
+		if strings.HasPrefix(t.ID, acceptanceTaskPrefix) {
+			previous[t.ID] = t
+			continue
+		}
+		rest = append(rest, t)
+	}
+
+	tasks := make([]Task, 0, len(cases)+len(rest))
+	for _, c := range cases {
+		title := fmt.Sprintf("Acceptance test %s: %s", c.ID, c.Text)
+		desc := fmt.Sprintf("Replace the skipped case %s in %s with a real test of: %s", c.Name, relPath, c.Text)
+		if c.Expected != "" {
+			desc += fmt.Sprintf(" → %s", c.Expected)
+		}
+		desc += ". The test should fail until the feature is implemented."
+
+		t := Task{
+			ID:           acceptanceTaskPrefix + c.ID,
+			Title:        title,
+			Description:  desc,
+			Files:        []string{relPath},
+			Dependencies: []string{},
+			Status:       TaskStatusPending,
+			Verification: []string{c.Run},
+			Implements:   c.Implements,
+		}
+		if prev, ok := previous[t.ID]; ok {
+			t.Status = prev.Status
+			t.Agent = prev.Agent
+		}
+		tasks = append(tasks, t)
+	}
+	tasks = append(tasks, rest...)
+
+	completed := 0
+	for i := range tasks {
+		tasks[i].Order = i + 1
+		if tasks[i].Status == TaskStatusDone {
+			completed++
+		}
+	}
+	tq.Tasks = tasks
+	tq.TotalSteps = len(tasks)
+	tq.CompletedSteps = completed
+	tq.UpdatedAt = time.Now()
+
+	if err := tq.Save(); err != nil {
+		return 0, fmt.Errorf("failed to update task queue: %w", err)
+	}
+	return len(cases), nil
+}
+
+// FormatAcceptanceTestResult summarizes generated acceptance tests
+func FormatAcceptanceTestResult(r *AcceptanceTestResult) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("✅ Generated %d acceptance test cases: %s\n", len(r.Cases), r.FilePath))
+	for _, c := range r.Cases {
+		sb.WriteString(fmt.Sprintf("   - %s\n", c.Name))
+	}
+	if r.PlanRef != "" {
+		sb.WriteString(fmt.Sprintf("\n📋 Added %d tasks to the front of the %s task queue\n", r.TasksAdded, r.PlanRef))
+	} else {
+		sb.WriteString("\nNo decomposed plan is linked to this spec; no tasks were added (use --plan).\n")
+	}
+	return sb.String()
+}
@@ -0,0 +1,165 @@
+package ops
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tuanpep/oplusflow/internal/spec"
+)
+
+const acceptanceSpec = "# Feature Specification: OAuth Login\n\n**Status**: ✅ Approved\n\n" +
+	"## Goal\n\n> Let users sign in with Google\n\n" +
+	"### Functional Requirements\n\n- [ ] **FR1**: Redirect to the provider\n- [ ] **FR2**: Store the token\n\n" +
+	"## Edge Cases\n\n| Edge Case | Expected Behavior |\n|-----------|-------------------|\n" +
+	"| Provider is down (FR1) | Show \"try again\" |\n| [Empty input] | [Return validation error] |\n\n" +
+	"## Success Criteria\n\n- [ ] **SC1**: `GET /login` returns 302\n- [ ] **SC2**: [e.g., Docs updated]\n"
+
+func TestAcceptanceCases(t *testing.T) {
+	cases := acceptanceCases(spec.Parse(acceptanceSpec))
+	if len(cases) != 2 {
+		t.Fatalf("Expected one criterion and one edge case (placeholders skipped), got %+v", cases)
+	}
+	if cases[0].Name != "SC1_get_login_returns_302" || strings.Join(cases[0].Implements, ",") != "SC1" {
+		t.Errorf("Unexpected criterion case: %+v", cases[0])
+	}
+	if cases[1].ID != "EC1" || cases[1].Expected != "Show \"try again\"" || strings.Join(cases[1].Implements, ",") != "FR1" {
+		t.Errorf("Unexpected edge case: %+v", cases[1])
+	}
+}
+
+func TestGoIdentifier(t *testing.T) {
+	tests := []struct {
+		title    string
+		expected string
+	}{
+		{"OAuth login", "OAuthLogin"},
+		{"add-user auth", "AddUserAuth"},
+		{"2FA support", "Spec2FASupport"},
+		{"", "Spec"},
+	}
+
+	for _, tt := range tests {
+		if got := goIdentifier(tt.title); got != tt.expected {
+			t.Errorf("goIdentifier(%q) = %q; want %q", tt.title, got, tt.expected)
+		}
+	}
+}
+
+func TestGenerateAcceptanceTests(t *testing.T) {
+	tmpDir := t.TempDir()
+	specsDir := filepath.Join(tmpDir, "opusflow-planning", "specs")
+	if err := os.MkdirAll(specsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "go.mod"), []byte("module example.com/x\n\ngo 1.21\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	oldWd, _ := os.Getwd()
+	os.Chdir(tmpDir)
+	defer os.Chdir(oldWd)
+
+	if err := os.WriteFile(filepath.Join(specsDir, "spec-oauth.md"), []byte(acceptanceSpec), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// A bad --plan fails before anything is written
+	if _, err := GenerateAcceptanceTests("spec-oauth.md", AcceptanceTestOptions{Plan: "plan-99-missing.md"}); err == nil {
+		t.Fatal("Expected an error for a missing plan")
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, "acceptance", "oauth_login_acceptance_test.go")); !os.IsNotExist(err) {
+		t.Errorf("Expected no test file after a failed run, got %v", err)
+	}
+
+	// Without a decomposed plan only the file is written
+	result, err := GenerateAcceptanceTests("spec-oauth.md", AcceptanceTestOptions{})
+	if err != nil {
+		t.Fatalf("GenerateAcceptanceTests failed: %v", err)
+	}
+	if result.PlanRef != "" || filepath.Base(result.FilePath) != "oauth_login_acceptance_test.go" {
+		t.Errorf("Unexpected result: %+v", result)
+	}
+	content, _ := os.ReadFile(result.FilePath)
+	for _, want := range []string{
+		"package acceptance\n",
+		"func TestOAuthLoginSuccessCriteria(t *testing.T) {",
+		`{"SC1_get_login_returns_302", "` + "`GET /login` returns 302" + `"},`,
+		"func TestOAuthLoginEdgeCases(t *testing.T) {",
+		`{"EC1_provider_is_down_fr1", "Provider is down (FR1)", "Show \"try again\""},`,
+	} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("Expected %q in generated tests, got:\n%s", want, content)
+		}
+	}
+
+	if _, err := exec.LookPath("go"); err == nil {
+		cmd := exec.Command("go", "test", "./acceptance", "-run", "TestOAuthLoginSuccessCriteria/SC1_", "-v")
+		cmd.Dir = tmpDir
+		out, err := cmd.CombinedOutput()
+		if err != nil || !strings.Contains(string(out), "--- SKIP: TestOAuthLoginSuccessCriteria/SC1_get_login_returns_302") {
+			t.Errorf("Expected generated tests to compile and skip, got %v:\n%s", err, out)
+		}
+	}
+
+	if _, err := GenerateAcceptanceTests("spec-oauth.md", AcceptanceTestOptions{}); err == nil || !strings.Contains(err.Error(), "--force") {
+		t.Errorf("Expected existing file to be kept without --force, got %v", err)
+	}
+
+	// With a decomposed plan the stubs go to the front of its queue
+	created, err := CreatePlanFromSpec("spec-oauth.md", "", PlanOptions{}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tq, err := QuickDecomposeFromFile(created.FullPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	planTasks := len(tq.Tasks)
+
+	result, err = GenerateAcceptanceTests("spec-oauth.md", AcceptanceTestOptions{Lang: TestLangGherkin})
+	if err != nil {
+		t.Fatalf("GenerateAcceptanceTests failed: %v", err)
+	}
+	if result.PlanRef != created.Filename || result.TasksAdded != 2 {
+		t.Errorf("Expected 2 tasks added to %s, got %+v", created.Filename, result)
+	}
+	feature, _ := os.ReadFile(result.FilePath)
+	for _, want := range []string{"Feature: OAuth Login\n", "  @SC1\n  Scenario: SC1 - `GET /login` returns 302\n", "    Given Provider is down (FR1)\n    When TODO: the action\n    Then Show \"try again\"\n"} {
+		if !strings.Contains(string(feature), want) {
+			t.Errorf("Expected %q in feature file, got:\n%s", want, feature)
+		}
+	}
+
+	tq, _ = LoadTaskQueue(created.Filename)
+	tq.CompleteTask("test-SC1")
+	if err := tq.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Regenerating replaces the stub tasks and keeps their status
+	if _, err := GenerateAcceptanceTests("spec-oauth.md", AcceptanceTestOptions{Lang: TestLangGherkin, Force: true}); err != nil {
+		t.Fatal(err)
+	}
+	tq, _ = LoadTaskQueue(created.Filename)
+	if len(tq.Tasks) != planTasks+2 || tq.Tasks[0].ID != "test-SC1" || tq.Tasks[1].ID != "test-EC1" || tq.Tasks[2].ID != "task-1" {
+		t.Fatalf("Expected stub tasks before the plan tasks, got %+v", tq.Tasks)
+	}
+	if tq.Tasks[0].Status != TaskStatusDone || tq.CompletedSteps != 1 || tq.Tasks[2].Order != 3 {
+		t.Errorf("Expected stub status to be kept and tasks renumbered, got %+v", tq.Tasks)
+	}
+	if next := tq.GetNextTask(); next == nil || next.ID != "test-EC1" {
+		t.Errorf("Expected the pending stub to come first, got %+v", next)
+	}
+
+	// Plan step edits keep the stubs at the front
+	if _, err := AddPlanStep(created.Filename, 0, NewStep{Title: "Prepare config"}); err != nil {
+		t.Fatal(err)
+	}
+	tq, _ = LoadTaskQueue(created.Filename)
+	if len(tq.Tasks) != planTasks+3 || tq.Tasks[0].ID != "test-SC1" || tq.Tasks[2].Title != "Prepare config" {
+		t.Errorf("Expected stubs to survive plan renumbering, got %+v", tq.Tasks)
+	}
+}
@@ -0,0 +1,194 @@
+package ops
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// projectManifests are the files that mark a directory as a subproject
+var projectManifests = []string{"go.mod", "Cargo.toml", "package.json", "pyproject.toml", "setup.py", "Makefile"}
+
+// subprojectMaxDepth limits how deep discovery looks for manifests
+const subprojectMaxDepth = 4
+
+// Subproject is a directory of the repository with its own build and tests
+type Subproject struct {
+	Path      string           `json:"path"` // relative to the project root; "" for the root
+	Name      string           `json:"name"`
+	Manifests []string         `json:"manifests,omitempty"`
+	Commands  []ProjectCommand `json:"commands,omitempty"`
+}
+
+// DiscoverSubprojects returns the subprojects declared in the project config
+// or, when none are declared, every directory holding a manifest file. The
+// root's commands come from the top-level commands section, which makes the
+// root a subproject even without a manifest.
+func DiscoverSubprojects(rootDir string) ([]Subproject, error) {
+	cfg, err := LoadProjectConfig(rootDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var subs []Subproject
+	if len(cfg.Subprojects) > 0 {
+		for i, sc := range cfg.Subprojects {
+			path := filepath.ToSlash(filepath.Clean(sc.Path))
+			if path == "." {
+				path = ""
+			}
+			info, err := os.Stat(filepath.Join(rootDir, path))
+			if err != nil || !info.IsDir() {
+				return nil, fmt.Errorf("subprojects[%d]: %s is not a directory", i, sc.Path)
+			}
+			commands := sc.Commands
+			if path == "" && commands == nil {
+				commands = cfg.Commands
+			}
+			sub, err := newSubproject(rootDir, path, sc.Name, fmt.Sprintf("subprojects[%d].commands", i), commands)
+			if err != nil {
+				return nil, err
+			}
+			subs = append(subs, sub)
+		}
+		return subs, nil
+	}
+
+	dirs, err := findManifestDirs(rootDir)
+	if err != nil {
+		return nil, err
+	}
+	// Configured commands belong to the root even when it has no manifest
+	if len(cfg.Commands) > 0 && !slices.Contains(dirs, "") {
+		dirs = append([]string{""}, dirs...)
+	}
+	for _, dir := range dirs {
+		var commands map[string]CommandConfig
+		if dir == "" {
+			commands = cfg.Commands
+		}
+		sub, err := newSubproject(rootDir, dir, "", "commands", commands)
+		if err != nil {
+			return nil, err
+		}
+		subs = append(subs, sub)
+	}
+	return subs, nil
+}
+
+func newSubproject(rootDir, path, name, label string, commands map[string]CommandConfig) (Subproject, error) {
+	if name == "" {
+		name = path
+		if name == "" {
+			name = "."
+		}
+	}
+
+	sub := Subproject{Path: path, Name: name}
+	for _, m := range projectManifests {
+		if manifestExists(filepath.Join(rootDir, path), m) {
+			sub.Manifests = append(sub.Manifests, m)
+		}
+	}
+
+	cmds, err := resolveCommands(rootDir, path, label, commands)
+	if err != nil {
+		return Subproject{}, err
+	}
+	sub.Commands = cmds
+	return sub, nil
+}
+
+// findManifestDirs walks rootDir for directories containing a manifest,
+// skipping ignored directories and test fixtures
+func findManifestDirs(rootDir string) ([]string, error) {
+	ignore := NewIgnoreHandler(rootDir)
+	var dirs []string
+	err := filepath.Walk(rootDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || !info.IsDir() {
+			return nil
+		}
+		rel := relToRoot(rootDir, path)
+		if path != rootDir {
+			if ignore.ShouldIgnore(path, true) || info.Name() == "testdata" || strings.Count(rel, "/") >= subprojectMaxDepth {
+				return filepath.SkipDir
+			}
+		}
+		ignore.TrackDirectory(path)
+
+		for _, m := range projectManifests {
+			if manifestExists(path, m) {
+				if rel == "." {
+					rel = ""
+				}
+				dirs = append(dirs, rel)
+				break
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk directory: %w", err)
+	}
+	return dirs, nil
+}
+
+// OwningSubproject returns the path of the deepest subproject containing
+// file, and false when no subproject contains it
+func OwningSubproject(subs []Subproject, file string) (string, bool) {
+	best, found := "", false
+	for _, sub := range subs {
+		if sub.Path != "" && file != sub.Path && !strings.HasPrefix(file, sub.Path+"/") {
+			continue
+		}
+		if !found || len(sub.Path) > len(best) {
+			best, found = sub.Path, true
+		}
+	}
+	return best, found
+}
+
+// groupChangedFiles maps each subproject path to the changed files it owns
+func groupChangedFiles(subs []Subproject, files []string) map[string][]string {
+	groups := make(map[string][]string)
+	for _, f := range files {
+		if path, ok := OwningSubproject(subs, f); ok {
+			groups[path] = append(groups[path], f)
+		}
+	}
+	return groups
+}
+
+// changedFiles lists files modified since HEAD plus untracked files, relative
+// to root
+func changedFiles(root string) ([]string, error) {
+	diff := exec.Command("git", "diff", "--name-only", "--relative", "HEAD")
+	diff.Dir = root
+	modified, err := diff.Output()
+	if err != nil {
+		return nil, err
+	}
+
+	others := exec.Command("git", "ls-files", "--others", "--exclude-standard")
+	others.Dir = root
+	untracked, err := others.Output()
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	var files []string
+	for _, line := range strings.Split(string(modified)+"\n"+string(untracked), "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && !seen[line] {
+			seen[line] = true
+			files = append(files, line)
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
@@ -0,0 +1,177 @@
+package ops
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeTestFiles(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestDiscoverSubprojects(t *testing.T) {
+	root := t.TempDir()
+	writeTestFiles(t, root, map[string]string{
+		"go.mod":                          "module x\n",
+		"web/package.json":                `{"scripts": {"test": "jest"}}`,
+		"web/node_modules/a/package.json": "{}",
+		"services/api/Cargo.toml":         "[package]\n",
+		"internal/testdata/go.mod":        "module fixture\n",
+		".opusflow/config.yaml":           "commands:\n  lint:\n    skip: true\n",
+	})
+
+	subs, err := DiscoverSubprojects(root)
+	if err != nil {
+		t.Fatalf("DiscoverSubprojects failed: %v", err)
+	}
+
+	var got []string
+	for _, sub := range subs {
+		var kinds []string
+		for _, pc := range sub.Commands {
+			kinds = append(kinds, pc.Kind)
+		}
+		got = append(got, sub.Name+":"+strings.Join(sub.Manifests, ",")+":"+strings.Join(kinds, ","))
+	}
+	want := ".:go.mod:build,test services/api:Cargo.toml:build,typecheck,test web:package.json:test"
+	if strings.Join(got, " ") != want {
+		t.Errorf("DiscoverSubprojects() = %v; want %s", got, want)
+	}
+	if subs[2].Commands[0].CommandLine() != "cd web && npm test" {
+		t.Errorf("Expected web commands to run in web, got %q", subs[2].Commands[0].CommandLine())
+	}
+
+	// Declared subprojects replace discovery
+	writeTestFiles(t, root, map[string]string{
+		".opusflow/config.yaml": "subprojects:\n  - path: web\n    name: frontend\n    commands:\n      test: npm run test:unit\n",
+	})
+	subs, err = DiscoverSubprojects(root)
+	if err != nil {
+		t.Fatalf("DiscoverSubprojects failed: %v", err)
+	}
+	if len(subs) != 1 || subs[0].Name != "frontend" || subs[0].Commands[0].CommandLine() != "cd web && npm run test:unit" {
+		t.Errorf("Expected the declared subproject only, got %+v", subs)
+	}
+
+	// Configured root commands make the root a subproject without a manifest
+	bare := t.TempDir()
+	writeTestFiles(t, bare, map[string]string{
+		"web/package.json":      `{"scripts": {"test": "jest"}}`,
+		".opusflow/config.yaml": "commands:\n  build: ./build.sh\n  test: ./test.sh\n",
+	})
+	subs, err = DiscoverSubprojects(bare)
+	if err != nil {
+		t.Fatalf("DiscoverSubprojects failed: %v", err)
+	}
+	if len(subs) != 2 || subs[0].Path != "" || len(subs[0].Manifests) != 0 || len(subs[0].Commands) != 2 ||
+		subs[0].Commands[0].Run != "./build.sh" || subs[1].Path != "web" {
+		t.Errorf("Expected the root with its configured commands and web, got %+v", subs)
+	}
+
+	writeTestFiles(t, root, map[string]string{".opusflow/config.yaml": "subprojects:\n  - path: mobile\n"})
+	if _, err := DiscoverSubprojects(root); err == nil || !strings.Contains(err.Error(), "mobile is not a directory") {
+		t.Errorf("Expected missing subproject error, got %v", err)
+	}
+}
+
+func TestOwningSubproject(t *testing.T) {
+	subs := []Subproject{{Path: ""}, {Path: "cli"}, {Path: "cli/tools"}, {Path: "web"}}
+	tests := []struct {
+		file string
+		want string
+	}{
+		{"README.md", ""},
+		{"cli/main.go", "cli"},
+		{"cli/tools/gen.go", "cli/tools"},
+		{"client/app.ts", ""},
+		{"web/src/app.ts", "web"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			got, ok := OwningSubproject(subs, tt.file)
+			if !ok || got != tt.want {
+				t.Errorf("OwningSubproject(%q) = %q, %v; want %q", tt.file, got, ok, tt.want)
+			}
+		})
+	}
+
+	if _, ok := OwningSubproject(subs[1:], "README.md"); ok {
+		t.Error("Expected no owner for a file outside every subproject")
+	}
+}
+
+func TestAutoVerifyPlan_Subprojects(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	root := t.TempDir()
+	writeTestFiles(t, root, map[string]string{
+		".agent/.keep":  "",
+		"plan.md":       "# Plan\n",
+		"api/main.go":   "package main\n",
+		"web/app.ts":    "export {}\n",
+		"docs/guide.md": "# Guide\n",
+		".opusflow/config.yaml": "subprojects:\n" +
+			"  - path: api\n    commands:\n      build: \"true\"\n      test: \"false\"\n" +
+			"  - path: web\n    commands:\n      build: \"false\"\n",
+	})
+
+	oldWd, _ := os.Getwd()
+	os.Chdir(root)
+	defer os.Chdir(oldWd)
+
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"add", "-A"},
+		{"-c", "user.name=t", "-c", "user.email=t@example.com", "commit", "-qm", "init"},
+	} {
+		if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+
+	// Only api is affected by the change
+	writeTestFiles(t, root, map[string]string{"api/main.go": "package main\n\nfunc main() {}\n"})
+	result, err := AutoVerifyPlan(filepath.Join(root, "plan.md"), VerifyOptions{})
+	if err != nil {
+		t.Fatalf("AutoVerifyPlan failed: %v", err)
+	}
+
+	if len(result.Subprojects) != 2 || result.Subprojects[0].ChangedFiles != 1 || !result.Subprojects[1].Skipped {
+		t.Fatalf("Expected api verified and web skipped, got %+v", result.Subprojects)
+	}
+	if len(result.Comments) != 1 || result.Comments[0].Title != "api: Tests Failed" {
+		t.Errorf("Expected only the api test failure, got %+v", result.Comments)
+	}
+	if result.BuildStatus != "✅ Passed" || result.TestStatus != "❌ Failed" {
+		t.Errorf("Unexpected statuses: build %q, test %q", result.BuildStatus, result.TestStatus)
+	}
+	md := result.FormatMarkdown()
+	if !strings.Contains(md, "### api\n\n**Changed files**: 1\n**Build**: ✅ Passed\n**Tests**: ❌ Failed\n") || !strings.Contains(md, "### web\n\n**Changed files**: 0\n**Status**: ⏭️ Skipped") {
+		t.Errorf("Expected per-subproject sections, got:\n%s", md)
+	}
+
+	// Changes outside every subproject run nothing
+	exec.Command("git", "checkout", "--", "api/main.go").Run()
+	writeTestFiles(t, root, map[string]string{"docs/guide.md": "# Guide\n\nMore.\n"})
+	result, err = AutoVerifyPlan(filepath.Join(root, "plan.md"), VerifyOptions{})
+	if err != nil {
+		t.Fatalf("AutoVerifyPlan failed: %v", err)
+	}
+	if result.TotalChecks != 0 || !strings.HasPrefix(result.BuildStatus, "⏭️") {
+		t.Errorf("Expected no commands to run, got %d checks, build %q", result.TotalChecks, result.BuildStatus)
+	}
+}
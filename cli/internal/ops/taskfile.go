@@ -0,0 +1,348 @@
+package ops
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// TaskFileVersion is the current version of the structured task file schema
+const TaskFileVersion = 1
+
+// Task file formats
+const (
+	TaskFileFormatYAML = "yaml"
+	TaskFileFormatJSON = "json"
+)
+
+// TaskFile is the structured (YAML or JSON) representation of a task queue.
+// It is an alternative to decomposing markdown plan steps:
+//
+//	version: 1
+//	plan: plan-01-auth.md          # optional plan reference
+//	tasks:
+//	  - id: task-1                 # required, unique
+//	    title: Add login handler   # required
+//	    description: ...           # optional
+//	    files: [cmd/login.go]      # optional
+//	    dependencies: []           # optional, ids of other tasks
+//	    verification: [go test ./cmd/...]
+//	    actions: [Create]
+//	    implements: [FR1, SC2]     # optional, spec requirement IDs
+//	    status: pending            # optional, defaults to pending
+type TaskFile struct {
+	Version int            `yaml:"version" json:"version"`
+	Plan    string         `yaml:"plan,omitempty" json:"plan,omitempty"`
+	Tasks   []TaskFileTask `yaml:"tasks" json:"tasks"`
+}
+
+// TaskFileTask is a single task entry in a TaskFile
+type TaskFileTask struct {
+	ID           string   `yaml:"id" json:"id"`
+	Title        string   `yaml:"title" json:"title"`
+	Description  string   `yaml:"description,omitempty" json:"description,omitempty"`
+	Files        []string `yaml:"files,omitempty" json:"files,omitempty"`
+	Dependencies []string `yaml:"dependencies,omitempty" json:"dependencies,omitempty"`
+	Verification []string `yaml:"verification,omitempty" json:"verification,omitempty"`
+	Actions      []string `yaml:"actions,omitempty" json:"actions,omitempty"`
+	Implements   []string `yaml:"implements,omitempty" json:"implements,omitempty"`
+	Status       string   `yaml:"status,omitempty" json:"status,omitempty"`
+	Agent        string   `yaml:"agent,omitempty" json:"agent,omitempty"`
+}
+
+// TaskFileFormatFromPath derives the task file format from a file extension
+func TaskFileFormatFromPath(path string) (string, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return TaskFileFormatYAML, nil
+	case ".json":
+		return TaskFileFormatJSON, nil
+	default:
+		return "", fmt.Errorf("unsupported task file extension: %s (use .yaml, .yml or .json)", filepath.Ext(path))
+	}
+}
+
+// LoadTaskFile reads and validates a structured task file
+func LoadTaskFile(path string) (*TaskFile, error) {
+	format, err := TaskFileFormatFromPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read task file: %w", err)
+	}
+
+	tf, err := ParseTaskFile([]byte(content), format)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tf.Validate(); err != nil {
+		return nil, err
+	}
+
+	return tf, nil
+}
+
+// ParseTaskFile decodes a task file, rejecting unknown fields
+func ParseTaskFile(data []byte, format string) (*TaskFile, error) {
+	var tf TaskFile
+
+	switch format {
+	case TaskFileFormatYAML:
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err := dec.Decode(&tf); err != nil {
+			return nil, fmt.Errorf("failed to parse YAML task file: %w", err)
+		}
+	case TaskFileFormatJSON:
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&tf); err != nil {
+			return nil, fmt.Errorf("failed to parse JSON task file: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported task file format: %s", format)
+	}
+
+	return &tf, nil
+}
+
+// Validate checks the task file against the schema
+func (tf *TaskFile) Validate() error {
+	var problems []string
+
+	if tf.Version != 0 && tf.Version != TaskFileVersion {
+		problems = append(problems, fmt.Sprintf("unsupported version %d (expected %d)", tf.Version, TaskFileVersion))
+	}
+	if len(tf.Tasks) == 0 {
+		problems = append(problems, "no tasks defined")
+	}
+
+	ids := make(map[string]bool, len(tf.Tasks))
+	for i, t := range tf.Tasks {
+		label := fmt.Sprintf("tasks[%d]", i)
+		if t.ID == "" {
+			problems = append(problems, label+": missing id")
+		} else if ids[t.ID] {
+			problems = append(problems, fmt.Sprintf("%s: duplicate id %q", label, t.ID))
+		} else {
+			ids[t.ID] = true
+		}
+		if strings.TrimSpace(t.Title) == "" {
+			problems = append(problems, label+": missing title")
+		}
+		if t.Status != "" {
+			if err := ValidateTaskStatus(t.Status); err != nil {
+				problems = append(problems, fmt.Sprintf("%s: %v", label, err))
+			}
+		}
+	}
+
+	for i, t := range tf.Tasks {
+		for _, dep := range t.Dependencies {
+			switch {
+			case dep == t.ID:
+				problems = append(problems, fmt.Sprintf("tasks[%d]: %q depends on itself", i, t.ID))
+			case !ids[dep]:
+				problems = append(problems, fmt.Sprintf("tasks[%d]: unknown dependency %q", i, dep))
+			}
+		}
+	}
+
+	if len(problems) == 0 {
+		if cycle := findDependencyCycle(tf.Tasks); cycle != nil {
+			problems = append(problems, "dependency cycle: "+strings.Join(cycle, " -> "))
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid task file:\n  - %s", strings.Join(problems, "\n  - "))
+	}
+	return nil
+}
+
+// findDependencyCycle returns the ids forming a dependency cycle, or nil
+func findDependencyCycle(tasks []TaskFileTask) []string {
+	deps := make(map[string][]string, len(tasks))
+	for _, t := range tasks {
+		deps[t.ID] = t.Dependencies
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[string]int, len(tasks))
+	var stack []string
+
+	var visit func(id string) []string
+	visit = func(id string) []string {
+		state[id] = visiting
+		stack = append(stack, id)
+		for _, dep := range deps[id] {
+			switch state[dep] {
+			case visiting:
+				for i, s := range stack {
+					if s == dep {
+						return append(append([]string{}, stack[i:]...), dep)
+					}
+				}
+			case unvisited:
+				if cycle := visit(dep); cycle != nil {
+					return cycle
+				}
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[id] = visited
+		return nil
+	}
+
+	for _, t := range tasks {
+		if state[t.ID] == unvisited {
+			if cycle := visit(t.ID); cycle != nil {
+				return cycle
+			}
+		}
+	}
+	return nil
+}
+
+// ToTaskQueue converts a validated task file into a task queue
+func (tf *TaskFile) ToTaskQueue(planRef, planPath string) *TaskQueue {
+	tasks := make([]Task, 0, len(tf.Tasks))
+	completed := 0
+
+	for i, t := range tf.Tasks {
+		status := t.Status
+		if status == "" {
+			status = TaskStatusPending
+		}
+		if status == TaskStatusDone {
+			completed++
+		}
+
+		tasks = append(tasks, Task{
+			ID:           t.ID,
+			Title:        strings.TrimSpace(t.Title),
+			Description:  strings.TrimSpace(t.Description),
+			StepNumber:   i + 1,
+			Files:        nonNilStrings(t.Files),
+			Dependencies: nonNilStrings(t.Dependencies),
+			Verification: t.Verification,
+			Status:       status,
+			Order:        i + 1,
+			Actions:      t.Actions,
+			Implements:   t.Implements,
+			Agent:        t.Agent,
+		})
+	}
+
+	return &TaskQueue{
+		PlanRef:        planRef,
+		PlanPath:       planPath,
+		CreatedAt:      time.Now(),
+		UpdatedAt:      time.Now(),
+		Tasks:          tasks,
+		TotalSteps:     len(tasks),
+		CompletedSteps: completed,
+	}
+}
+
+// NewTaskFile converts a task queue into its structured file representation
+func NewTaskFile(tq *TaskQueue) *TaskFile {
+	tf := &TaskFile{
+		Version: TaskFileVersion,
+		Plan:    tq.PlanRef,
+		Tasks:   make([]TaskFileTask, 0, len(tq.Tasks)),
+	}
+
+	for _, t := range tq.Tasks {
+		tf.Tasks = append(tf.Tasks, TaskFileTask{
+			ID:           t.ID,
+			Title:        t.Title,
+			Description:  t.Description,
+			Files:        t.Files,
+			Dependencies: t.Dependencies,
+			Verification: t.Verification,
+			Actions:      t.Actions,
+			Implements:   t.Implements,
+			Status:       t.Status,
+			Agent:        t.Agent,
+		})
+	}
+
+	return tf
+}
+
+// Marshal encodes the task file in the given format
+func (tf *TaskFile) Marshal(format string) ([]byte, error) {
+	switch format {
+	case TaskFileFormatYAML:
+		var buf bytes.Buffer
+		enc := yaml.NewEncoder(&buf)
+		enc.SetIndent(2)
+		if err := enc.Encode(tf); err != nil {
+			return nil, fmt.Errorf("failed to encode YAML: %w", err)
+		}
+		if err := enc.Close(); err != nil {
+			return nil, fmt.Errorf("failed to encode YAML: %w", err)
+		}
+		return buf.Bytes(), nil
+	case TaskFileFormatJSON:
+		data, err := json.MarshalIndent(tf, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode JSON: %w", err)
+		}
+		return append(data, '\n'), nil
+	default:
+		return nil, fmt.Errorf("unsupported task file format: %s", format)
+	}
+}
+
+// DecomposeFromTaskFile loads a structured task file into a task queue and saves it.
+// planPath is optional; when empty the plan reference from the file is used.
+func DecomposeFromTaskFile(taskFilePath, planPath string) (*TaskQueue, error) {
+	tf, err := LoadTaskFile(taskFilePath)
+	if err != nil {
+		return nil, err
+	}
+
+	planRef := tf.Plan
+	if planPath != "" {
+		planPath = rootPath(planPath)
+		planRef = filepath.Base(planPath)
+	}
+	if planRef == "" {
+		return nil, fmt.Errorf("plan reference required: pass a plan file or set 'plan' in %s", taskFilePath)
+	}
+
+	tq := tf.ToTaskQueue(planRef, planPath)
+	if planPath != "" {
+		if rev, err := SnapshotPlan(planPath, RevisionReasonDecompose); err == nil {
+			tq.PlanRevision = rev.Number
+		}
+	}
+	if err := tq.Save(); err != nil {
+		return nil, fmt.Errorf("failed to save task queue: %w", err)
+	}
+
+	return tq, nil
+}
+
+// nonNilStrings returns s, or an empty slice if s is nil
+func nonNilStrings(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
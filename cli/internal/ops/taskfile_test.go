@@ -0,0 +1,175 @@
+package ops
+
+import (
+	"strings"
+	"testing"
+)
+
+const sampleTaskFileYAML = `version: 1
+plan: plan-01-auth.md
+tasks:
+  - id: setup
+    title: Setup project
+    description: Create the skeleton
+    files: [cmd/main.go]
+    verification:
+      - go build ./...
+    actions: [Create]
+  - id: login
+    title: Add login
+    files: [cmd/login.go]
+    dependencies: [setup]
+    implements: [FR1]
+    status: done
+    agent: aider
+`
+
+func TestParseTaskFile_YAML(t *testing.T) {
+	tf, err := ParseTaskFile([]byte(sampleTaskFileYAML), TaskFileFormatYAML)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := tf.Validate(); err != nil {
+		t.Fatalf("Unexpected validation error: %v", err)
+	}
+
+	if tf.Plan != "plan-01-auth.md" {
+		t.Errorf("Expected plan ref, got %q", tf.Plan)
+	}
+	if len(tf.Tasks) != 2 {
+		t.Fatalf("Expected 2 tasks, got %d", len(tf.Tasks))
+	}
+	if len(tf.Tasks[0].Verification) != 1 {
+		t.Errorf("Expected verification entries, got %v", tf.Tasks[0].Verification)
+	}
+}
+
+func TestParseTaskFile_UnknownField(t *testing.T) {
+	_, err := ParseTaskFile([]byte("tasks:\n  - id: a\n    title: A\n    owner: bob\n"), TaskFileFormatYAML)
+	if err == nil {
+		t.Error("Expected error for unknown field")
+	}
+
+	_, err = ParseTaskFile([]byte(`{"tasks":[{"id":"a","title":"A","owner":"bob"}]}`), TaskFileFormatJSON)
+	if err == nil {
+		t.Error("Expected error for unknown JSON field")
+	}
+}
+
+func TestTaskFile_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		tasks   []TaskFileTask
+		wantErr string
+	}{
+		{"empty", nil, "no tasks"},
+		{"missing id", []TaskFileTask{{Title: "A"}}, "missing id"},
+		{"missing title", []TaskFileTask{{ID: "a"}}, "missing title"},
+		{"duplicate id", []TaskFileTask{{ID: "a", Title: "A"}, {ID: "a", Title: "B"}}, "duplicate id"},
+		{"unknown dependency", []TaskFileTask{{ID: "a", Title: "A", Dependencies: []string{"x"}}}, "unknown dependency"},
+		{"self dependency", []TaskFileTask{{ID: "a", Title: "A", Dependencies: []string{"a"}}}, "depends on itself"},
+		{"bad status", []TaskFileTask{{ID: "a", Title: "A", Status: "blocked"}}, "unknown task status"},
+		{"cycle", []TaskFileTask{
+			{ID: "a", Title: "A", Dependencies: []string{"b"}},
+			{ID: "b", Title: "B", Dependencies: []string{"a"}},
+		}, "dependency cycle"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tf := &TaskFile{Tasks: tt.tasks}
+			err := tf.Validate()
+			if err == nil {
+				t.Fatal("Expected validation error")
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got: %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestTaskFile_ToTaskQueue(t *testing.T) {
+	tf, err := ParseTaskFile([]byte(sampleTaskFileYAML), TaskFileFormatYAML)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	tq := tf.ToTaskQueue("plan-01-auth.md", "")
+
+	if tq.TotalSteps != 2 || tq.CompletedSteps != 1 {
+		t.Errorf("Expected 2 steps with 1 completed, got %d/%d", tq.CompletedSteps, tq.TotalSteps)
+	}
+	if tq.Tasks[0].Status != TaskStatusPending {
+		t.Errorf("Expected default status pending, got %s", tq.Tasks[0].Status)
+	}
+	if tq.Tasks[1].StepNumber != 2 || tq.Tasks[1].Dependencies[0] != "setup" {
+		t.Errorf("Unexpected second task: %+v", tq.Tasks[1])
+	}
+	if tq.Tasks[1].Files == nil || tq.Tasks[0].Dependencies == nil {
+		t.Error("Expected non-nil slices")
+	}
+}
+
+func TestTaskFile_RoundTrip(t *testing.T) {
+	for _, format := range []string{TaskFileFormatYAML, TaskFileFormatJSON} {
+		t.Run(format, func(t *testing.T) {
+			tf, err := ParseTaskFile([]byte(sampleTaskFileYAML), TaskFileFormatYAML)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			tq := tf.ToTaskQueue(tf.Plan, "")
+
+			data, err := NewTaskFile(tq).Marshal(format)
+			if err != nil {
+				t.Fatalf("Marshal failed: %v", err)
+			}
+
+			back, err := ParseTaskFile(data, format)
+			if err != nil {
+				t.Fatalf("Re-parse failed: %v\n%s", err, data)
+			}
+			if err := back.Validate(); err != nil {
+				t.Fatalf("Re-validate failed: %v", err)
+			}
+
+			if back.Plan != tf.Plan || len(back.Tasks) != len(tf.Tasks) {
+				t.Fatalf("Round trip mismatch: %+v", back)
+			}
+			if back.Tasks[1].Status != TaskStatusDone {
+				t.Errorf("Expected status to survive round trip, got %q", back.Tasks[1].Status)
+			}
+			if back.Tasks[0].Verification[0] != "go build ./..." {
+				t.Errorf("Expected verification to survive round trip, got %v", back.Tasks[0].Verification)
+			}
+			if back.Tasks[1].Agent != "aider" || len(back.Tasks[1].Implements) != 1 || back.Tasks[1].Implements[0] != "FR1" {
+				t.Errorf("Expected agent and implements to survive round trip, got %+v", back.Tasks[1])
+			}
+		})
+	}
+}
+
+func TestTaskFileFormatFromPath(t *testing.T) {
+	tests := []struct {
+		path    string
+		want    string
+		wantErr bool
+	}{
+		{"tasks.yaml", TaskFileFormatYAML, false},
+		{"tasks.YML", TaskFileFormatYAML, false},
+		{"tasks.json", TaskFileFormatJSON, false},
+		{"tasks.md", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got, err := TaskFileFormatFromPath(tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("TaskFileFormatFromPath(%q) error = %v, wantErr %v", tt.path, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("TaskFileFormatFromPath(%q) = %q; want %q", tt.path, got, tt.want)
+			}
+		})
+	}
+}
@@ -5,12 +5,11 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"regexp"
-	"slices"
 	"strings"
 	"time"
 
 	"github.com/tuanpep/oplusflow/internal/manager"
+	"github.com/tuanpep/oplusflow/internal/plan"
 )
 
 // Task represents a single atomic task from a plan
@@ -24,12 +23,17 @@ type Task struct {
 	Status       string   `json:"status"` // pending, in_progress, done, failed, skipped
 	Order        int      `json:"order"`
 	Actions      []string `json:"actions,omitempty"`
+	Verification []string `json:"verification,omitempty"`
+	Agent        string   `json:"agent,omitempty"`
+	// Implements lists the spec requirement IDs (FR1, SC2, ...) the task covers
+	Implements []string `json:"implements,omitempty"`
 }
 
 // TaskQueue represents a queue of tasks from a plan
 type TaskQueue struct {
 	PlanRef        string    `json:"plan_ref"`
 	PlanPath       string    `json:"plan_path"`
+	PlanRevision   int       `json:"plan_revision,omitempty"` // plan revision the tasks were decomposed from
 	CreatedAt      time.Time `json:"created_at"`
 	UpdatedAt      time.Time `json:"updated_at"`
 	Tasks          []Task    `json:"tasks"`
@@ -55,11 +59,14 @@ func DecomposePlan(planPath string) (*TaskQueue, error) {
 	}
 
 	// Parse the plan to extract implementation steps
-	tasks := extractTasksFromPlan(content)
+	tasks, err := extractTasksFromPlan(content)
+	if err != nil {
+		return nil, fmt.Errorf("invalid plan %s:\n%w", planPath, err)
+	}
 
 	queue := &TaskQueue{
 		PlanRef:    filepath.Base(planPath),
-		PlanPath:   planPath,
+		PlanPath:   rootPath(planPath),
 		CreatedAt:  time.Now(),
 		UpdatedAt:  time.Now(),
 		Tasks:      tasks,
@@ -70,93 +77,65 @@ func DecomposePlan(planPath string) (*TaskQueue, error) {
 	return queue, nil
 }
 
-// extractTasksFromPlan parses markdown plan and extracts implementation steps
-func extractTasksFromPlan(content string) []Task {
-	var tasks []Task
-
-	// Regex patterns for parsing
-	stepPattern := regexp.MustCompile(`(?m)^###\s+Step\s+(\d+):\s*(.+)$`)
-	filePattern := regexp.MustCompile(`(?m)\*\*File\*\*:\s*\x60([^\x60]+)\x60`)
-	actionPattern := regexp.MustCompile(`(?m)\*\*Action\*\*:\s*(\w+)`)
-
-	lines := strings.Split(content, "\n")
-
-	var currentTask *Task
-	var currentDescription strings.Builder
-	inStep := false
-	stepNumber := 0
-
-	for i, line := range lines {
-		// Check for new step
-		if matches := stepPattern.FindStringSubmatch(line); matches != nil {
-			// Save previous task if exists
-			if currentTask != nil {
-				currentTask.Description = strings.TrimSpace(currentDescription.String())
-				tasks = append(tasks, *currentTask)
-			}
+// rootPath makes a project-root-relative path absolute, so a task queue's
+// PlanPath does not depend on the directory it was created from
+func rootPath(path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	root, err := manager.FindProjectRoot()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(root, path)
+}
 
-			stepNumber++
-			currentTask = &Task{
-				ID:           fmt.Sprintf("task-%d", stepNumber),
-				Title:        strings.TrimSpace(matches[2]),
-				StepNumber:   stepNumber,
-				Status:       TaskStatusPending,
-				Order:        stepNumber,
-				Files:        []string{},
-				Dependencies: []string{},
-				Actions:      []string{},
-			}
-			currentDescription.Reset()
-			inStep = true
+// extractTasksFromPlan parses a markdown plan and turns its implementation steps into tasks
+func extractTasksFromPlan(content string) ([]Task, error) {
+	p, err := plan.Parse(content)
+	if err != nil {
+		return nil, err
+	}
 
-			// Check for dependencies based on step order
-			if stepNumber > 1 {
-				currentTask.Dependencies = append(currentTask.Dependencies, fmt.Sprintf("task-%d", stepNumber-1))
-			}
-			continue
+	// Depends on lines use the step numbers as written; tasks are numbered by position
+	positions := make(map[int]int)
+	for i, step := range p.Steps {
+		if _, seen := positions[step.Number]; !seen {
+			positions[step.Number] = i + 1
 		}
+	}
 
-		// Check for file references
-		if inStep && currentTask != nil {
-			if matches := filePattern.FindStringSubmatch(line); matches != nil {
-				filePath := matches[1]
-				if !slices.Contains(currentTask.Files, filePath) {
-					currentTask.Files = append(currentTask.Files, filePath)
-				}
-			}
-
-			// Check for action type
-			if matches := actionPattern.FindStringSubmatch(line); matches != nil {
-				action := matches[1]
-				if !slices.Contains(currentTask.Actions, action) {
-					currentTask.Actions = append(currentTask.Actions, action)
-				}
-			}
+	tasks := make([]Task, 0, len(p.Steps))
+	for i, step := range p.Steps {
+		stepNumber := i + 1
+		task := Task{
+			ID:           fmt.Sprintf("task-%d", stepNumber),
+			Title:        step.Title,
+			Description:  step.Description,
+			StepNumber:   stepNumber,
+			Status:       TaskStatusPending,
+			Order:        stepNumber,
+			Files:        step.Files,
+			Dependencies: []string{},
+			Actions:      step.Actions,
+			Verification: step.Verification,
+			Implements:   step.Implements,
+		}
 
-			// Check for horizontal rule or next section (end of step)
-			if strings.HasPrefix(line, "---") || (strings.HasPrefix(line, "## ") && i > 0) {
-				if currentTask != nil {
-					currentTask.Description = strings.TrimSpace(currentDescription.String())
-					tasks = append(tasks, *currentTask)
-					currentTask = nil
-					inStep = false
-				}
-				continue
+		// Each step depends on the one before it, unless it declares its dependencies
+		for _, dep := range step.DependsOn {
+			if pos, ok := positions[dep]; ok && pos != stepNumber {
+				task.Dependencies = append(task.Dependencies, fmt.Sprintf("task-%d", pos))
 			}
-
-			// Accumulate description
-			currentDescription.WriteString(line)
-			currentDescription.WriteString("\n")
 		}
-	}
+		if len(step.DependsOn) == 0 && stepNumber > 1 {
+			task.Dependencies = append(task.Dependencies, fmt.Sprintf("task-%d", stepNumber-1))
+		}
 
-	// Don't forget the last task
-	if currentTask != nil {
-		currentTask.Description = strings.TrimSpace(currentDescription.String())
-		tasks = append(tasks, *currentTask)
+		tasks = append(tasks, task)
 	}
 
-	return tasks
+	return tasks, nil
 }
 
 // SaveTaskQueue saves the task queue to a file
@@ -186,6 +165,9 @@ func (tq *TaskQueue) Save() error {
 		return fmt.Errorf("failed to write task queue: %w", err)
 	}
 
+	// Plan metadata is informational; a missing or moved plan must not block task updates
+	_ = tq.syncPlanStatus()
+
 	return nil
 }
 
@@ -260,6 +242,18 @@ func (tq *TaskQueue) StartTask(taskID string) error {
 	return fmt.Errorf("task not found: %s", taskID)
 }
 
+// AssignTask records the agent working on a task
+func (tq *TaskQueue) AssignTask(taskID, agent string) error {
+	for i := range tq.Tasks {
+		if tq.Tasks[i].ID == taskID {
+			tq.Tasks[i].Agent = agent
+			tq.UpdatedAt = time.Now()
+			return nil
+		}
+	}
+	return fmt.Errorf("task not found: %s", taskID)
+}
+
 // GetProgress returns progress info
 func (tq *TaskQueue) GetProgress() string {
 	pending := 0
@@ -290,6 +284,9 @@ func (tq *TaskQueue) FormatTaskList() string {
 
 	sb.WriteString(fmt.Sprintf("# Task Queue: %s\n\n", tq.PlanRef))
 	sb.WriteString(fmt.Sprintf("**Created**: %s\n", tq.CreatedAt.Format("2006-01-02 15:04")))
+	if tq.PlanRevision > 0 {
+		sb.WriteString(fmt.Sprintf("**Plan Revision**: %d\n", tq.PlanRevision))
+	}
 	sb.WriteString(fmt.Sprintf("**Progress**: %s\n\n", tq.GetProgress()))
 
 	for _, task := range tq.Tasks {
@@ -308,6 +305,10 @@ func (tq *TaskQueue) FormatTaskList() string {
 			sb.WriteString(fmt.Sprintf("**Depends on**: %s\n\n", strings.Join(task.Dependencies, ", ")))
 		}
 
+		if len(task.Implements) > 0 {
+			sb.WriteString(fmt.Sprintf("**Implements**: %s\n\n", strings.Join(task.Implements, ", ")))
+		}
+
 		sb.WriteString("---\n\n")
 	}
 
@@ -366,8 +367,14 @@ func GenerateTaskPrompt(task *Task, planContent string) string {
 
 	sb.WriteString("## After Completion\n\n")
 	sb.WriteString("Run the following to verify your work:\n")
-	sb.WriteString("1.  `go build ./...` (or equivalent)\n")
-	sb.WriteString("2.  `go test ./...` (if applicable)\n")
+	if len(task.Verification) > 0 {
+		for i, v := range task.Verification {
+			sb.WriteString(fmt.Sprintf("%d.  %s\n", i+1, v))
+		}
+	} else {
+		sb.WriteString("1.  `go build ./...` (or equivalent)\n")
+		sb.WriteString("2.  `go test ./...` (if applicable)\n")
+	}
 
 	return sb.String()
 }
@@ -379,6 +386,11 @@ func QuickDecomposeFromFile(planPath string) (*TaskQueue, error) {
 		return nil, err
 	}
 
+	// Revision history is best effort; it must not block decomposition
+	if rev, err := SnapshotPlan(tq.PlanPath, RevisionReasonDecompose); err == nil {
+		tq.PlanRevision = rev.Number
+	}
+
 	if err := tq.Save(); err != nil {
 		return nil, fmt.Errorf("failed to save task queue: %w", err)
 	}
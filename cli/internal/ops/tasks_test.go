@@ -33,7 +33,10 @@ Add configuration file.
 Run tests.
 `
 
-	tasks := extractTasksFromPlan(planContent)
+	tasks, err := extractTasksFromPlan(planContent)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
 
 	if len(tasks) != 2 {
 		t.Errorf("Expected 2 tasks, got %d", len(tasks))
@@ -65,7 +68,10 @@ Run tests.
 }
 
 func TestExtractTasksFromPlan_Empty(t *testing.T) {
-	tasks := extractTasksFromPlan("")
+	tasks, err := extractTasksFromPlan("")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
 	if len(tasks) != 0 {
 		t.Errorf("Expected 0 tasks for empty content, got %d", len(tasks))
 	}
@@ -80,12 +86,39 @@ Some goal without steps.
 ## Notes
 Just notes.
 `
-	tasks := extractTasksFromPlan(planContent)
+	tasks, err := extractTasksFromPlan(planContent)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
 	if len(tasks) != 0 {
 		t.Errorf("Expected 0 tasks, got %d", len(tasks))
 	}
 }
 
+func TestExtractTasksFromPlan_InvalidPlan(t *testing.T) {
+	planContent := "## Implementation Steps\n\n### Step one: Bad number\n"
+
+	_, err := extractTasksFromPlan(planContent)
+	if err == nil {
+		t.Fatal("Expected error for invalid step heading")
+	}
+	if !strings.Contains(err.Error(), "line 3") {
+		t.Errorf("Expected line number in error, got: %v", err)
+	}
+}
+
+func TestExtractTasksFromPlan_Verification(t *testing.T) {
+	planContent := "## Implementation Steps\n\n### Step 1: Build\n**File**: `a.go`\n\n**Verification**:\n- [ ] Automated: `go test ./...`\n"
+
+	tasks, err := extractTasksFromPlan(planContent)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(tasks) != 1 || len(tasks[0].Verification) != 1 {
+		t.Fatalf("Expected verification on task, got %+v", tasks)
+	}
+}
+
 func TestTaskQueue_GetNextTask(t *testing.T) {
 	tq := &TaskQueue{
 		Tasks: []Task{
@@ -0,0 +1,456 @@
+package ops
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Test reporters a test command's results can be parsed from
+const (
+	ReporterGo     = "go"     // go test -json
+	ReporterJest   = "jest"   // jest --json
+	ReporterVitest = "vitest" // vitest --reporter=json (jest-compatible)
+	ReporterPytest = "pytest" // pytest --junitxml
+	ReporterJUnit  = "junit"  // any JUnit XML report
+)
+
+// TestReporters lists the supported test reporters
+var TestReporters = []string{ReporterGo, ReporterJest, ReporterVitest, ReporterPytest, ReporterJUnit}
+
+// Test case statuses
+const (
+	TestPass = "pass"
+	TestFail = "fail"
+	TestSkip = "skip"
+)
+
+// TestCase is the result of a single test
+type TestCase struct {
+	Name       string        `json:"name"`
+	Suite      string        `json:"suite,omitempty"` // package, file or class
+	Subproject string        `json:"subproject,omitempty"`
+	Status     string        `json:"status"`
+	Duration   time.Duration `json:"duration"`
+	File       string        `json:"file,omitempty"` // relative to the project root
+	Line       int           `json:"line,omitempty"`
+	Output     string        `json:"output,omitempty"`
+}
+
+// Location returns file:line, or just the file when the line is unknown
+func (tc TestCase) Location() string {
+	if tc.File == "" || tc.Line == 0 {
+		return tc.File
+	}
+	return fmt.Sprintf("%s:%d", tc.File, tc.Line)
+}
+
+// DisplayName names the test together with its suite
+func (tc TestCase) DisplayName() string {
+	switch {
+	case tc.Name == "":
+		return tc.Suite
+	case tc.Suite == "":
+		return tc.Name
+	}
+	return fmt.Sprintf("%s (%s)", tc.Name, tc.Suite)
+}
+
+// testSnippetLines caps the output quoted for a failing test
+const testSnippetLines = 40
+
+// inferTestReporter picks the reporter for commands that invoke a known test
+// runner directly
+func inferTestReporter(run string) string {
+	fields := strings.Fields(run)
+	if len(fields) > 0 && fields[0] == "npx" {
+		fields = fields[1:]
+	}
+	switch {
+	case len(fields) >= 2 && fields[0] == "go" && fields[1] == "test":
+		return ReporterGo
+	case len(fields) >= 1 && fields[0] == "jest":
+		return ReporterJest
+	case len(fields) >= 1 && fields[0] == "vitest":
+		return ReporterVitest
+	case len(fields) >= 1 && fields[0] == "pytest",
+		len(fields) >= 3 && strings.HasPrefix(fields[0], "python") && fields[1] == "-m" && fields[2] == "pytest":
+		return ReporterPytest
+	}
+	return ""
+}
+
+// withReporterFlags adds the flags making run produce a machine-readable
+// report, written to reportFile unless the reporter uses stdout
+func withReporterFlags(run, reporter, reportFile string) string {
+	quoted := shellQuote(reportFile)
+	switch reporter {
+	case ReporterGo:
+		if strings.Contains(run, "-json") {
+			return run
+		}
+		return strings.Replace(run, "go test", "go test -json", 1)
+	case ReporterJest:
+		return forwardArgs(run) + " --json --outputFile=" + quoted
+	case ReporterVitest:
+		return forwardArgs(run) + " --reporter=json --outputFile=" + quoted
+	case ReporterPytest:
+		return forwardArgs(run) + " --junitxml=" + quoted
+	}
+	return run
+}
+
+// forwardArgs prepares a command for extra arguments meant for the test
+// runner. npm keeps arguments before "--" for itself; yarn and pnpm forward
+// them to the script as they are.
+func forwardArgs(run string) string {
+	fields := strings.Fields(run)
+	if len(fields) > 0 && fields[0] == "npm" && !slices.Contains(fields, "--") {
+		return run + " --"
+	}
+	return run
+}
+
+// parseTestReport parses a report in the given reporter's format. File
+// locations are made relative to the project root; workdir is the command's
+// directory relative to root.
+func parseTestReport(reporter string, data []byte, root, workdir string) ([]TestCase, error) {
+	switch reporter {
+	case ReporterGo:
+		return parseGoTestJSON(data, modulePath(filepath.Join(root, workdir)), workdir), nil
+	case ReporterJest, ReporterVitest:
+		return parseJestJSON(data, root)
+	case ReporterPytest, ReporterJUnit:
+		return parseJUnitXML(data, workdir)
+	}
+	return nil, fmt.Errorf("unknown test reporter %q", reporter)
+}
+
+// goTestEvent is one line of go test -json output
+type goTestEvent struct {
+	Action     string
+	Package    string
+	Test       string
+	Elapsed    float64
+	Output     string
+	ImportPath string
+}
+
+var goFileLineRe = regexp.MustCompile(`(?m)^\s+([\w./-]+\.go):(\d+):`)
+
+// parseGoTestJSON parses go test -json output. Packages that fail without a
+// failing test (build errors, TestMain) become a case named after the package.
+// Non-JSON lines are ignored.
+func parseGoTestJSON(data []byte, module, workdir string) []TestCase {
+	type key struct{ pkg, test string }
+	outputs := make(map[key]*strings.Builder)
+	var cases []TestCase
+	var failedPkgs []TestCase
+	failingTests := make(map[string]bool)
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 1024*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var ev goTestEvent
+		if err := json.Unmarshal(scanner.Bytes(), &ev); err != nil {
+			continue
+		}
+		pkg := ev.Package
+		if pkg == "" {
+			pkg = ev.ImportPath
+		}
+		k := key{pkg, ev.Test}
+
+		switch ev.Action {
+		case "output", "build-output":
+			if strings.HasPrefix(ev.Output, "=== ") {
+				continue
+			}
+			if outputs[k] == nil {
+				outputs[k] = &strings.Builder{}
+			}
+			outputs[k].WriteString(ev.Output)
+		case "pass", "fail", "skip":
+			var out string
+			if outputs[k] != nil {
+				out = outputs[k].String()
+			}
+			tc := TestCase{
+				Name:     ev.Test,
+				Suite:    pkg,
+				Status:   map[string]string{"pass": TestPass, "fail": TestFail, "skip": TestSkip}[ev.Action],
+				Duration: time.Duration(ev.Elapsed * float64(time.Second)),
+				Output:   out,
+			}
+			if ev.Test == "" {
+				if ev.Action == "fail" {
+					failedPkgs = append(failedPkgs, tc)
+				}
+				continue
+			}
+			if m := goFileLineRe.FindStringSubmatch(out); m != nil {
+				tc.File = goSourcePath(module, workdir, pkg, m[1])
+				tc.Line, _ = strconv.Atoi(m[2])
+			}
+			if tc.Status == TestFail {
+				failingTests[pkg] = true
+			}
+			cases = append(cases, tc)
+		}
+	}
+
+	// A failing subtest also fails its parents; keep only the innermost failure
+	var kept []TestCase
+	for _, tc := range cases {
+		if tc.Status == TestFail && hasFailingSubtest(tc, cases) {
+			continue
+		}
+		kept = append(kept, tc)
+	}
+
+	for _, pkg := range failedPkgs {
+		if !failingTests[pkg.Suite] {
+			pkg.Name = ""
+			kept = append(kept, pkg)
+		}
+	}
+	return kept
+}
+
+func hasFailingSubtest(parent TestCase, cases []TestCase) bool {
+	for _, tc := range cases {
+		if tc.Status == TestFail && tc.Suite == parent.Suite && strings.HasPrefix(tc.Name, parent.Name+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// goSourcePath maps a file reported by a test in pkg to a path relative to the
+// project root, using the module path of the command's workdir
+func goSourcePath(module, workdir, pkg, file string) string {
+	if module == "" || (pkg != module && !strings.HasPrefix(pkg, module+"/")) {
+		return file
+	}
+	dir := strings.TrimPrefix(strings.TrimPrefix(pkg, module), "/")
+	return filepath.ToSlash(filepath.Join(workdir, dir, filepath.Base(file)))
+}
+
+// modulePath reads the module path from dir's go.mod
+func modulePath(dir string) string {
+	data, err := os.ReadFile(filepath.Join(dir, "go.mod"))
+	if err != nil {
+		return ""
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if fields := strings.Fields(line); len(fields) == 2 && fields[0] == "module" {
+			return strings.Trim(fields[1], `"`)
+		}
+	}
+	return ""
+}
+
+// jestReport is the jest --json format, which vitest's json reporter shares
+type jestReport struct {
+	TestResults []struct {
+		Name             string `json:"name"`
+		Status           string `json:"status"`
+		Message          string `json:"message"`
+		AssertionResults []struct {
+			FullName        string   `json:"fullName"`
+			Title           string   `json:"title"`
+			Status          string   `json:"status"`
+			Duration        *float64 `json:"duration"`
+			FailureMessages []string `json:"failureMessages"`
+			Location        *struct {
+				Line int `json:"line"`
+			} `json:"location"`
+		} `json:"assertionResults"`
+	} `json:"testResults"`
+}
+
+// parseJestJSON parses a jest or vitest JSON report, skipping any text
+// printed before the JSON object
+func parseJestJSON(data []byte, root string) ([]TestCase, error) {
+	start := bytes.Index(data, []byte("{"))
+	if start < 0 {
+		return nil, fmt.Errorf("no JSON test report found")
+	}
+	var report jestReport
+	if err := json.NewDecoder(bytes.NewReader(data[start:])).Decode(&report); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON test report: %w", err)
+	}
+
+	var cases []TestCase
+	for _, file := range report.TestResults {
+		path := relToRoot(root, file.Name)
+		if len(file.AssertionResults) == 0 && file.Status == "failed" {
+			// The file failed to load or run
+			cases = append(cases, TestCase{Suite: path, Status: TestFail, File: path, Output: file.Message})
+			continue
+		}
+		for _, a := range file.AssertionResults {
+			name := a.FullName
+			if name == "" {
+				name = a.Title
+			}
+			tc := TestCase{Name: name, Suite: path, File: path, Output: strings.Join(a.FailureMessages, "\n")}
+			switch a.Status {
+			case "passed":
+				tc.Status = TestPass
+			case "failed":
+				tc.Status = TestFail
+			default: // pending, skipped, todo, disabled
+				tc.Status = TestSkip
+			}
+			if a.Duration != nil {
+				tc.Duration = time.Duration(*a.Duration * float64(time.Millisecond))
+			}
+			if a.Location != nil {
+				tc.Line = a.Location.Line
+			}
+			cases = append(cases, tc)
+		}
+	}
+	return cases, nil
+}
+
+// junitSuite is a JUnit XML <testsuite> (or <testsuites>) element
+type junitSuite struct {
+	XMLName xml.Name
+	Name    string       `xml:"name,attr"`
+	File    string       `xml:"file,attr"`
+	Suites  []junitSuite `xml:"testsuite"`
+	Cases   []struct {
+		Name      string  `xml:"name,attr"`
+		ClassName string  `xml:"classname,attr"`
+		Time      float64 `xml:"time,attr"`
+		File      string  `xml:"file,attr"`
+		Line      int     `xml:"line,attr"`
+		Failure   *struct {
+			Message string `xml:"message,attr"`
+			Text    string `xml:",chardata"`
+		} `xml:"failure"`
+		Error *struct {
+			Message string `xml:"message,attr"`
+			Text    string `xml:",chardata"`
+		} `xml:"error"`
+		Skipped   *struct{} `xml:"skipped"`
+		SystemOut string    `xml:"system-out"`
+	} `xml:"testcase"`
+}
+
+// parseJUnitXML parses a JUnit XML report such as pytest --junitxml writes
+func parseJUnitXML(data []byte, workdir string) ([]TestCase, error) {
+	var root junitSuite
+	if err := xml.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("failed to parse JUnit report: %w", err)
+	}
+
+	var cases []TestCase
+	var walk func(s junitSuite)
+	walk = func(s junitSuite) {
+		for _, c := range s.Cases {
+			suite := c.ClassName
+			if suite == "" {
+				suite = s.Name
+			}
+			tc := TestCase{
+				Name:     c.Name,
+				Suite:    suite,
+				Status:   TestPass,
+				Duration: time.Duration(c.Time * float64(time.Second)),
+				Line:     c.Line,
+			}
+			if file := firstNonEmpty(c.File, s.File); file != "" {
+				tc.File = filepath.ToSlash(filepath.Join(workdir, file))
+			}
+			switch {
+			case c.Failure != nil:
+				tc.Status = TestFail
+				tc.Output = strings.TrimSpace(c.Failure.Message + "\n" + c.Failure.Text)
+			case c.Error != nil:
+				tc.Status = TestFail
+				tc.Output = strings.TrimSpace(c.Error.Message + "\n" + c.Error.Text)
+			case c.Skipped != nil:
+				tc.Status = TestSkip
+			}
+			cases = append(cases, tc)
+		}
+		for _, child := range s.Suites {
+			walk(child)
+		}
+	}
+	walk(root)
+	return cases, nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// goTestPlainOutput turns go test -json output back into the text go test
+// would have printed
+func goTestPlainOutput(output string) string {
+	var sb strings.Builder
+	for _, line := range strings.SplitAfter(output, "\n") {
+		var ev goTestEvent
+		if json.Unmarshal([]byte(line), &ev) != nil {
+			sb.WriteString(line)
+			continue
+		}
+		sb.WriteString(ev.Output)
+	}
+	return sb.String()
+}
+
+// testOutputSnippet keeps the last lines of a failing test's output
+func testOutputSnippet(output string) string {
+	lines := strings.Split(strings.TrimRight(output, "\n"), "\n")
+	if len(lines) > testSnippetLines {
+		lines = append([]string{"..."}, lines[len(lines)-testSnippetLines:]...)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// countTestCases tallies cases by status and sums their durations
+func countTestCases(cases []TestCase) (passed, failed, skipped int, total time.Duration) {
+	for _, tc := range cases {
+		switch tc.Status {
+		case TestPass:
+			passed++
+		case TestFail:
+			failed++
+		case TestSkip:
+			skipped++
+		}
+		total += tc.Duration
+	}
+	return
+}
+
+// sortTestCases orders failures first, then skips, then by name
+func sortTestCases(cases []TestCase) {
+	rank := map[string]int{TestFail: 0, TestSkip: 1, TestPass: 2}
+	sort.SliceStable(cases, func(i, j int) bool {
+		if rank[cases[i].Status] != rank[cases[j].Status] {
+			return rank[cases[i].Status] < rank[cases[j].Status]
+		}
+		return cases[i].DisplayName() < cases[j].DisplayName()
+	})
+}
@@ -0,0 +1,203 @@
+package ops
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestInferTestReporter(t *testing.T) {
+	tests := []struct {
+		run  string
+		want string
+	}{
+		{"go test ./...", ReporterGo},
+		{"go build ./...", ""},
+		{"npx jest --ci", ReporterJest},
+		{"vitest run", ReporterVitest},
+		{"pytest -q", ReporterPytest},
+		{"python3 -m pytest tests", ReporterPytest},
+		{"npm test", ""},
+		{"make test", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.run, func(t *testing.T) {
+			if got := inferTestReporter(tt.run); got != tt.want {
+				t.Errorf("inferTestReporter(%q) = %q; want %q", tt.run, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithReporterFlags(t *testing.T) {
+	tests := []struct {
+		run, reporter, want string
+	}{
+		{"go test ./...", ReporterGo, "go test -json ./..."},
+		{"go test -json ./...", ReporterGo, "go test -json ./..."},
+		{"npx jest", ReporterJest, "npx jest --json --outputFile=" + shellQuote("/tmp/r.json")},
+		{"vitest run", ReporterVitest, "vitest run --reporter=json --outputFile=" + shellQuote("/tmp/r.json")},
+		{"pytest", ReporterPytest, "pytest --junitxml=" + shellQuote("/tmp/r.json")},
+		{"npm test", ReporterJest, "npm test -- --json --outputFile=" + shellQuote("/tmp/r.json")},
+		{"npm run test:unit -- --ci", ReporterVitest, "npm run test:unit -- --ci --reporter=json --outputFile=" + shellQuote("/tmp/r.json")},
+		{"yarn test", ReporterJest, "yarn test --json --outputFile=" + shellQuote("/tmp/r.json")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.run, func(t *testing.T) {
+			if got := withReporterFlags(tt.run, tt.reporter, "/tmp/r.json"); got != tt.want {
+				t.Errorf("withReporterFlags() = %q; want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseGoTestJSON(t *testing.T) {
+	events := strings.Join([]string{
+		`{"Action":"run","Package":"example.com/m/calc","Test":"TestAdd"}`,
+		`{"Action":"output","Package":"example.com/m/calc","Test":"TestAdd","Output":"=== RUN   TestAdd\n"}`,
+		`{"Action":"pass","Package":"example.com/m/calc","Test":"TestAdd","Elapsed":0.25}`,
+		`{"Action":"output","Package":"example.com/m/calc","Test":"TestDiv/zero","Output":"    calc_test.go:21: got 1; want 0\n"}`,
+		`{"Action":"fail","Package":"example.com/m/calc","Test":"TestDiv/zero","Elapsed":0.01}`,
+		`{"Action":"fail","Package":"example.com/m/calc","Test":"TestDiv","Elapsed":0.02}`,
+		`{"Action":"skip","Package":"example.com/m/calc","Test":"TestSlow","Elapsed":0}`,
+		`{"Action":"fail","Package":"example.com/m/calc","Elapsed":0.3}`,
+		`# example.com/m/broken`,
+		`{"Action":"build-output","ImportPath":"example.com/m/broken","Output":"broken/x.go:3:2: undefined: y\n"}`,
+		`{"Action":"fail","Package":"example.com/m/broken","Elapsed":0}`,
+	}, "\n")
+
+	cases := parseGoTestJSON([]byte(events), "example.com/m", "cli")
+
+	var got []string
+	for _, tc := range cases {
+		got = append(got, tc.Status+" "+tc.DisplayName()+" "+tc.Location())
+	}
+	want := []string{
+		"pass TestAdd (example.com/m/calc) ",
+		"fail TestDiv/zero (example.com/m/calc) cli/calc/calc_test.go:21",
+		"skip TestSlow (example.com/m/calc) ",
+		"fail example.com/m/broken ",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("parseGoTestJSON() =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if cases[0].Duration != 250*time.Millisecond {
+		t.Errorf("Expected duration 250ms, got %s", cases[0].Duration)
+	}
+	if !strings.Contains(cases[3].Output, "undefined: y") {
+		t.Errorf("Expected build output on the failed package, got %q", cases[3].Output)
+	}
+}
+
+func TestParseJestJSON(t *testing.T) {
+	report := "\n> web@1.0.0 test\n> jest\n\n" + `{"numFailedTests":1,"testResults":[
+		{"name":"/repo/web/src/sum.test.ts","status":"failed","assertionResults":[
+			{"fullName":"sum adds","title":"adds","status":"passed","duration":4},
+			{"fullName":"sum rounds","title":"rounds","status":"failed","duration":2,"failureMessages":["Expected 1, received 2"],"location":{"line":9,"column":3}},
+			{"fullName":"sum later","title":"later","status":"todo","duration":null}
+		]},
+		{"name":"/repo/web/src/broken.test.ts","status":"failed","message":"Cannot find module './x'","assertionResults":[]}
+	]}`
+
+	cases, err := parseJestJSON([]byte(report), "/repo")
+	if err != nil {
+		t.Fatalf("parseJestJSON failed: %v", err)
+	}
+
+	var got []string
+	for _, tc := range cases {
+		got = append(got, tc.Status+" "+tc.Name+" "+tc.Location())
+	}
+	want := "pass sum adds web/src/sum.test.ts|fail sum rounds web/src/sum.test.ts:9|skip sum later web/src/sum.test.ts|fail  web/src/broken.test.ts"
+	if strings.Join(got, "|") != want {
+		t.Errorf("parseJestJSON() = %q; want %q", strings.Join(got, "|"), want)
+	}
+	if cases[0].Duration != 4*time.Millisecond || cases[1].Output != "Expected 1, received 2" {
+		t.Errorf("Unexpected case details: %+v", cases[:2])
+	}
+
+	if _, err := parseJestJSON([]byte("npm ERR! missing script: test"), "/repo"); err == nil {
+		t.Error("Expected an error without a JSON report")
+	}
+}
+
+func TestParseJUnitXML(t *testing.T) {
+	report := `<?xml version="1.0" encoding="utf-8"?>
+<testsuites>
+  <testsuite name="pytest" tests="3">
+    <testcase classname="tests.test_api" name="test_get" time="0.120" file="tests/test_api.py" line="4"/>
+    <testcase classname="tests.test_api" name="test_post" time="0.010" file="tests/test_api.py" line="12">
+      <failure message="AssertionError: 500 != 201">def test_post():
+&gt;       assert resp.status == 201</failure>
+    </testcase>
+    <testcase classname="tests.test_api" name="test_put" time="0"><skipped message="todo"/></testcase>
+  </testsuite>
+</testsuites>`
+
+	cases, err := parseJUnitXML([]byte(report), "api")
+	if err != nil {
+		t.Fatalf("parseJUnitXML failed: %v", err)
+	}
+	if len(cases) != 3 {
+		t.Fatalf("Expected 3 cases, got %+v", cases)
+	}
+
+	failed := cases[1]
+	if failed.Status != TestFail || failed.Location() != "api/tests/test_api.py:12" || !strings.Contains(failed.Output, "assert resp.status == 201") {
+		t.Errorf("Unexpected failing case: %+v", failed)
+	}
+	if cases[0].Status != TestPass || cases[0].Duration != 120*time.Millisecond || cases[2].Status != TestSkip {
+		t.Errorf("Unexpected cases: %+v", cases)
+	}
+}
+
+func TestRunTestCommand_Go(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go not available")
+	}
+
+	root := t.TempDir()
+	writeTestFiles(t, root, map[string]string{
+		"svc/go.mod":            "module example.com/svc\n\ngo 1.21\n",
+		"svc/calc/calc.go":      "package calc\n\nfunc Add(a, b int) int { return a - b }\n",
+		"svc/calc/calc_test.go": "package calc\n\nimport \"testing\"\n\nfunc TestAdd(t *testing.T) {\n\tif got := Add(1, 2); got != 3 {\n\t\tt.Errorf(\"Add(1, 2) = %d; want 3\", got)\n\t}\n}\n\nfunc TestZero(t *testing.T) {}\n",
+	})
+
+	tr, err := runTestCommand(context.Background(), root, ProjectCommand{Kind: CommandTest, Run: "go test ./...", Workdir: "svc", Timeout: 2 * time.Minute, Reporter: ReporterGo}, true)
+	if err == nil {
+		t.Fatal("Expected failing tests to return an error")
+	}
+	output, cases := tr.Output, tr.Cases
+	if !strings.Contains(output, "--- FAIL: TestAdd") || strings.Contains(output, `"Action"`) {
+		t.Errorf("Expected plain go test output, got:\n%s", output)
+	}
+
+	passed, failed, _, _ := countTestCases(cases)
+	if passed != 1 || failed != 1 {
+		t.Fatalf("Expected 1 pass and 1 failure, got %+v", cases)
+	}
+	for _, tc := range cases {
+		if tc.Status == TestFail && (tc.Location() != "svc/calc/calc_test.go:7" || !strings.Contains(tc.Output, "want 3")) {
+			t.Errorf("Unexpected failing case: %+v", tc)
+		}
+	}
+
+	// go test writes a cover profile when coverage is requested
+	if tr.Coverage == nil || tr.Coverage.Total != 1 || tr.Coverage.Covered != 1 || !tr.Coverage.Lines["svc/calc/calc.go"][3] {
+		t.Errorf("Expected coverage of svc/calc/calc.go, got %+v", tr.Coverage)
+	}
+
+	// A stale report file is not mistaken for this run's results
+	stale := filepath.Join(root, "svc", "report.xml")
+	os.WriteFile(stale, []byte(`<testsuite><testcase name="old"><failure/></testcase></testsuite>`), 0644)
+	old := time.Now().Add(-time.Hour)
+	os.Chtimes(stale, old, old)
+	tr, _ = runTestCommand(context.Background(), root, ProjectCommand{Kind: CommandTest, Run: "true", Workdir: "svc", Timeout: time.Minute, Reporter: ReporterJUnit, Report: "report.xml"}, false)
+	if tr.Cases != nil {
+		t.Errorf("Expected the stale report to be ignored, got %+v", tr.Cases)
+	}
+}
@@ -0,0 +1,371 @@
+package ops
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/tuanpep/oplusflow/internal/manager"
+	"github.com/tuanpep/oplusflow/internal/plan"
+)
+
+// Requirement coverage statuses in a trace matrix
+const (
+	TraceUncovered   = "uncovered"
+	TraceSkipped     = "skipped"
+	TracePlanned     = "planned"
+	TraceInProgress  = "in progress"
+	TraceImplemented = "implemented"
+	TraceVerified    = "verified"
+)
+
+// traceNotDecomposed is the status of a step whose plan has no task queue yet
+const traceNotDecomposed = "not decomposed"
+
+// TraceTask is a task (or undecomposed plan step) that implements a requirement
+type TraceTask struct {
+	Plan         string   `json:"plan"`
+	TaskID       string   `json:"task_id"`
+	Title        string   `json:"title"`
+	Status       string   `json:"status"`
+	Verification []string `json:"verification,omitempty"`
+}
+
+// TraceRow is one requirement of the matrix with its tasks and evidence
+type TraceRow struct {
+	ID       string      `json:"id"`
+	Text     string      `json:"text"`
+	Status   string      `json:"status"`
+	Tasks    []TraceTask `json:"tasks"`
+	Evidence []string    `json:"evidence"`
+}
+
+// TraceMatrix links a spec's requirements to plan tasks and verification results
+type TraceMatrix struct {
+	SpecPath string     `json:"spec_path"`
+	Title    string     `json:"title"`
+	Plans    []string   `json:"plans"`
+	Rows     []TraceRow `json:"rows"`
+	// UnknownRefs are requirement IDs referenced by tasks but missing from the spec
+	UnknownRefs []string `json:"unknown_refs,omitempty"`
+}
+
+// tracedPlan is a plan linked to the spec with the tasks derived from it
+type tracedPlan struct {
+	filename     string
+	tasks        []Task
+	criteria     []plan.ChecklistItem
+	verification *VerificationSummary
+}
+
+// TraceSpec builds the traceability matrix for a spec: every functional
+// requirement and success criterion, the tasks of linked plans that declare
+// "**Implements**: <ID>", their status and the verification evidence.
+// Plans are linked through their spec_ref frontmatter or a link to the spec.
+func TraceSpec(specRef string) (*TraceMatrix, error) {
+	root, err := manager.FindProjectRoot()
+	if err != nil {
+		return nil, fmt.Errorf("failed to find project root: %w", err)
+	}
+
+	s, specPath, err := LoadSpec(specRef)
+	if err != nil {
+		return nil, err
+	}
+	specName := filepath.Base(specPath)
+
+	summaries, err := ListPlans(true)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &TraceMatrix{SpecPath: specPath, Title: s.Title, Plans: []string{}, Rows: []TraceRow{}}
+
+	var plans []tracedPlan
+	for _, summary := range summaries {
+		tp, ok := loadTracedPlan(root, summary, specName)
+		if !ok {
+			continue
+		}
+		plans = append(plans, tp)
+		m.Plans = append(m.Plans, tp.filename)
+	}
+
+	known := make(map[string]bool)
+	for _, req := range append(s.FilledRequirements(), s.FilledSuccessCriteria()...) {
+		known[req.ID] = true
+		row := TraceRow{ID: req.ID, Text: req.Text, Tasks: []TraceTask{}, Evidence: []string{}}
+		for _, tp := range plans {
+			traceRequirement(&row, tp)
+		}
+		row.Status = traceStatus(row, plans)
+		m.Rows = append(m.Rows, row)
+	}
+
+	for _, tp := range plans {
+		for _, t := range tp.tasks {
+			for _, id := range t.Implements {
+				if !known[id] {
+					m.UnknownRefs = append(m.UnknownRefs, fmt.Sprintf("%s %s: %s", tp.filename, t.ID, id))
+				}
+			}
+		}
+	}
+
+	return m, nil
+}
+
+// loadTracedPlan reads a plan and its tasks if the plan belongs to the spec
+func loadTracedPlan(root string, summary PlanSummary, specName string) (tracedPlan, bool) {
+	if !planLinkedToSpec(summary, specName) {
+		return tracedPlan{}, false
+	}
+	content, err := os.ReadFile(summary.Path)
+	if err != nil {
+		return tracedPlan{}, false
+	}
+
+	p, _ := plan.Parse(string(content))
+	tp := tracedPlan{
+		filename:     summary.Filename,
+		criteria:     p.SuccessCriteria,
+		verification: latestVerification(root, summary.Filename),
+	}
+
+	if tq, err := LoadTaskQueue(summary.Filename); err == nil {
+		tp.tasks = tq.Tasks
+		// Queues decomposed before requirement IDs were tracked take them from the plan
+		for i, t := range tp.tasks {
+			if len(t.Implements) == 0 && t.ID == fmt.Sprintf("task-%d", t.StepNumber) && t.StepNumber <= len(p.Steps) {
+				tp.tasks[i].Implements = p.Steps[t.StepNumber-1].Implements
+			}
+		}
+		return tp, true
+	}
+
+	for i, st := range p.Steps {
+		tp.tasks = append(tp.tasks, Task{
+			ID:           fmt.Sprintf("step-%d", i+1),
+			Title:        st.Title,
+			Status:       traceNotDecomposed,
+			Verification: st.Verification,
+			Implements:   st.Implements,
+		})
+	}
+	return tp, true
+}
+
+// planLinkedToSpec reports whether a plan was created from the spec, through
+// its spec_ref frontmatter or, for plans without one, a mention of the spec
+// file as a whole name or path ("specs/auth.md" but not "oauth.md")
+func planLinkedToSpec(summary PlanSummary, specName string) bool {
+	if summary.Meta != nil && summary.Meta.SpecRef != "" {
+		return summary.Meta.SpecRef == specName
+	}
+	content, err := os.ReadFile(summary.Path)
+	if err != nil {
+		return false
+	}
+	pattern := regexp.MustCompile(`(?:^|[^\w.-])` + regexp.QuoteMeta(specName) + `(?:$|[^\w.-]|\.(?:$|[^\w-]))`)
+	return pattern.Match(content)
+}
+
+// traceRequirement adds the tasks and evidence a plan provides for a requirement
+func traceRequirement(row *TraceRow, tp tracedPlan) {
+	covered := false
+	for _, t := range tp.tasks {
+		if !slices.Contains(t.Implements, row.ID) {
+			continue
+		}
+		covered = true
+		row.Tasks = append(row.Tasks, TraceTask{
+			Plan:         tp.filename,
+			TaskID:       t.ID,
+			Title:        t.Title,
+			Status:       t.Status,
+			Verification: t.Verification,
+		})
+	}
+
+	for _, c := range tp.criteria {
+		if !slices.Contains(plan.RequirementIDs(c.Text), row.ID) {
+			continue
+		}
+		mark := "unchecked"
+		if c.Checked {
+			mark = "checked"
+		}
+		row.Evidence = append(row.Evidence, fmt.Sprintf("%s success criterion %s", tp.filename, mark))
+	}
+
+	if covered && tp.verification != nil {
+		row.Evidence = append(row.Evidence, fmt.Sprintf("%s: %s", filepath.Base(tp.verification.Path), tp.verification.Status))
+	}
+}
+
+// traceStatus derives a requirement's coverage from its tasks and the latest
+// verification of the plans they belong to
+func traceStatus(row TraceRow, plans []tracedPlan) string {
+	if len(row.Tasks) == 0 {
+		return TraceUncovered
+	}
+
+	// Skipped tasks are no evidence the requirement was met
+	finished, started, active := true, false, 0
+	for _, t := range row.Tasks {
+		if t.Status == TaskStatusSkipped {
+			continue
+		}
+		active++
+		switch t.Status {
+		case TaskStatusDone:
+			started = true
+		case TaskStatusInProgress, TaskStatusFailed:
+			started = true
+			finished = false
+		default:
+			finished = false
+		}
+	}
+
+	switch {
+	case active == 0:
+		return TraceSkipped
+	case finished && verifiedPlans(row, plans):
+		return TraceVerified
+	case finished:
+		return TraceImplemented
+	case started:
+		return TraceInProgress
+	}
+	return TracePlanned
+}
+
+// verifiedPlans reports whether every plan covering the requirement passed verification
+func verifiedPlans(row TraceRow, plans []tracedPlan) bool {
+	for _, t := range row.Tasks {
+		for _, tp := range plans {
+			if tp.filename == t.Plan && (tp.verification == nil || tp.verification.Status != "passed") {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Uncovered returns the requirements no task implements, including those
+// whose tasks were all skipped
+func (m *TraceMatrix) Uncovered() []TraceRow {
+	var rows []TraceRow
+	for _, r := range m.Rows {
+		if r.Status == TraceUncovered || r.Status == TraceSkipped {
+			rows = append(rows, r)
+		}
+	}
+	return rows
+}
+
+// FormatMarkdown renders the matrix as a markdown table
+func (m *TraceMatrix) FormatMarkdown() string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("# Traceability: %s\n\n", valueOrDash(m.Title)))
+	sb.WriteString(fmt.Sprintf("**Spec**: %s\n", filepath.Base(m.SpecPath)))
+	plans := "(none)"
+	if len(m.Plans) > 0 {
+		plans = strings.Join(m.Plans, ", ")
+	}
+	sb.WriteString(fmt.Sprintf("**Plans**: %s\n", plans))
+
+	verified := 0
+	for _, r := range m.Rows {
+		if r.Status == TraceVerified {
+			verified++
+		}
+	}
+	sb.WriteString(fmt.Sprintf("**Coverage**: %d/%d requirements covered, %d verified\n\n",
+		len(m.Rows)-len(m.Uncovered()), len(m.Rows), verified))
+
+	if len(m.Rows) == 0 {
+		sb.WriteString("The spec has no filled-in requirements or success criteria.\n")
+		return sb.String()
+	}
+
+	sb.WriteString("| ID | Requirement | Tasks | Status | Evidence |\n")
+	sb.WriteString("|----|-------------|-------|--------|----------|\n")
+	for _, r := range m.Rows {
+		var tasks []string
+		for _, t := range r.Tasks {
+			emoji := getStatusEmoji(t.Status)
+			if t.Status == traceNotDecomposed {
+				emoji = "📝"
+			}
+			tasks = append(tasks, fmt.Sprintf("%s %s %s", emoji, t.Plan, t.TaskID))
+		}
+
+		var evidence []string
+		for _, t := range r.Tasks {
+			for _, v := range t.Verification {
+				evidence = append(evidence, fmt.Sprintf("%s: %s", t.TaskID, v))
+			}
+		}
+		evidence = append(evidence, r.Evidence...)
+
+		sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s |\n",
+			r.ID, tableCell(r.Text), tableCell(strings.Join(tasks, "<br>")),
+			traceStatusLabel(r.Status), tableCell(strings.Join(evidence, "<br>"))))
+	}
+
+	if uncovered := m.Uncovered(); len(uncovered) > 0 {
+		sb.WriteString(fmt.Sprintf("\n## Uncovered Requirements (%d)\n\n", len(uncovered)))
+		for _, r := range uncovered {
+			sb.WriteString(fmt.Sprintf("- **%s**: %s\n", r.ID, r.Text))
+		}
+	}
+
+	if len(m.UnknownRefs) > 0 {
+		sb.WriteString("\n## Unknown Requirement References\n\n")
+		for _, ref := range m.UnknownRefs {
+			sb.WriteString(fmt.Sprintf("- %s\n", ref))
+		}
+	}
+
+	return sb.String()
+}
+
+// FormatJSON returns the matrix as indented JSON
+func (m *TraceMatrix) FormatJSON() (string, error) {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+func traceStatusLabel(status string) string {
+	switch status {
+	case TraceVerified:
+		return "✅ verified"
+	case TraceImplemented:
+		return "🟢 implemented"
+	case TraceInProgress:
+		return "🔄 in progress"
+	case TracePlanned:
+		return "⬜ planned"
+	case TraceSkipped:
+		return "⏭️ skipped"
+	}
+	return "❌ uncovered"
+}
+
+// tableCell escapes text for a markdown table cell
+func tableCell(text string) string {
+	if text == "" {
+		return "—"
+	}
+	return strings.ReplaceAll(strings.ReplaceAll(text, "|", "\\|"), "\n", " ")
+}
@@ -0,0 +1,155 @@
+package ops
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTraceSpec(t *testing.T) {
+	tmpDir := t.TempDir()
+	specsDir := filepath.Join(tmpDir, "opusflow-planning", "specs")
+	if err := os.MkdirAll(specsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	oldWd, _ := os.Getwd()
+	os.Chdir(tmpDir)
+	defer os.Chdir(oldWd)
+
+	content := "# Feature Specification: OAuth Login\n\n**Status**: ✅ Approved\n\n" +
+		"## Goal\n\n> Let users sign in with Google\n\n" +
+		"### Functional Requirements\n\n- [ ] **FR1**: Redirect to the provider\n- [ ] **FR2**: Store the token\n\n" +
+		"## Success Criteria\n\n- [ ] **SC1**: Login works end to end\n"
+	if err := os.WriteFile(filepath.Join(specsDir, "spec-oauth.md"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Before any plan exists everything is uncovered
+	m, err := TraceSpec("spec-oauth.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(m.Rows) != 3 || len(m.Uncovered()) != 3 {
+		t.Fatalf("Expected 3 uncovered rows, got %+v", m.Rows)
+	}
+
+	created, err := CreatePlanFromSpec("spec-oauth.md", "", PlanOptions{}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Undecomposed steps already count as planned coverage
+	m, _ = TraceSpec("spec-oauth.md")
+	if m.Rows[0].Status != TracePlanned || m.Rows[0].Tasks[0].TaskID != "step-1" {
+		t.Errorf("Expected FR1 planned by step-1, got %+v", m.Rows[0])
+	}
+
+	tq, err := QuickDecomposeFromFile(created.FullPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(tq.Tasks[1].Implements, ","); got != "FR2" {
+		t.Errorf("Expected task-2 to implement FR2, got %q", got)
+	}
+	tq.CompleteTask("task-1")
+	if err := tq.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	vr := &VerificationResult{PlanRef: created.Filename, Status: "passed", VerifiedAt: time.Now()}
+	if _, err := vr.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	m, err = TraceSpec("spec-oauth.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(m.Plans) != 1 || m.Plans[0] != created.Filename {
+		t.Errorf("Expected the plan to be linked, got %v", m.Plans)
+	}
+
+	statuses := make(map[string]string)
+	for _, r := range m.Rows {
+		statuses[r.ID] = r.Status
+	}
+	if statuses["FR1"] != TraceVerified || statuses["FR2"] != TracePlanned || statuses["SC1"] != TraceUncovered {
+		t.Errorf("Unexpected statuses: %v", statuses)
+	}
+
+	out := m.FormatMarkdown()
+	for _, want := range []string{
+		"**Coverage**: 2/3 requirements covered, 1 verified",
+		"| FR1 | Redirect to the provider | ✅ " + created.Filename + " task-1 | ✅ verified |",
+		"## Uncovered Requirements (1)\n\n- **SC1**: Login works end to end",
+		"success criterion unchecked",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected %q in:\n%s", want, out)
+		}
+	}
+}
+
+func TestTraceStatus(t *testing.T) {
+	passed := []tracedPlan{{filename: "plan-01-a.md", verification: &VerificationSummary{Status: "passed"}}}
+	tests := []struct {
+		name     string
+		statuses []string
+		want     string
+	}{
+		{"no tasks", nil, TraceUncovered},
+		{"all skipped", []string{TaskStatusSkipped, TaskStatusSkipped}, TraceSkipped},
+		{"done and skipped", []string{TaskStatusDone, TaskStatusSkipped}, TraceVerified},
+		{"pending and skipped", []string{TaskStatusPending, TaskStatusSkipped}, TracePlanned},
+		{"in progress", []string{TaskStatusDone, TaskStatusInProgress}, TraceInProgress},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			row := TraceRow{ID: "FR1"}
+			for i, s := range tt.statuses {
+				row.Tasks = append(row.Tasks, TraceTask{Plan: "plan-01-a.md", TaskID: fmt.Sprintf("task-%d", i+1), Status: s})
+			}
+			if got := traceStatus(row, passed); got != tt.want {
+				t.Errorf("traceStatus() = %s; want %s", got, tt.want)
+			}
+		})
+	}
+
+	m := &TraceMatrix{Rows: []TraceRow{{ID: "FR1", Status: TraceSkipped}, {ID: "FR2", Status: TraceImplemented}}}
+	if uncovered := m.Uncovered(); len(uncovered) != 1 || uncovered[0].ID != "FR1" {
+		t.Errorf("Expected skipped requirements to count as uncovered, got %+v", uncovered)
+	}
+}
+
+func TestPlanLinkedToSpec(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name    string
+		content string
+		want    bool
+	}{
+		{"spec_ref", "---\nspec_ref: auth.md\n---\n# Plan\n", true},
+		{"other spec_ref", "---\nspec_ref: oauth.md\n---\n# Plan\nSee auth.md\n", false},
+		{"link", "# Plan\n\nSee [the spec](../specs/auth.md).\n", true},
+		{"sentence", "# Plan\n\nImplements auth.md.\n", true},
+		{"longer name", "# Plan\n\nSee oauth.md and auth.md.bak\n", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, "plan.md")
+			if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+			meta, _ := ReadDocumentMeta(path)
+			if got := planLinkedToSpec(PlanSummary{Path: path, Meta: meta}, "auth.md"); got != tt.want {
+				t.Errorf("planLinkedToSpec() = %v; want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/tuanpep/oplusflow/internal/manager"
+	"github.com/tuanpep/oplusflow/internal/spec"
 )
 
 // WorkflowState represents the current state of an SDD workflow
@@ -68,6 +69,11 @@ func (ws *WorkflowState) Transition(to Phase, reason string) error {
 	if !isValidTransition(ws.CurrentPhase, to) {
 		return fmt.Errorf("invalid transition from %s to %s", ws.CurrentPhase, to)
 	}
+	if to == PhasePlan && ws.SpecPath != "" {
+		if err := requireApprovedSpec(ws.SpecPath); err != nil {
+			return err
+		}
+	}
 
 	transition := PhaseTransition{
 		From:      ws.CurrentPhase,
@@ -83,6 +89,23 @@ func (ws *WorkflowState) Transition(to Phase, reason string) error {
 	return nil
 }
 
+// requireApprovedSpec refuses planning from a spec that has not been approved
+func requireApprovedSpec(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read spec: %w", err)
+	}
+
+	status := spec.Parse(string(data)).Status
+	if status == "" {
+		status = spec.StatusDraft
+	}
+	if status != spec.StatusApproved {
+		return fmt.Errorf("spec %s is %s; it must be approved before planning (opusflow spec approve)", filepath.Base(path), status)
+	}
+	return nil
+}
+
 // isValidTransition checks if a phase transition is valid
 func isValidTransition(from, to Phase) bool {
 	validTransitions := map[Phase][]Phase{
@@ -181,6 +204,25 @@ func LoadWorkflowState() (*WorkflowState, error) {
 	return &ws, nil
 }
 
+// ActiveWorkflowID returns the ID of the saved workflow, or "" if none has been started
+func ActiveWorkflowID() string {
+	root, err := manager.FindProjectRoot()
+	if err != nil {
+		return ""
+	}
+
+	data, err := os.ReadFile(filepath.Join(root, ".opusflow", "workflow-state.json"))
+	if err != nil {
+		return ""
+	}
+
+	var ws WorkflowState
+	if err := json.Unmarshal(data, &ws); err != nil {
+		return ""
+	}
+	return ws.ID
+}
+
 // FormatStatus returns a formatted status string
 func (ws *WorkflowState) FormatStatus() string {
 	phaseEmoji := map[Phase]string{
@@ -0,0 +1,140 @@
+package plan
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var stepTargetPattern = regexp.MustCompile(`(?i)^\s*step\s+(\d+)\s*$`)
+
+// EditMode selects how new content is combined with a section's existing body
+type EditMode string
+
+// Edit modes
+const (
+	EditReplace EditMode = "replace"
+	EditAppend  EditMode = "append"
+)
+
+// ParseEditMode validates an edit mode name; empty means replace
+func ParseEditMode(s string) (EditMode, error) {
+	switch EditMode(strings.ToLower(strings.TrimSpace(s))) {
+	case "", EditReplace:
+		return EditReplace, nil
+	case EditAppend:
+		return EditAppend, nil
+	}
+	return "", fmt.Errorf("invalid edit mode %q (want replace or append)", s)
+}
+
+// StepTarget reports whether target names a step ("Step 3") and returns its number
+func StepTarget(target string) (int, bool) {
+	m := stepTargetPattern.FindStringSubmatch(target)
+	if m == nil {
+		return 0, false
+	}
+	n, err := strconv.Atoi(m[1])
+	return n, err == nil
+}
+
+// UpdateSection replaces or appends to the body of the target, which is either
+// a level-2 section title ("Observations") or a step ("Step 3"). Steps keep
+// their heading; sections keep their steps. A missing section is added at the
+// end of the document; a missing step is an error.
+func (p *Plan) UpdateSection(target, content string, mode EditMode) error {
+	if n, ok := StepTarget(target); ok {
+		st := p.Step(n)
+		if st == nil {
+			return fmt.Errorf("step %d not found", n)
+		}
+		st.Body = editBody(st.Body, content, mode)
+		return nil
+	}
+
+	name := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(target), "##"))
+	if name == "" {
+		return fmt.Errorf("section name required")
+	}
+
+	s := p.Section(name)
+	if s == nil {
+		p.addSection(name)
+		s = p.Sections[len(p.Sections)-1]
+	}
+	s.Body = editBody(s.Body, content, mode)
+	return nil
+}
+
+// addSection appends an empty level-2 section, separated from the previous content by a blank line
+func (p *Plan) addSection(name string) {
+	if n := len(p.Sections); n > 0 {
+		last := p.Sections[n-1]
+		if len(last.Steps) > 0 {
+			st := last.Steps[len(last.Steps)-1]
+			st.Body = ensureBlankLineAfter(st.Body)
+		} else {
+			last.Body = ensureBlankLineAfter(last.Body)
+		}
+	} else {
+		p.Preamble = ensureBlankLineAfter(p.Preamble)
+	}
+
+	p.Sections = append(p.Sections, &Section{Title: name})
+}
+
+func ensureBlankLineAfter(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.TrimRight(s, "\n") + "\n\n"
+}
+
+// editBody combines content with body. Trailing blank lines and "---" step
+// separators are kept after the new content, so surrounding layout is unchanged.
+func editBody(body, content string, mode EditMode) string {
+	content = strings.Trim(content, "\n")
+
+	lines := strings.Split(body, "\n")
+	cut := len(lines)
+	for cut > 0 {
+		t := strings.TrimSpace(lines[cut-1])
+		if t != "" && t != "---" {
+			break
+		}
+		cut--
+	}
+	head := strings.Join(lines[:cut], "\n")
+	tail := strings.Join(lines[cut:], "\n")
+
+	// Keep at least one blank line before whatever follows
+	if strings.Count(tail, "\n") < 1 {
+		tail = "\n"
+	}
+	if !strings.HasPrefix(tail, "\n") {
+		tail = "\n" + tail
+	}
+
+	switch {
+	case mode == EditAppend && strings.TrimSpace(head) != "":
+		return head + "\n" + content + "\n" + tail
+	case content == "":
+		return tail
+	default:
+		return content + "\n" + tail
+	}
+}
+
+// Structure summarizes the headings of a plan, used to check that an edit
+// did not add or remove sections or steps
+func (p *Plan) Structure() string {
+	var sb strings.Builder
+	for _, s := range p.Sections {
+		sb.WriteString("## " + s.Title + "\n")
+		for _, st := range s.Steps {
+			sb.WriteString(fmt.Sprintf("### Step %d\n", st.Number))
+		}
+	}
+	return sb.String()
+}
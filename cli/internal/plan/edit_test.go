@@ -0,0 +1,118 @@
+package plan
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestUpdateSection(t *testing.T) {
+	tests := []struct {
+		name    string
+		target  string
+		content string
+		mode    EditMode
+		check   func(t *testing.T, out string)
+		wantErr string
+	}{
+		{
+			name:    "replace section",
+			target:  "Observations",
+			content: "- **Current State**: JWT sessions\n",
+			mode:    EditReplace,
+			check: func(t *testing.T, out string) {
+				want := "## Observations\n- **Current State**: JWT sessions\n\n## Proposed Changes\n"
+				if !strings.Contains(out, want) {
+					t.Errorf("Expected replaced section, got:\n%s", out)
+				}
+				if strings.Contains(out, "cookie based") {
+					t.Errorf("Expected old content to be gone")
+				}
+			},
+		},
+		{
+			name:    "append to section",
+			target:  "observations",
+			content: "- **Risks**: token expiry",
+			mode:    EditAppend,
+			check: func(t *testing.T, out string) {
+				want := "- **Missing Components**: token store\n- **Risks**: token expiry\n\n## Proposed Changes\n"
+				if !strings.Contains(out, want) {
+					t.Errorf("Expected appended content, got:\n%s", out)
+				}
+			},
+		},
+		{
+			name:    "replace step keeps heading and separator",
+			target:  "Step 1",
+			content: "**File**: `internal/auth/tokens.go`\n**Action**: Create\n",
+			mode:    EditReplace,
+			check: func(t *testing.T, out string) {
+				want := "### Step 1: Add token store\n**File**: `internal/auth/tokens.go`\n**Action**: Create\n\n---\n\n### Step 2"
+				if !strings.Contains(out, want) {
+					t.Errorf("Expected replaced step, got:\n%s", out)
+				}
+			},
+		},
+		{
+			name:    "missing section is added at the end",
+			target:  "Rollout",
+			content: "Ship behind a flag",
+			mode:    EditReplace,
+			check: func(t *testing.T, out string) {
+				if !strings.HasSuffix(out, "- [ ] Tests pass\n\n## Rollout\nShip behind a flag\n\n") {
+					t.Errorf("Expected new section at the end, got:\n%s", out)
+				}
+			},
+		},
+		{
+			name:    "missing step",
+			target:  "Step 7",
+			content: "x",
+			wantErr: "step 7 not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := Parse(samplePlan)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = p.UpdateSection(tt.target, tt.content, tt.mode)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Expected error %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			out := p.Markdown()
+			tt.check(t, out)
+
+			// Everything outside the edited section is preserved
+			if !strings.HasPrefix(out, "# Add Authentication\n\nFollow the below plan verbatim.\n\n## Goal\n") {
+				t.Errorf("Expected preamble to be preserved, got:\n%s", out)
+			}
+			reparsed, err := Parse(out)
+			if err != nil {
+				t.Fatalf("Edited plan does not parse: %v", err)
+			}
+			if reparsed.Structure() != p.Structure() {
+				t.Errorf("Structure changed:\n%s\nvs\n%s", reparsed.Structure(), p.Structure())
+			}
+		})
+	}
+}
+
+func TestStepTarget(t *testing.T) {
+	if n, ok := StepTarget("step 3"); !ok || n != 3 {
+		t.Errorf("Expected step 3, got %d %v", n, ok)
+	}
+	if _, ok := StepTarget("Steps"); ok {
+		t.Errorf("Expected non-step target")
+	}
+}
@@ -0,0 +1,237 @@
+package plan
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// canonicalSections maps lower-cased section titles to their canonical spelling
+var canonicalSections = map[string]string{
+	"goal":                     SectionGoal,
+	"pre-requisites":           SectionPrerequisites,
+	"prerequisites":            SectionPrerequisites,
+	"observations":             SectionObservations,
+	"proposed changes":         SectionProposedChanges,
+	"implementation steps":     SectionSteps,
+	"verification":             SectionVerification,
+	"verification plan":        "Verification Plan",
+	"success criteria":         SectionSuccessCriteria,
+	"user review required":     "User Review Required",
+	"architecture constraints": "Architecture Constraints",
+}
+
+// StepMove records where a step ended up after a structural edit. Old is the
+// step's 1-based position before the edit (0 for a newly added step).
+type StepMove struct {
+	Old int
+	New int
+}
+
+// Format normalizes section and step headings and renumbers steps
+// sequentially in document order, rewriting "**Depends on**" references to
+// match. It returns where every step moved.
+func (p *Plan) Format() []StepMove {
+	for _, s := range p.Sections {
+		if s.heading == "" && s.title == "" {
+			continue // untitled section holding steps before the first heading
+		}
+		title := strings.TrimSpace(strings.TrimRight(strings.TrimSpace(s.Title), ":#"))
+		if canonical, ok := canonicalSections[strings.ToLower(title)]; ok {
+			title = canonical
+		}
+		s.Title = title
+		s.heading = fmt.Sprintf("## %s\n", title)
+		s.title = title
+	}
+
+	for _, st := range p.Steps {
+		st.Title = strings.TrimSpace(strings.TrimRight(strings.TrimSpace(st.Title), ":"))
+		st.heading = ""
+	}
+
+	moves := make([]StepMove, len(p.Steps))
+	for i := range p.Steps {
+		moves[i] = StepMove{Old: i + 1, New: i + 1}
+	}
+	p.renumber()
+	return moves
+}
+
+// InsertStep adds a step after step position after (0 inserts before the
+// first step) and renumbers the plan. The step is placed in the same section
+// as its neighbour, or in Implementation Steps when the plan has no steps.
+func (p *Plan) InsertStep(after int, title, body string) ([]StepMove, error) {
+	if after < 0 || after > len(p.Steps) {
+		return nil, fmt.Errorf("step %d not found (plan has %d steps)", after, len(p.Steps))
+	}
+
+	st := &Step{
+		Title:        title,
+		Body:         body,
+		Files:        []string{},
+		Actions:      []string{},
+		Verification: []string{},
+	}
+
+	before := p.currentPositions()
+	p.insertAt(after, st)
+	return p.finishMove(before), nil
+}
+
+// MoveStep moves the step at position from so it ends up at position to, and
+// renumbers the plan
+func (p *Plan) MoveStep(from, to int) ([]StepMove, error) {
+	n := len(p.Steps)
+	if from < 1 || from > n {
+		return nil, fmt.Errorf("step %d not found (plan has %d steps)", from, n)
+	}
+	if to < 1 || to > n {
+		return nil, fmt.Errorf("invalid target position %d (plan has %d steps)", to, n)
+	}
+
+	before := p.currentPositions()
+	st := p.Steps[from-1]
+	p.removeStep(st)
+	p.insertAt(to-1, st)
+	return p.finishMove(before), nil
+}
+
+// currentPositions remembers each step's position before a structural edit
+func (p *Plan) currentPositions() map[*Step]int {
+	pos := make(map[*Step]int, len(p.Steps))
+	for i, st := range p.Steps {
+		pos[st] = i + 1
+	}
+	return pos
+}
+
+func (p *Plan) finishMove(before map[*Step]int) []StepMove {
+	moves := make([]StepMove, len(p.Steps))
+	for i, st := range p.Steps {
+		moves[i] = StepMove{Old: before[st], New: i + 1}
+	}
+	p.renumber()
+	return moves
+}
+
+// renumber assigns sequential numbers in document order and rewrites Depends
+// on references, which use the numbers the steps were written with
+func (p *Plan) renumber() {
+	newNumber := make(map[int]int)
+	for i, st := range p.Steps {
+		if _, seen := newNumber[st.Number]; st.Number > 0 && !seen {
+			newNumber[st.Number] = i + 1
+		}
+	}
+
+	for i, st := range p.Steps {
+		st.Body = rewriteDependsOn(st.Body, func(n int) int {
+			if mapped, ok := newNumber[n]; ok {
+				return mapped
+			}
+			return n
+		})
+		st.DependsOn = nil
+		for _, line := range strings.Split(st.Body, "\n") {
+			if m := dependsOnPattern.FindStringSubmatch(line); m != nil {
+				st.DependsOn = append(st.DependsOn, parseStepRefs(m[1])...)
+			}
+		}
+		st.Number = i + 1
+	}
+}
+
+// rewriteDependsOn maps the step numbers in "**Depends on**" lines
+func rewriteDependsOn(body string, mapNumber func(int) int) string {
+	lines := strings.Split(body, "\n")
+	for i, line := range lines {
+		m := dependsOnPattern.FindStringSubmatchIndex(line)
+		if m == nil {
+			continue
+		}
+		refs := line[m[2]:m[3]]
+		refs = stepRefPattern.ReplaceAllStringFunc(refs, func(ref string) string {
+			sm := stepRefPattern.FindStringSubmatch(ref)
+			n, err := strconv.Atoi(sm[2])
+			if err != nil {
+				return ref
+			}
+			return sm[1] + strconv.Itoa(mapNumber(n))
+		})
+		lines[i] = line[:m[2]] + refs + line[m[3]:]
+	}
+	return strings.Join(lines, "\n")
+}
+
+// removeStep detaches a step from its section and the step list
+func (p *Plan) removeStep(st *Step) {
+	for _, s := range p.Sections {
+		for i, candidate := range s.Steps {
+			if candidate == st {
+				s.Steps = append(s.Steps[:i:i], s.Steps[i+1:]...)
+			}
+		}
+	}
+	p.rebuildSteps()
+}
+
+// insertAt places st so it becomes the step at 0-based index pos
+func (p *Plan) insertAt(pos int, st *Step) {
+	switch {
+	case pos < len(p.Steps):
+		next := p.Steps[pos]
+		s := p.sectionOf(next)
+		i := indexOfStep(s.Steps, next)
+		ensureStepSeparated(st)
+		s.Steps = append(s.Steps[:i], append([]*Step{st}, s.Steps[i:]...)...)
+	case len(p.Steps) > 0:
+		prev := p.Steps[len(p.Steps)-1]
+		s := p.sectionOf(prev)
+		prev.Body = ensureBlankLineAfter(prev.Body)
+		s.Steps = append(s.Steps, st)
+	default:
+		s := p.Section(SectionSteps)
+		if s == nil {
+			p.addSection(SectionSteps)
+			s = p.Sections[len(p.Sections)-1]
+			s.Body = "\n"
+		}
+		s.Body = ensureBlankLineAfter(s.Body)
+		s.Steps = append(s.Steps, st)
+	}
+	p.rebuildSteps()
+}
+
+// ensureStepSeparated makes sure a step inserted before another ends with a blank line
+func ensureStepSeparated(st *Step) {
+	st.Body = ensureBlankLineAfter(st.Body)
+	if st.Body == "" {
+		st.Body = "\n"
+	}
+}
+
+func (p *Plan) sectionOf(st *Step) *Section {
+	for _, s := range p.Sections {
+		if indexOfStep(s.Steps, st) >= 0 {
+			return s
+		}
+	}
+	return nil
+}
+
+func indexOfStep(steps []*Step, st *Step) int {
+	for i, candidate := range steps {
+		if candidate == st {
+			return i
+		}
+	}
+	return -1
+}
+
+func (p *Plan) rebuildSteps() {
+	p.Steps = p.Steps[:0]
+	for _, s := range p.Sections {
+		p.Steps = append(p.Steps, s.Steps...)
+	}
+}
@@ -0,0 +1,153 @@
+package plan
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+const gappyPlan = "# Gaps\n" +
+	"\n" +
+	"## goal:\n" +
+	"Do things\n" +
+	"\n" +
+	"## Implementation Steps\n" +
+	"\n" +
+	"### Step 1: First\n" +
+	"**File**: `a.go`\n" +
+	"\n" +
+	"---\n" +
+	"\n" +
+	"### Step 2 - Second\n" +
+	"**File**: `b.go`\n" +
+	"**Depends on**: Step 1\n" +
+	"\n" +
+	"---\n" +
+	"\n" +
+	"### Step 5: Third:\n" +
+	"**File**: `c.go`\n" +
+	"**Depends on**: Step 1, Step 2\n" +
+	"\n" +
+	"---\n"
+
+func TestFormat(t *testing.T) {
+	p, err := Parse(gappyPlan)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p.Format()
+	out := p.Markdown()
+
+	for _, want := range []string{
+		"## Goal\n",
+		"### Step 1: First\n",
+		"### Step 2: Second\n",
+		"### Step 3: Third\n**File**: `c.go`\n**Depends on**: Step 1, Step 2\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected %q in:\n%s", want, out)
+		}
+	}
+
+	// Formatting is idempotent
+	again, _ := Parse(out)
+	again.Format()
+	if again.Markdown() != out {
+		t.Errorf("Expected second format to be a no-op")
+	}
+}
+
+func TestInsertAndMoveStep(t *testing.T) {
+	tests := []struct {
+		name      string
+		edit      func(p *Plan) ([]StepMove, error)
+		wantOrder []string
+		wantDeps  map[int][]int
+		wantMoves []StepMove
+		wantErr   bool
+	}{
+		{
+			name: "insert in the middle",
+			edit: func(p *Plan) ([]StepMove, error) {
+				return p.InsertStep(1, "Inserted", "**File**: `x.go`\n")
+			},
+			wantOrder: []string{"First", "Inserted", "Second", "Third:"},
+			wantDeps:  map[int][]int{3: {1}, 4: {1, 3}},
+			wantMoves: []StepMove{{1, 1}, {0, 2}, {2, 3}, {3, 4}},
+		},
+		{
+			name: "insert at the end",
+			edit: func(p *Plan) ([]StepMove, error) {
+				return p.InsertStep(3, "Last", "**File**: `z.go`\n")
+			},
+			wantOrder: []string{"First", "Second", "Third:", "Last"},
+			wantDeps:  map[int][]int{2: {1}, 3: {1, 2}},
+			wantMoves: []StepMove{{1, 1}, {2, 2}, {3, 3}, {0, 4}},
+		},
+		{
+			name: "move last to first",
+			edit: func(p *Plan) ([]StepMove, error) {
+				return p.MoveStep(3, 1)
+			},
+			wantOrder: []string{"Third:", "First", "Second"},
+			wantDeps:  map[int][]int{1: {2, 3}, 3: {2}},
+			wantMoves: []StepMove{{3, 1}, {1, 2}, {2, 3}},
+		},
+		{
+			name: "move out of range",
+			edit: func(p *Plan) ([]StepMove, error) {
+				return p.MoveStep(4, 1)
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := Parse(gappyPlan)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			moves, err := tt.edit(p)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("Expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			reparsed, err := Parse(p.Markdown())
+			if err != nil {
+				t.Fatalf("Edited plan does not parse: %v\n%s", err, p.Markdown())
+			}
+
+			var order []string
+			for i, st := range reparsed.Steps {
+				order = append(order, st.Title)
+				if st.Number != i+1 {
+					t.Errorf("Expected step %d to be numbered %d, got %d", i+1, i+1, st.Number)
+				}
+				if want := tt.wantDeps[st.Number]; fmt.Sprint(st.DependsOn) != fmt.Sprint(want) {
+					t.Errorf("Step %d depends on %v, want %v", st.Number, st.DependsOn, want)
+				}
+			}
+			if strings.Join(order, "|") != strings.Join(tt.wantOrder, "|") {
+				t.Errorf("Expected order %v, got %v", tt.wantOrder, order)
+			}
+			if len(moves) != len(tt.wantMoves) {
+				t.Fatalf("Expected moves %v, got %v", tt.wantMoves, moves)
+			}
+			for i := range moves {
+				if moves[i] != tt.wantMoves[i] {
+					t.Errorf("Expected moves %v, got %v", tt.wantMoves, moves)
+					break
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,417 @@
+package plan
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/tuanpep/oplusflow/internal/frontmatter"
+)
+
+var (
+	sectionHeadingPattern = regexp.MustCompile(`^##\s+(.+?)\s*$`)
+	titleHeadingPattern   = regexp.MustCompile(`^#\s+(.+?)\s*$`)
+	stepHeadingPattern    = regexp.MustCompile(`^###\s+Step\s+([^\s:.\-–—]+)\s*[:.\-–—]?\s*(.*?)\s*$`)
+	fileLabelPattern      = regexp.MustCompile(`\*\*Files?(?::\*\*|\*\*:)`)
+	backtickPattern       = regexp.MustCompile("`([^`]+)`")
+	actionPattern         = regexp.MustCompile(`\*\*Action(?::\*\*|\*\*:)\s*(\w+)`)
+	verificationPattern   = regexp.MustCompile(`^\s*\*\*Verification(?::\*\*|\*\*:)\s*(.*)$`)
+	listItemPattern       = regexp.MustCompile(`^\s*[-*]\s+(?:\[[ xX]\]\s+)?(.+?)\s*$`)
+	checklistPattern      = regexp.MustCompile(`^\s*[-*]\s+\[([ xX])\]\s+(.*?)\s*$`)
+	fieldPattern          = regexp.MustCompile(`^\s*[-*]\s+\*\*([^*]+?):?\*\*:?\s*(.*?)\s*$`)
+	componentPattern      = regexp.MustCompile(`^###\s+(.+?)\s*$`)
+	changePattern         = regexp.MustCompile(`^####\s+(?:\[([^\]]+)\]|([A-Z]+))\s+(.+?)\s*$`)
+	dependsOnPattern      = regexp.MustCompile(`(?i)^\s*(?:[-*]\s+)?\*\*Depends on(?::\*\*|\*\*:)\s*(.*)$`)
+	stepRefPattern        = regexp.MustCompile(`(?i)\b(step\s+|task-)(\d+)\b`)
+	implementsPattern     = regexp.MustCompile(`(?i)^\s*(?:[-*]\s+)?\*\*Implements(?::\*\*|\*\*:)\s*(.*)$`)
+	requirementIDPattern  = regexp.MustCompile(`\b[A-Z]{1,5}-?\d+\b`)
+)
+
+// RequirementIDs returns the requirement IDs such as "FR-001" or "SC2" in text, in order
+func RequirementIDs(text string) []string {
+	return requirementIDPattern.FindAllString(text, -1)
+}
+
+// changeActionAliases maps accepted action spellings to their canonical action
+var changeActionAliases = map[string]ChangeAction{
+	"MODIFY": ActionModify,
+	"UPDATE": ActionModify,
+	"EDIT":   ActionModify,
+	"NEW":    ActionNew,
+	"CREATE": ActionNew,
+	"ADD":    ActionNew,
+	"DELETE": ActionDelete,
+	"REMOVE": ActionDelete,
+}
+
+// parser holds the state of a single Parse call
+type parser struct {
+	plan *Plan
+	errs ParseErrors
+
+	preamble strings.Builder
+	section  *Section
+	step     *Step
+
+	// step body state
+	stepEnded    bool
+	inStepVerify bool
+	description  strings.Builder
+
+	// code fence state
+	fence     string
+	fenceLine int
+}
+
+// Parse parses a plan document.
+//
+// Parse always returns a best-effort Plan. The error, if any, is a ParseErrors
+// value listing structural problems with their line numbers: unterminated code
+// fences, step headings without a numeric step number, and Proposed Changes
+// entries with an unknown action.
+func Parse(content string) (*Plan, error) {
+	ps := &parser{plan: &Plan{}}
+
+	// Frontmatter is kept verbatim in the preamble
+	_, _, bodyLine, _ := frontmatter.Split(content)
+
+	for i, raw := range strings.SplitAfter(content, "\n") {
+		if raw == "" {
+			continue
+		}
+		if i+1 < bodyLine {
+			ps.preamble.WriteString(raw)
+			continue
+		}
+		ps.parseLine(i+1, raw)
+	}
+	ps.finishStep()
+
+	if ps.fence != "" {
+		ps.errorf(ps.fenceLine, "unterminated code fence")
+	}
+
+	p := ps.plan
+	p.Preamble = ps.preamble.String()
+	for _, s := range p.Sections {
+		ps.parseSection(s)
+	}
+
+	if len(ps.errs) > 0 {
+		sort.SliceStable(ps.errs, func(i, j int) bool { return ps.errs[i].Line < ps.errs[j].Line })
+		return p, ps.errs
+	}
+	return p, nil
+}
+
+func (ps *parser) errorf(line int, format string, args ...interface{}) {
+	ps.errs = append(ps.errs, &ParseError{Line: line, Msg: fmt.Sprintf(format, args...)})
+}
+
+func (ps *parser) parseLine(lineNo int, raw string) {
+	text := strings.TrimRight(raw, "\r\n")
+	trimmed := strings.TrimSpace(text)
+
+	if ps.fence != "" {
+		if strings.HasPrefix(trimmed, ps.fence) {
+			ps.fence = ""
+		}
+		ps.appendRaw(raw, text)
+		return
+	}
+
+	if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+		ps.fence = trimmed[:3]
+		ps.fenceLine = lineNo
+		ps.appendRaw(raw, text)
+		return
+	}
+
+	if m := sectionHeadingPattern.FindStringSubmatch(text); m != nil {
+		ps.finishStep()
+		ps.section = &Section{Title: m[1], Line: lineNo, heading: raw, title: m[1]}
+		ps.plan.Sections = append(ps.plan.Sections, ps.section)
+		return
+	}
+
+	if m := stepHeadingPattern.FindStringSubmatch(text); m != nil {
+		number, err := strconv.Atoi(m[1])
+		if err != nil {
+			ps.errorf(lineNo, "invalid step number %q in heading %q", m[1], trimmed)
+		} else {
+			ps.startStep(lineNo, raw, number, m[2])
+			return
+		}
+	}
+
+	if ps.plan.Title == "" {
+		if m := titleHeadingPattern.FindStringSubmatch(text); m != nil {
+			ps.plan.Title = m[1]
+		}
+	}
+
+	if fileLabelPattern.MatchString(text) {
+		for _, m := range backtickPattern.FindAllStringSubmatch(text, -1) {
+			ps.plan.FileRefs = append(ps.plan.FileRefs, FileRef{Path: strings.TrimSpace(m[1]), Line: lineNo})
+		}
+	}
+
+	ps.appendRaw(raw, text)
+}
+
+func (ps *parser) startStep(lineNo int, raw string, number int, title string) {
+	ps.finishStep()
+
+	if ps.section == nil {
+		// Steps before the first section live in an untitled section with no heading
+		ps.section = &Section{Line: lineNo}
+		ps.plan.Sections = append(ps.plan.Sections, ps.section)
+	}
+
+	ps.step = &Step{
+		Number:       number,
+		Title:        title,
+		Line:         lineNo,
+		Files:        []string{},
+		Actions:      []string{},
+		Verification: []string{},
+		heading:      raw,
+		number:       number,
+		title:        title,
+	}
+	ps.section.Steps = append(ps.section.Steps, ps.step)
+	ps.plan.Steps = append(ps.plan.Steps, ps.step)
+	ps.stepEnded = false
+	ps.inStepVerify = false
+	ps.description.Reset()
+}
+
+func (ps *parser) finishStep() {
+	if ps.step != nil {
+		ps.step.Description = strings.TrimSpace(ps.description.String())
+		ps.step = nil
+	}
+}
+
+// appendRaw adds a line to the current step, section or preamble
+func (ps *parser) appendRaw(raw, text string) {
+	switch {
+	case ps.step != nil:
+		ps.step.Body += raw
+		ps.parseStepLine(text)
+	case ps.section != nil:
+		ps.section.Body += raw
+	default:
+		ps.preamble.WriteString(raw)
+	}
+}
+
+// parseStepLine extracts files, actions and verification from a step body line.
+// Content after a horizontal rule is kept in the body but not interpreted.
+func (ps *parser) parseStepLine(text string) {
+	if ps.stepEnded {
+		return
+	}
+	if strings.HasPrefix(text, "---") {
+		ps.stepEnded = true
+		return
+	}
+
+	st := ps.step
+	ps.description.WriteString(text)
+	ps.description.WriteString("\n")
+
+	if fileLabelPattern.MatchString(text) {
+		for _, m := range backtickPattern.FindAllStringSubmatch(text, -1) {
+			st.Files = appendUnique(st.Files, strings.TrimSpace(m[1]))
+		}
+	}
+
+	if m := actionPattern.FindStringSubmatch(text); m != nil {
+		st.Actions = appendUnique(st.Actions, m[1])
+	}
+
+	if m := dependsOnPattern.FindStringSubmatch(text); m != nil {
+		st.DependsOn = append(st.DependsOn, parseStepRefs(m[1])...)
+	}
+
+	if m := implementsPattern.FindStringSubmatch(text); m != nil {
+		for _, id := range RequirementIDs(m[1]) {
+			st.Implements = appendUnique(st.Implements, id)
+		}
+	}
+
+	if m := verificationPattern.FindStringSubmatch(text); m != nil {
+		ps.inStepVerify = true
+		if m[1] != "" {
+			st.Verification = append(st.Verification, m[1])
+		}
+		return
+	}
+
+	if ps.inStepVerify {
+		trimmed := strings.TrimSpace(text)
+		switch {
+		case trimmed == "":
+		case strings.HasPrefix(trimmed, "**"):
+			ps.inStepVerify = false
+		default:
+			if m := listItemPattern.FindStringSubmatch(text); m != nil {
+				st.Verification = append(st.Verification, m[1])
+			} else {
+				ps.inStepVerify = false
+			}
+		}
+	}
+}
+
+// parseSection fills the typed plan fields from a well-known section
+func (ps *parser) parseSection(s *Section) {
+	p := ps.plan
+
+	switch {
+	case sectionTitleMatches(s.Title, SectionGoal):
+		p.Goal = strings.TrimSpace(s.Body)
+	case sectionTitleMatches(s.Title, SectionPrerequisites), sectionTitleMatches(s.Title, "Prerequisites"):
+		p.Prerequisites = parseFields(s)
+	case sectionTitleMatches(s.Title, SectionObservations):
+		p.Observations = parseFields(s)
+	case sectionTitleMatches(s.Title, SectionProposedChanges):
+		p.Changes = ps.parseChanges(s)
+	case sectionTitleMatches(s.Title, SectionVerification), sectionTitleMatches(s.Title, "Verification Plan"):
+		p.Verification = strings.TrimSpace(s.Body)
+	case sectionTitleMatches(s.Title, SectionSuccessCriteria):
+		p.SuccessCriteria = parseChecklist(s)
+	}
+}
+
+// bodyLines returns the lines of a section body with their line numbers, skipping fenced code
+func bodyLines(s *Section) ([]string, []int) {
+	var lines []string
+	var numbers []int
+
+	fence := ""
+	for i, l := range strings.Split(strings.TrimSuffix(s.Body, "\n"), "\n") {
+		trimmed := strings.TrimSpace(l)
+		if fence != "" {
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+			continue
+		}
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			fence = trimmed[:3]
+			continue
+		}
+		lines = append(lines, strings.TrimRight(l, "\r"))
+		numbers = append(numbers, s.Line+1+i)
+	}
+
+	return lines, numbers
+}
+
+// parseFields reads "- **Key**: Value" entries; indented continuation lines extend the value
+func parseFields(s *Section) []Field {
+	var fields []Field
+	lines, numbers := bodyLines(s)
+
+	for i, l := range lines {
+		if m := fieldPattern.FindStringSubmatch(l); m != nil && !strings.HasPrefix(l, " ") && !strings.HasPrefix(l, "\t") {
+			fields = append(fields, Field{Key: strings.TrimSpace(m[1]), Value: m[2], Line: numbers[i]})
+			continue
+		}
+
+		trimmed := strings.TrimSpace(l)
+		if len(fields) > 0 && trimmed != "" && (strings.HasPrefix(l, " ") || strings.HasPrefix(l, "\t")) {
+			last := &fields[len(fields)-1]
+			if last.Value == "" {
+				last.Value = trimmed
+			} else {
+				last.Value += "\n" + trimmed
+			}
+		}
+	}
+
+	return fields
+}
+
+// parseChecklist reads "- [ ] text" entries
+func parseChecklist(s *Section) []ChecklistItem {
+	var items []ChecklistItem
+	lines, numbers := bodyLines(s)
+
+	for i, l := range lines {
+		if m := checklistPattern.FindStringSubmatch(l); m != nil {
+			items = append(items, ChecklistItem{Text: m[2], Checked: m[1] != " ", Line: numbers[i]})
+		}
+	}
+
+	return items
+}
+
+// parseChanges reads "### Component" / "#### [ACTION] file" entries under Proposed Changes
+func (ps *parser) parseChanges(s *Section) []Change {
+	var changes []Change
+	component := ""
+	lines, numbers := bodyLines(s)
+
+	for i, l := range lines {
+		if m := changePattern.FindStringSubmatch(l); m != nil {
+			raw := strings.TrimSpace(m[1] + m[2])
+			action, ok := changeActionAliases[strings.ToUpper(raw)]
+			if !ok && !strings.Contains(raw, "|") {
+				ps.errorf(numbers[i], "unknown change action %q (expected MODIFY, NEW or DELETE)", raw)
+			}
+			changes = append(changes, Change{
+				Component: component,
+				Action:    action,
+				RawAction: raw,
+				File:      strings.Trim(m[3], "`"),
+				Line:      numbers[i],
+			})
+			continue
+		}
+
+		if m := componentPattern.FindStringSubmatch(l); m != nil {
+			component = m[1]
+			continue
+		}
+
+		if len(changes) == 0 {
+			continue
+		}
+		if m := fieldPattern.FindStringSubmatch(l); m != nil {
+			last := &changes[len(changes)-1]
+			switch strings.ToLower(strings.TrimSpace(m[1])) {
+			case "reason":
+				last.Reason = m[2]
+			case "complexity":
+				last.Complexity = m[2]
+			}
+		}
+	}
+
+	return changes
+}
+
+// parseStepRefs returns the step numbers referenced as "Step N" or "task-N"
+func parseStepRefs(text string) []int {
+	var refs []int
+	for _, m := range stepRefPattern.FindAllStringSubmatch(text, -1) {
+		if n, err := strconv.Atoi(m[2]); err == nil {
+			refs = append(refs, n)
+		}
+	}
+	return refs
+}
+
+func appendUnique(list []string, value string) []string {
+	for _, v := range list {
+		if v == value {
+			return list
+		}
+	}
+	return append(list, value)
+}
@@ -0,0 +1,235 @@
+// Package plan provides a typed model of opusflow plan documents (PLAN.md).
+//
+// A plan is parsed into an ordered list of level-2 sections. Implementation
+// steps ("### Step N: Title") are split out of the section that contains them,
+// and well-known sections (Goal, Pre-requisites, Observations, Proposed Changes,
+// Verification, Success Criteria) are exposed as typed fields. The raw text of
+// every section and step is preserved, so Markdown() reproduces the original
+// document byte for byte unless the plan was edited.
+package plan
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Well-known section titles
+const (
+	SectionGoal            = "Goal"
+	SectionPrerequisites   = "Pre-requisites"
+	SectionObservations    = "Observations"
+	SectionProposedChanges = "Proposed Changes"
+	SectionSteps           = "Implementation Steps"
+	SectionVerification    = "Verification"
+	SectionSuccessCriteria = "Success Criteria"
+)
+
+// ChangeAction is the kind of change listed under Proposed Changes
+type ChangeAction string
+
+// Change actions
+const (
+	ActionModify ChangeAction = "MODIFY"
+	ActionNew    ChangeAction = "NEW"
+	ActionDelete ChangeAction = "DELETE"
+)
+
+// Plan is the typed model of a plan document
+type Plan struct {
+	// Title is the first level-1 heading, if any
+	Title string
+	// Preamble is the raw text before the first level-2 section
+	Preamble string
+	// Sections are the level-2 sections in document order
+	Sections []*Section
+
+	Goal            string
+	Prerequisites   []Field
+	Observations    []Field
+	Changes         []Change
+	Steps           []*Step
+	Verification    string
+	SuccessCriteria []ChecklistItem
+	// FileRefs lists every **File** reference in the document, including ones outside steps
+	FileRefs []FileRef
+}
+
+// Section is a level-2 ("## ") section of the plan
+type Section struct {
+	Title string
+	Line  int
+	// Body is the raw text after the heading and before the first step
+	Body  string
+	Steps []*Step
+
+	heading string
+	title   string
+}
+
+// Step is an implementation step ("### Step N: Title")
+type Step struct {
+	Number int
+	Title  string
+	Line   int
+	// Body is the raw text after the step heading
+	Body string
+
+	Files        []string
+	Actions      []string
+	Description  string
+	Verification []string
+	// DependsOn lists the step numbers from a "**Depends on**: Step 1, Step 2" line
+	DependsOn []int
+	// Implements lists the requirement IDs from an "**Implements**: FR1, SC2" line
+	Implements []string
+
+	heading string
+	number  int
+	title   string
+}
+
+// Field is a "- **Key**: Value" entry
+type Field struct {
+	Key   string
+	Value string
+	Line  int
+}
+
+// Change is a file entry under Proposed Changes ("#### [MODIFY] path")
+type Change struct {
+	Component  string
+	Action     ChangeAction // empty when the action is an unfilled placeholder
+	RawAction  string
+	File       string
+	Reason     string
+	Complexity string
+	Line       int
+}
+
+// ChecklistItem is a "- [ ] text" entry
+type ChecklistItem struct {
+	Text    string
+	Checked bool
+	Line    int
+}
+
+// FileRef is a file path referenced with **File**
+type FileRef struct {
+	Path string
+	Line int
+}
+
+// ParseError is a structural problem found while parsing a plan
+type ParseError struct {
+	Line int
+	Msg  string
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Msg)
+}
+
+// ParseErrors collects every ParseError found in a document
+type ParseErrors []*ParseError
+
+func (errs ParseErrors) Error() string {
+	msgs := make([]string, len(errs))
+	for i, e := range errs {
+		msgs[i] = e.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// Section returns the first section whose title matches name (case-insensitive), or nil
+func (p *Plan) Section(name string) *Section {
+	for _, s := range p.Sections {
+		if sectionTitleMatches(s.Title, name) {
+			return s
+		}
+	}
+	return nil
+}
+
+// Step returns the step with the given number, or nil
+func (p *Plan) Step(number int) *Step {
+	for _, s := range p.Steps {
+		if s.Number == number {
+			return s
+		}
+	}
+	return nil
+}
+
+// ReferencedFiles returns the unique file paths referenced by the plan, in document order
+func (p *Plan) ReferencedFiles() []string {
+	seen := make(map[string]bool)
+	var files []string
+	for _, ref := range p.FileRefs {
+		if !seen[ref.Path] {
+			seen[ref.Path] = true
+			files = append(files, ref.Path)
+		}
+	}
+	return files
+}
+
+// DeletedFiles returns the files the plan marks for deletion under Proposed Changes
+func (p *Plan) DeletedFiles() []string {
+	var files []string
+	for _, c := range p.Changes {
+		if c.Action == ActionDelete {
+			files = append(files, c.File)
+		}
+	}
+	return files
+}
+
+// Markdown serializes the plan back to markdown.
+// Unedited headings are written exactly as they were parsed.
+func (p *Plan) Markdown() string {
+	var sb strings.Builder
+
+	sb.WriteString(p.Preamble)
+	for _, s := range p.Sections {
+		sb.WriteString(s.headingLine())
+		sb.WriteString(s.Body)
+		for _, st := range s.Steps {
+			sb.WriteString(st.headingLine())
+			sb.WriteString(st.Body)
+		}
+	}
+
+	return sb.String()
+}
+
+func (s *Section) headingLine() string {
+	// The untitled section holding steps before the first heading has no heading line
+	if s.Title == s.title {
+		return s.heading
+	}
+	return fmt.Sprintf("## %s\n", s.Title)
+}
+
+func (st *Step) headingLine() string {
+	if st.heading != "" && st.Number == st.number && st.Title == st.title {
+		return st.heading
+	}
+	return FormatStepHeading(st.Number, st.Title)
+}
+
+// FormatStepHeading renders the canonical step heading line
+func FormatStepHeading(number int, title string) string {
+	if title == "" {
+		return fmt.Sprintf("### Step %d\n", number)
+	}
+	return fmt.Sprintf("### Step %d: %s\n", number, title)
+}
+
+// sectionTitleMatches compares a section title (or raw heading) against a name
+func sectionTitleMatches(title, name string) bool {
+	normalize := func(s string) string {
+		s = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(s), "##"))
+		return strings.ToLower(strings.TrimSuffix(s, ":"))
+	}
+	return normalize(title) == normalize(name)
+}
@@ -0,0 +1,200 @@
+package spec
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/tuanpep/oplusflow/internal/frontmatter"
+)
+
+var (
+	headingPattern     = regexp.MustCompile(`^(#{1,3})\s+(.+?)\s*#*\s*$`)
+	statusPattern      = regexp.MustCompile(`^\s*\*\*Status\*\*:\s*(.*?)\s*$`)
+	requirementPattern = regexp.MustCompile(`^\s*[-*]\s+(?:\[([ xX])\]\s+)?\**([A-Z]{1,5}-?\d+)(?:\*\*\s*[:.\-–]?|\s*[:.\-–]\**)\s*(.*?)\s*$`)
+	listItemPattern    = regexp.MustCompile(`^\s*(?:[-*]|\d+[.)])\s+(?:\[([ xX])\]\s+)?(.+?)\s*$`)
+	commentPattern     = regexp.MustCompile(`<!--.*?-->`)
+	titlePrefixPattern = regexp.MustCompile(`(?i)^feature specification:\s*`)
+)
+
+// line is a body line with its 1-based line number in the document
+type line struct {
+	text   string
+	number int
+}
+
+// Parse parses a specification document. Parsing never fails: sections that
+// are missing or malformed simply leave the corresponding fields empty.
+// The status comes from the frontmatter when present, otherwise from the
+// "**Status**:" line.
+func Parse(content string) *Spec {
+	s := &Spec{}
+	bodies := make(map[*Section][]line)
+
+	_, _, bodyLine, _ := frontmatter.Split(content)
+	if m, _, err := frontmatter.Parse(content); err == nil && m != nil {
+		s.Meta = m
+		s.Status = NormalizeStatus(m.Status)
+	}
+
+	var current *Section
+	fence := ""
+	for i, raw := range strings.Split(content, "\n") {
+		number := i + 1
+		if number < bodyLine {
+			continue
+		}
+		text := strings.TrimRight(raw, "\r")
+		trimmed := strings.TrimSpace(text)
+
+		if fence != "" || strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			switch {
+			case fence == "":
+				fence = trimmed[:3]
+			case strings.HasPrefix(trimmed, fence):
+				fence = ""
+			}
+			if current != nil {
+				current.Body += text + "\n"
+			}
+			continue
+		}
+
+		if m := headingPattern.FindStringSubmatch(text); m != nil {
+			if len(m[1]) == 1 {
+				if s.Title == "" {
+					s.Title = titlePrefixPattern.ReplaceAllString(m[2], "")
+				}
+				continue
+			}
+			current = &Section{Level: len(m[1]), Title: m[2], Line: number}
+			s.Sections = append(s.Sections, current)
+			continue
+		}
+
+		if s.StatusLine == 0 && s.Meta == nil {
+			if m := statusPattern.FindStringSubmatch(text); m != nil {
+				s.Status = NormalizeStatus(m[1])
+				s.StatusLine = number
+				continue
+			}
+		}
+
+		if current != nil {
+			current.Body += text + "\n"
+			bodies[current] = append(bodies[current], line{text: commentPattern.ReplaceAllString(text, ""), number: number})
+		}
+	}
+
+	for _, sec := range s.Sections {
+		s.parseSection(sec, bodies[sec])
+	}
+
+	return s
+}
+
+// NormalizeStatus strips decoration such as emoji from a status value and
+// returns the canonical status name when it is a known status
+func NormalizeStatus(value string) string {
+	value = strings.TrimFunc(value, func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+	for _, known := range []string{StatusDraft, StatusInReview, StatusApproved, StatusRejected} {
+		if strings.EqualFold(value, known) {
+			return known
+		}
+	}
+	return value
+}
+
+func (s *Spec) parseSection(sec *Section, lines []line) {
+	switch {
+	case strings.EqualFold(sec.Title, SectionGoal):
+		s.Goal = parseGoal(lines)
+	case strings.EqualFold(sec.Title, SectionFunctionalReqs):
+		s.FunctionalRequirements = parseRequirements(lines, "FR")
+	case strings.EqualFold(sec.Title, SectionConstraints):
+		s.Constraints = parseItems(lines)
+	case strings.EqualFold(sec.Title, SectionEdgeCases):
+		s.EdgeCases = parseEdgeCases(lines)
+	case strings.EqualFold(sec.Title, SectionSuccessCriteria):
+		s.SuccessCriteria = parseRequirements(lines, "SC")
+	case strings.EqualFold(sec.Title, SectionOpenQuestions):
+		s.OpenQuestions = parseItems(lines)
+	}
+}
+
+// parseGoal joins the goal text, dropping quote markers and placeholder lines
+func parseGoal(lines []line) string {
+	var parts []string
+	for _, l := range lines {
+		text := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(l.text), ">"))
+		if text == "" || IsPlaceholder(text) {
+			continue
+		}
+		parts = append(parts, text)
+	}
+	return strings.Join(parts, "\n")
+}
+
+// parseRequirements reads list entries; entries without an ID are numbered with prefix
+func parseRequirements(lines []line, prefix string) []Requirement {
+	var reqs []Requirement
+	for _, l := range lines {
+		if m := requirementPattern.FindStringSubmatch(l.text); m != nil {
+			reqs = append(reqs, Requirement{
+				ID:      m[2],
+				Text:    m[3],
+				Checked: m[1] != "" && m[1] != " ",
+				Line:    l.number,
+			})
+			continue
+		}
+		if m := listItemPattern.FindStringSubmatch(l.text); m != nil {
+			reqs = append(reqs, Requirement{
+				ID:      fmt.Sprintf("%s%d", prefix, len(reqs)+1),
+				Text:    m[2],
+				Checked: m[1] != "" && m[1] != " ",
+				Line:    l.number,
+			})
+		}
+	}
+	return reqs
+}
+
+func parseItems(lines []line) []Item {
+	var items []Item
+	for _, l := range lines {
+		if m := listItemPattern.FindStringSubmatch(l.text); m != nil {
+			items = append(items, Item{Text: m[2], Checked: m[1] != "" && m[1] != " ", Line: l.number})
+		}
+	}
+	return items
+}
+
+// parseEdgeCases reads the "| Edge Case | Expected Behavior |" table
+func parseEdgeCases(lines []line) []EdgeCase {
+	var cases []EdgeCase
+	header := true
+	for _, l := range lines {
+		text := strings.TrimSpace(l.text)
+		if !strings.HasPrefix(text, "|") {
+			continue
+		}
+		cells := strings.Split(strings.Trim(text, "|"), "|")
+		if header {
+			header = false
+			continue
+		}
+		if strings.Trim(cells[0], " -:") == "" {
+			continue // separator row
+		}
+		ec := EdgeCase{Case: strings.TrimSpace(cells[0]), Line: l.number}
+		if len(cells) > 1 {
+			ec.Expected = strings.TrimSpace(cells[1])
+		}
+		cases = append(cases, ec)
+	}
+	return cases
+}
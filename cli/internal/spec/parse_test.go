@@ -0,0 +1,162 @@
+package spec
+
+import (
+	"fmt"
+	"testing"
+)
+
+const sampleSpec = "# Feature Specification: OAuth Login\n" +
+	"\n" +
+	"**Created**: 2026-01-10 10:00\n" +
+	"**Status**: ✅ Approved\n" +
+	"\n" +
+	"## Goal\n" +
+	"\n" +
+	"> Let users sign in with Google\n" +
+	"\n" +
+	"<!-- Describe the high-level objective -->\n" +
+	"[TODO: Expand the goal description here]\n" +
+	"\n" +
+	"## Requirements\n" +
+	"\n" +
+	"### Functional Requirements\n" +
+	"\n" +
+	"- [ ] **FR1**: Redirect to the provider\n" +
+	"- [x] **FR2:** Store the token\n" +
+	"- FR3: Refresh expired tokens\n" +
+	"- [ ] **FR4**: [Description of Functional Requirement]\n" +
+	"\n" +
+	"## Architecture Constraints\n" +
+	"\n" +
+	"- Must use golang.org/x/oauth2\n" +
+	"- Must integrate with: [existing service/component]\n" +
+	"\n" +
+	"## Edge Cases\n" +
+	"\n" +
+	"| Edge Case | Expected Behavior |\n" +
+	"|-----------|-------------------|\n" +
+	"| Denied consent | Show an error |\n" +
+	"\n" +
+	"## Success Criteria\n" +
+	"\n" +
+	"- [ ] **SC1**: Login works end to end\n" +
+	"- [ ] HTTP2 clients are supported\n" +
+	"\n" +
+	"## Open Questions\n" +
+	"\n" +
+	"1. Which providers besides Google?\n"
+
+func TestParse(t *testing.T) {
+	s := Parse(sampleSpec)
+
+	if s.Title != "OAuth Login" {
+		t.Errorf("Expected title without prefix, got %q", s.Title)
+	}
+	if s.Status != StatusApproved || s.StatusLine != 4 {
+		t.Errorf("Expected Approved on line 4, got %q on %d", s.Status, s.StatusLine)
+	}
+	if s.Goal != "Let users sign in with Google" {
+		t.Errorf("Unexpected goal: %q", s.Goal)
+	}
+
+	if len(s.FunctionalRequirements) != 4 {
+		t.Fatalf("Expected 4 requirements, got %+v", s.FunctionalRequirements)
+	}
+	fr2 := s.FunctionalRequirements[1]
+	if fr2.ID != "FR2" || fr2.Text != "Store the token" || !fr2.Checked || fr2.Line != 18 {
+		t.Errorf("Unexpected FR2: %+v", fr2)
+	}
+	if s.FunctionalRequirements[2].ID != "FR3" {
+		t.Errorf("Expected plain FR3 to be parsed, got %+v", s.FunctionalRequirements[2])
+	}
+	if filled := s.FilledRequirements(); len(filled) != 3 {
+		t.Errorf("Expected placeholder requirement to be skipped, got %+v", filled)
+	}
+
+	if len(s.Constraints) != 2 || s.Constraints[0].Text != "Must use golang.org/x/oauth2" {
+		t.Errorf("Unexpected constraints: %+v", s.Constraints)
+	}
+	if len(s.EdgeCases) != 1 || s.EdgeCases[0].Expected != "Show an error" {
+		t.Errorf("Unexpected edge cases: %+v", s.EdgeCases)
+	}
+
+	if len(s.SuccessCriteria) != 2 || s.SuccessCriteria[1].ID != "SC2" || s.SuccessCriteria[1].Text != "HTTP2 clients are supported" {
+		t.Errorf("Expected criteria without IDs to be numbered, got %+v", s.SuccessCriteria)
+	}
+	if len(s.OpenQuestions) != 1 {
+		t.Errorf("Unexpected open questions: %+v", s.OpenQuestions)
+	}
+
+	if s.Requirement("sc1") == nil || s.Requirement("FR9") != nil {
+		t.Error("Unexpected Requirement lookup result")
+	}
+}
+
+func TestNormalizeStatus(t *testing.T) {
+	tests := map[string]string{
+		"📝 Draft":      StatusDraft,
+		"🔍 in review":  StatusInReview,
+		"**Approved**": StatusApproved,
+		"Shipped":      "Shipped",
+		"":             "",
+	}
+
+	for in, want := range tests {
+		if got := NormalizeStatus(in); got != want {
+			t.Errorf("NormalizeStatus(%q) = %q; want %q", in, got, want)
+		}
+	}
+}
+
+func TestIsPlaceholder(t *testing.T) {
+	tests := map[string]bool{
+		"[TODO: fill me]":                 true,
+		"As a **[User Role]**, I want it": true,
+		"See [the docs](https://x.y)":     false,
+		"- [ ] plain checklist":           false,
+		"- [X] done":                      false,
+		"> [!NOTE] Keep it short":         false,
+		"Clamp `v[0]` to [0, 100]":        false,
+		"Nothing to fill":                 false,
+	}
+
+	for in, want := range tests {
+		if got := IsPlaceholder(in); got != want {
+			t.Errorf("IsPlaceholder(%q) = %v; want %v", in, got, want)
+		}
+	}
+}
+
+func TestPlaceholders(t *testing.T) {
+	tests := map[string]string{
+		"- [ ] As a **[User Role]**, I want **[Feature]**":                           "[[User Role] [Feature]]",
+		"[TODO: Expand the goal]":                                                    "[[TODO: Expand the goal]]",
+		"> [!NOTE] see [docs](https://x.y)":                                          "[]",
+		"- [x] done":                                                                 "[]",
+		"- [x] **FR1**: Reject `items[0]`, clamp to [0, 100]":                        "[]",
+		"Use ```\nx := a[i]\n``` then [Describe usage]":                              "[[Describe usage]]",
+		"See [the guide][guide] and [docs]\n[guide]: https://x.y\n[docs]: ./docs.md": "[]",
+		"[Component Name]: [Why]":                                                    "[[Component Name] [Why]]",
+	}
+
+	for in, want := range tests {
+		if got := fmt.Sprint(Placeholders(in)); got != want {
+			t.Errorf("Placeholders(%q) = %s; want %s", in, got, want)
+		}
+	}
+}
+
+func TestParse_Frontmatter(t *testing.T) {
+	content := "---\nid: spec-x\nkind: spec\nstatus: approved\n---\n# Title\n\n**Status**: 📝 Draft\n\n## Goal\nDo it\n"
+
+	s := Parse(content)
+	if s.Meta == nil || s.Meta.ID != "spec-x" {
+		t.Fatalf("Expected frontmatter to be parsed, got %+v", s.Meta)
+	}
+	if s.Status != StatusApproved {
+		t.Errorf("Expected frontmatter status to win, got %q", s.Status)
+	}
+	if s.Title != "Title" || s.Goal != "Do it" || s.Section(SectionGoal).Line != 10 {
+		t.Errorf("Unexpected spec: %+v", s)
+	}
+}
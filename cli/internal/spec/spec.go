@@ -0,0 +1,179 @@
+// Package spec provides a typed model of opusflow feature specifications (SPEC.md).
+//
+// A spec is parsed into a flat list of level-2 and level-3 sections. Well-known
+// sections (Goal, Functional Requirements, Architecture Constraints, Edge Cases,
+// Success Criteria, Open Questions) are exposed as typed fields. The status is
+// read from the YAML frontmatter, or from the "**Status**:" line of older specs.
+package spec
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/tuanpep/oplusflow/internal/frontmatter"
+)
+
+// Well-known section titles
+const (
+	SectionGoal              = "Goal"
+	SectionUserStories       = "User Stories"
+	SectionFunctionalReqs    = "Functional Requirements"
+	SectionNonFunctionalReqs = "Non-Functional Requirements"
+	SectionConstraints       = "Architecture Constraints"
+	SectionEdgeCases         = "Edge Cases"
+	SectionOutOfScope        = "Out of Scope"
+	SectionSuccessCriteria   = "Success Criteria"
+	SectionOpenQuestions     = "Open Questions"
+)
+
+// Spec statuses
+const (
+	StatusDraft    = "Draft"
+	StatusInReview = "In Review"
+	StatusApproved = "Approved"
+	StatusRejected = "Rejected"
+)
+
+// Spec is the typed model of a specification document
+type Spec struct {
+	// Title is the first level-1 heading without the "Feature Specification:" prefix
+	Title string
+	// Status is the normalized status (Draft, In Review, Approved, Rejected), or empty
+	Status string
+	// StatusLine is the line number of the "**Status**:" line, or 0
+	StatusLine int
+	// Meta is the frontmatter, or nil for specs without one
+	Meta     *frontmatter.Meta
+	Sections []*Section
+
+	Goal                   string
+	FunctionalRequirements []Requirement
+	Constraints            []Item
+	EdgeCases              []EdgeCase
+	SuccessCriteria        []Requirement
+	OpenQuestions          []Item
+}
+
+// Section is a level-2 or level-3 section of the spec
+type Section struct {
+	Level int
+	Title string
+	Line  int
+	Body  string
+}
+
+// Requirement is an identified list entry such as "- [ ] **FR1**: text"
+type Requirement struct {
+	ID      string
+	Text    string
+	Checked bool
+	Line    int
+}
+
+// Item is a plain list entry
+type Item struct {
+	Text    string
+	Checked bool
+	Line    int
+}
+
+// EdgeCase is a row of the Edge Cases table
+type EdgeCase struct {
+	Case     string
+	Expected string
+	Line     int
+}
+
+var (
+	bracketPattern  = regexp.MustCompile(`\[[^\]\n]+\]`)
+	codeSpanPattern = regexp.MustCompile("(?s)```.*?```|`[^`\n]+`")
+	linkDefPattern  = regexp.MustCompile(`(?m)^ {0,3}\[([^\]\n]+)\]:[ \t]+[^\s\[]\S*[ \t]*$`)
+)
+
+// IsPlaceholder reports whether text still contains an unfilled template
+// placeholder such as "[TODO: ...]" or "[User Role]", using the same rules as
+// Placeholders.
+func IsPlaceholder(text string) bool {
+	return len(Placeholders(text)) > 0
+}
+
+// Placeholders returns the unfilled template placeholders in text, in order:
+// bracketed prose outside code spans. Markdown links (inline and reference
+// style), checkboxes, admonitions such as "[!NOTE]" and brackets without
+// letters such as "[0, 100]" are skipped.
+func Placeholders(text string) []string {
+	// Blank out code so indexes still line up with text
+	prose := codeSpanPattern.ReplaceAllStringFunc(text, func(code string) string {
+		return strings.Repeat(" ", len(code))
+	})
+
+	defined := make(map[string]bool)
+	for _, m := range linkDefPattern.FindAllStringSubmatch(prose, -1) {
+		defined[strings.ToLower(m[1])] = true
+	}
+
+	var out []string
+	for _, loc := range bracketPattern.FindAllStringIndex(prose, -1) {
+		m := prose[loc[0]:loc[1]]
+		var next, prev byte
+		if loc[1] < len(prose) {
+			next = prose[loc[1]]
+		}
+		if loc[0] > 0 {
+			prev = prose[loc[0]-1]
+		}
+		switch {
+		case m == "[ ]" || m == "[x]" || m == "[X]" || strings.HasPrefix(m, "[!"):
+			continue
+		case !strings.ContainsFunc(m, unicode.IsLetter):
+			continue // index, range or number
+		case next == '(' || next == '[' || prev == ']' || defined[strings.ToLower(m[1:len(m)-1])]:
+			continue // inline or reference link, or its definition
+		}
+		out = append(out, m)
+	}
+	return out
+}
+
+// Section returns the first section whose title matches name (case-insensitive), or nil
+func (s *Spec) Section(name string) *Section {
+	for _, sec := range s.Sections {
+		if strings.EqualFold(strings.TrimSpace(sec.Title), name) {
+			return sec
+		}
+	}
+	return nil
+}
+
+// Requirement returns the functional requirement or success criterion with the given ID, or nil
+func (s *Spec) Requirement(id string) *Requirement {
+	for _, list := range [][]Requirement{s.FunctionalRequirements, s.SuccessCriteria} {
+		for i := range list {
+			if strings.EqualFold(list[i].ID, id) {
+				return &list[i]
+			}
+		}
+	}
+	return nil
+}
+
+// FilledRequirements returns the functional requirements that are not placeholders
+func (s *Spec) FilledRequirements() []Requirement {
+	return filled(s.FunctionalRequirements)
+}
+
+// FilledSuccessCriteria returns the success criteria that are not placeholders
+func (s *Spec) FilledSuccessCriteria() []Requirement {
+	return filled(s.SuccessCriteria)
+}
+
+func filled(reqs []Requirement) []Requirement {
+	var out []Requirement
+	for _, r := range reqs {
+		if !IsPlaceholder(r.Text) {
+			out = append(out, r)
+		}
+	}
+	return out
+}
@@ -1,11 +1,91 @@
 package templates
 
-const PlanTemplate = `Follow the below plan verbatim. Trust the files and references.
+// DefaultPlanTemplate is the name of the template used when none is requested
+const DefaultPlanTemplate = "feature"
+
+// PlanTemplates maps built-in plan template names to their content.
+// Projects can override any of them, or add new ones, with
+// .opusflow/templates/plan-<name>.md
+var PlanTemplates = map[string]string{
+	"feature":  PlanTemplate,
+	"bugfix":   BugfixPlanTemplate,
+	"refactor": RefactorPlanTemplate,
+}
+
+// planHeader is shared by every built-in plan template
+const planHeader = `# {{ .Title }}
+
+Follow the below plan verbatim. Trust the files and references.
 Do not re-verify what's written in the plan.
 
+**Author**: {{ .Author }}
+{{- if .SpecRef }}
+**Spec**: [{{ .SpecRef }}]({{ .SpecLink }})
+{{- end }}
+
 ## Goal
 {{ .Goal }}
+`
+
+// planPrerequisites is shared by every built-in plan template
+const planPrerequisites = `## Pre-requisites
+- **Dependencies**:{{ with .Dependencies }} {{ . }}{{ end }}
+- **Prior Context**:{{ with .Context }} {{ . }}{{ end }}
+- **Environment**:{{ with .Environment }} {{ . }}{{ end }}
+`
+
+// planConstraints lists the spec's architecture constraints, when planning from a spec
+const planConstraints = `{{ if .Constraints }}
+## Architecture Constraints
+{{ range .Constraints }}- {{ . }}
+{{ end }}{{ end }}`
+
+// planSuccessCriteria is shared by every built-in plan template
+const planSuccessCriteria = `## Success Criteria
+- [ ] Build passes{{ if .BuildCommand }}: ` + "`" + `{{ .BuildCommand }}` + "`" + `{{ end }}
+- [ ] Tests pass{{ if .TestCommand }}: ` + "`" + `{{ .TestCommand }}` + "`" + `{{ end }}
+{{ range .SpecCriteria }}- [ ] **{{ .ID }}**: {{ .Text }}
+{{ end }}`
+
+// planStep is the placeholder step shared by every built-in plan template
+const planStep = `### Step 1: [Step Title]
+**File**: ` + "`" + `[Absolute Path]` + "`" + `
+**Action**: [Create/Update/Delete]
+
+**Description**:
+[Detailed description of what to do]
+
+**Changes**:
+- ` + "`" + `[Symbol]` + "`" + `: [Description]
+
+**Verification**:
+- [ ] Automated: ` + "`" + `[Command]` + "`" + `
+- [ ] Manual: [Steps]
+
+---
+(Repeat for other steps)
+---
+`
 
+// planSteps renders one step per spec requirement, or the placeholder step
+const planSteps = `{{ if .Requirements }}{{ range $i, $r := .Requirements }}{{ if $i }}
+{{ end }}### Step {{ inc $i }}: {{ $r.Text }}
+**Implements**: {{ $r.ID }}
+**File**: ` + "`" + `[Absolute Path]` + "`" + `
+**Action**: [Create/Update/Delete]
+
+**Description**:
+[Detailed description of what to do]
+
+**Verification**:
+- [ ] Automated: ` + "`" + `{{ if $.TestCommand }}{{ $.TestCommand }}{{ else }}[Command]{{ end }}` + "`" + `
+- [ ] Manual: [Steps]
+
+---
+{{ end }}{{ else }}` + planStep + `{{ end }}`
+
+// PlanTemplate is the built-in "feature" plan template
+const PlanTemplate = planHeader + `
 ## User Review Required
 > [!IMPORTANT]
 > Critical items requiring user attention before proceeding.
@@ -13,13 +93,9 @@ Do not re-verify what's written in the plan.
 - **Breaking Changes**: None
 - **Risks**: None
 
-## Pre-requisites
-- **Dependencies**: {{ .Dependencies }}
-- **Prior Context**: {{ .Context }}
-- **Environment**: {{ .Environment }}
-
+` + planPrerequisites + planConstraints + `
 ## Observations
-- **Current State**:
+- **Current State**: {{ .CodebaseSummary }}
 - **Missing Components**:
 
 ## Proposed Changes
@@ -31,25 +107,84 @@ Do not re-verify what's written in the plan.
 
 ## Implementation Steps
 
-### Step 1: [Step Title]
+` + planSteps + `
+` + planSuccessCriteria
+
+// BugfixPlanTemplate is the built-in "bugfix" plan template
+const BugfixPlanTemplate = planHeader + `
+` + planPrerequisites + planConstraints + `
+## Observations
+- **Current State**: {{ .CodebaseSummary }}
+- **Symptoms**: [Observed behavior and how to reproduce it]
+- **Root Cause**: [Why the bug happens]
+
+## Proposed Changes
+
+### [Component Name]
+#### [MODIFY | NEW] [File Name]
+- **Reason**: [Why this change is needed]
+- **Complexity**: [Low/Medium/High]
+
+## Implementation Steps
+
+### Step 1: Add a failing regression test
 **File**: ` + "`" + `[Absolute Path]` + "`" + `
-**Action**: [Create/Update/Delete]
+**Action**: Create
 
 **Description**:
-[Detailed description of what to do]
+[Test that reproduces the bug]
 
-**Changes**:
-- ` + "`" + `[Symbol]` + "`" + `: [Description]
+**Verification**:
+- [ ] Automated: ` + "`" + `{{ if .TestCommand }}{{ .TestCommand }}{{ else }}[Command]{{ end }}` + "`" + ` fails before the fix
+
+---
+
+### Step 2: Fix the root cause
+**File**: ` + "`" + `[Absolute Path]` + "`" + `
+**Action**: Update
+
+**Description**:
+[Detailed description of the fix]
+
+{{- if .RequirementIDs }}
+**Implements**: {{ .RequirementIDs }}
+{{- end }}
 
 **Verification**:
-- [ ] Automated: ` + "`" + `[Command]` + "`" + `
-- [ ] Manual: [Steps]
+- [ ] Automated: ` + "`" + `{{ if .TestCommand }}{{ .TestCommand }}{{ else }}[Command]{{ end }}` + "`" + `
+- [ ] Manual: [Steps to confirm the symptom is gone]
 
 ---
 (Repeat for other steps)
 ---
 
-## Success Criteria
-- [ ] Build passes: ` + "`" + `go build ./...` + "`" + `
-- [ ] Tests pass: ` + "`" + `go test ./...` + "`" + `
+` + planSuccessCriteria + `- [ ] Regression test passes
+`
+
+// RefactorPlanTemplate is the built-in "refactor" plan template
+const RefactorPlanTemplate = planHeader + `
+## User Review Required
+> [!IMPORTANT]
+> Behavior must not change. Call out any public API changes here.
+
+- **Breaking Changes**: None
+- **Risks**: None
+
+` + planPrerequisites + planConstraints + `
+## Observations
+- **Current State**: {{ .CodebaseSummary }}
+- **Problems**: [What makes the current code hard to work with]
+- **Target Structure**: [How the code should be organized afterwards]
+
+## Proposed Changes
+
+### [Component Name]
+#### [MODIFY | NEW | DELETE] [File Name]
+- **Reason**: [Why this change is needed]
+- **Complexity**: [Low/Medium/High]
+
+## Implementation Steps
+
+` + planSteps + `
+` + planSuccessCriteria + `- [ ] No behavior changes: existing tests pass unmodified
 `
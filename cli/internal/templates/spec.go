@@ -0,0 +1,329 @@
+package templates
+
+// DefaultSpecTemplate is the name of the spec template used when no type is requested
+const DefaultSpecTemplate = "feature"
+
+// SpecTemplates maps built-in spec template names (work types) to their content.
+// Projects can override any of them, or add new ones, with
+// .opusflow/templates/spec-<name>.md
+var SpecTemplates = map[string]string{
+	"feature":   SpecTemplate,
+	"api":       APISpecTemplate,
+	"cli":       CLISpecTemplate,
+	"library":   LibrarySpecTemplate,
+	"ui":        UISpecTemplate,
+	"bugfix":    BugfixSpecTemplate,
+	"migration": MigrationSpecTemplate,
+}
+
+// specHeader is shared by every built-in spec template. Created date and
+// status live in the frontmatter.
+const specHeader = `# Feature Specification: {{ .Title }}
+
+## Goal
+
+> {{ .Query }}
+
+<!-- Describe the high-level objective. What are we building and why? -->
+
+[TODO: Expand the goal description here with clear business context]
+
+`
+
+// specUserStories is shared by the feature-style spec templates
+const specUserStories = `## User Stories
+
+<!-- Define user stories in the format: As a [role], I want [feature] so that [benefit] -->
+
+- [ ] As a **[User Role]**, I want **[Feature Description]** so that **[Benefit/Value]**
+- [ ] As a **[User Role]**, I want **[Feature Description]** so that **[Benefit/Value]**
+
+`
+
+// specFunctionalRequirements is shared by every built-in spec template
+const specFunctionalRequirements = `## Requirements
+
+### Functional Requirements
+
+<!-- What must the system do? -->
+
+- [ ] **FR1**: [Description of Functional Requirement]
+- [ ] **FR2**: [Description of Functional Requirement]
+- [ ] **FR3**: [Description of Functional Requirement]
+
+`
+
+// specNonFunctionalHeader opens the non-functional requirements table
+const specNonFunctionalHeader = `### Non-Functional Requirements
+
+<!-- Performance, security, scalability constraints -->
+
+| Category | Requirement |
+|----------|-------------|
+`
+
+// specConstraints is shared by every built-in spec template
+const specConstraints = `## Architecture Constraints
+
+<!-- What existing patterns, services, or constraints must be followed? -->
+
+- Must integrate with: [existing service/component]
+- Must follow pattern: [e.g., repository pattern, DI]
+- Must use: [specific technology/library]
+
+`
+
+// specContext renders the codebase summary and the --context files
+const specContext = `{{ if .CodebaseSummary }}## Codebase Context
+
+` + "```" + `
+{{ .CodebaseSummary }}` + "```" + `
+
+{{ end }}{{ if .Context }}## Additional Context
+
+{{ .Context }}
+{{ end }}`
+
+// specEdgeCasesHeader opens the edge case table
+const specEdgeCasesHeader = `## Edge Cases
+
+<!-- Document edge cases and expected behavior -->
+
+| Edge Case | Expected Behavior |
+|-----------|-------------------|
+`
+
+// specOutOfScope is shared by every built-in spec template
+const specOutOfScope = `
+## Out of Scope
+
+<!-- Explicitly list what is NOT included in this feature -->
+
+- ❌ [Feature/capability not included]
+- ❌ [Another exclusion]
+
+`
+
+// specSuccessCriteriaHeader opens the success criteria list
+const specSuccessCriteriaHeader = `## Success Criteria
+
+<!-- Measurable criteria to verify the feature is complete -->
+
+`
+
+// specFooter is shared by every built-in spec template
+const specFooter = `
+## Open Questions
+
+<!-- Questions that need answers before implementation -->
+
+1. [Question about requirement/design]
+2. [Question about integration]
+
+---
+
+> ⚠️ **Note**: This spec must be reviewed and approved before generating a PLAN.md
+> (` + "`" + `opusflow spec submit` + "`" + `, then ` + "`" + `opusflow spec approve` + "`" + ` or ` + "`" + `opusflow spec reject` + "`" + `)
+`
+
+// SpecTemplate is the built-in "feature" spec template for general work
+const SpecTemplate = specHeader + specUserStories + specFunctionalRequirements + specNonFunctionalHeader +
+	`| **Performance** | [e.g., Operation completes in < 1s] |
+| **Security** | [e.g., Input validation required] |
+| **Reliability** | [e.g., Failures are reported, never silently ignored] |
+
+` + specConstraints + specContext + specEdgeCasesHeader +
+	`| [Empty input] | [Return validation error with specific message] |
+| [Duplicate entry] | [Existing entry is kept and the conflict is reported] |
+| [Dependency unavailable] | [Fail with a clear error; no partial writes] |
+` + specOutOfScope + specSuccessCriteriaHeader +
+	`- [ ] **SC1**: [e.g., All unit tests pass with >80% coverage]
+- [ ] **SC2**: [e.g., The feature works end to end for each user story]
+- [ ] **SC3**: [e.g., Documentation updated]
+` + specFooter
+
+// APISpecTemplate is the built-in "api" spec template for web/service APIs
+const APISpecTemplate = specHeader + specUserStories +
+	`## API Contract
+
+<!-- Endpoints, payloads and error responses -->
+
+| Method | Path | Request | Response | Errors |
+|--------|------|---------|----------|--------|
+| [GET] | [/resource/{id}] | [Body/params] | [200 payload] | [404, 422] |
+
+- **Authentication**: [e.g., Bearer token, scopes required]
+- **Versioning**: [e.g., /v1 prefix, backwards compatible changes only]
+
+` + specFunctionalRequirements + specNonFunctionalHeader +
+	`| **Performance** | [e.g., Response time < 200ms] |
+| **Security** | [e.g., Input validation required] |
+| **Scalability** | [e.g., Support 1000 concurrent users] |
+
+` + specConstraints + specContext + specEdgeCasesHeader +
+	`| [Empty input] | [Return validation error with specific message] |
+| [Duplicate entry] | [Return 409 Conflict with resource location] |
+| [Unauthorized access] | [Return 401/403 with auth challenge] |
+` + specOutOfScope + specSuccessCriteriaHeader +
+	`- [ ] **SC1**: [e.g., All unit tests pass with >80% coverage]
+- [ ] **SC2**: [e.g., API returns 200 OK with correct payload]
+- [ ] **SC3**: [e.g., Error cases return appropriate status codes (400, 4xx, 5xx)]
+- [ ] **SC4**: [e.g., Documentation updated in internal wiki]
+` + specFooter
+
+// CLISpecTemplate is the built-in "cli" spec template for command-line tools
+const CLISpecTemplate = specHeader + specUserStories +
+	`## Command Interface
+
+<!-- How the command is invoked and what it prints -->
+
+- **Usage**: ` + "`" + `[tool command <args> [flags]]` + "`" + `
+- **Flags**: [--flag: meaning and default]
+- **Output**: [Human-readable output on stdout; --json for scripts]
+- **Exit Codes**: [0 success, 1 failure, 2 usage error]
+
+` + specFunctionalRequirements + specNonFunctionalHeader +
+	`| **Performance** | [e.g., Completes in < 2s on a typical project] |
+| **Portability** | [e.g., Linux, macOS and Windows] |
+| **Scriptability** | [e.g., Stable exit codes and machine-readable output] |
+
+` + specConstraints + specContext + specEdgeCasesHeader +
+	`| [Missing required argument] | [Print usage to stderr and exit 2] |
+| [File not found] | [Print the path in the error and exit 1] |
+| [Output piped / non-interactive terminal] | [No colors or prompts] |
+` + specOutOfScope + specSuccessCriteriaHeader +
+	`- [ ] **SC1**: [e.g., --help documents every flag with an example]
+- [ ] **SC2**: [e.g., Exit codes match the Command Interface section]
+- [ ] **SC3**: [e.g., Golden-output tests cover text and --json output]
+` + specFooter
+
+// LibrarySpecTemplate is the built-in "library" spec template for packages and SDKs
+const LibrarySpecTemplate = specHeader + specUserStories +
+	`## Public API
+
+<!-- Exported types and functions, with their contracts -->
+
+- ` + "`" + `[FunctionName(args) (result, error)]` + "`" + `: [Behavior, errors returned]
+- ` + "`" + `[TypeName]` + "`" + `: [Purpose, zero-value behavior]
+
+## Compatibility
+
+- **Versioning**: [e.g., Minor release, no breaking changes to exported API]
+- **Deprecations**: [What is deprecated and when it will be removed]
+
+` + specFunctionalRequirements + specNonFunctionalHeader +
+	`| **Compatibility** | [e.g., Supports the two latest language versions] |
+| **Performance** | [e.g., No allocations on the hot path] |
+| **Dependencies** | [e.g., No new third-party dependencies] |
+
+` + specConstraints + specContext + specEdgeCasesHeader +
+	`| [Nil or zero-value input] | [Return an error; never panic] |
+| [Concurrent use] | [Safe for concurrent use, or documented otherwise] |
+| [Context cancelled] | [Return promptly with the context error] |
+` + specOutOfScope + specSuccessCriteriaHeader +
+	`- [ ] **SC1**: [e.g., Every exported symbol is documented with an example]
+- [ ] **SC2**: [e.g., Tests cover every exported function]
+- [ ] **SC3**: [e.g., API compatibility check reports no breaking changes]
+` + specFooter
+
+// UISpecTemplate is the built-in "ui" spec template for user interfaces
+const UISpecTemplate = specHeader + specUserStories +
+	`## User Flows
+
+<!-- Step-by-step flows from entry point to outcome -->
+
+1. [Entry point] → [Steps] → [Outcome]
+2. [Entry point] → [Steps] → [Outcome]
+
+## Design References
+
+- [Link to mockups or design system components]
+
+` + specFunctionalRequirements + specNonFunctionalHeader +
+	`| **Accessibility** | [e.g., WCAG 2.1 AA, full keyboard navigation] |
+| **Performance** | [e.g., Page interactive in < 2.5s] |
+| **Compatibility** | [e.g., Latest two versions of major browsers, mobile widths >= 360px] |
+
+` + specConstraints + specContext + specEdgeCasesHeader +
+	`| [Empty state] | [Show guidance and a primary action] |
+| [Slow network / loading] | [Show a loading indicator; no layout shift] |
+| [Backend error] | [Show a retryable error message; keep user input] |
+` + specOutOfScope + specSuccessCriteriaHeader +
+	`- [ ] **SC1**: [e.g., Each user flow is covered by an end-to-end test]
+- [ ] **SC2**: [e.g., Automated accessibility checks report no violations]
+- [ ] **SC3**: [e.g., Design review sign-off]
+` + specFooter
+
+// BugfixSpecTemplate is the built-in "bugfix" spec template
+const BugfixSpecTemplate = specHeader +
+	`## Current Behavior
+
+<!-- What happens today, including error messages and logs -->
+
+[Observed behavior]
+
+## Expected Behavior
+
+[What should happen instead]
+
+## Reproduction Steps
+
+1. [Step]
+2. [Step]
+3. [Observe the bug]
+
+- **Environment**: [Version, OS, configuration]
+- **Impact**: [Who is affected and how often]
+
+` + specFunctionalRequirements + specConstraints + specContext + specEdgeCasesHeader +
+	`| [Original reproduction] | [Behaves as described in Expected Behavior] |
+| [Closely related input] | [Same fix applies; no new failure] |
+| [Previously working path] | [Behavior unchanged] |
+` + specOutOfScope + specSuccessCriteriaHeader +
+	`- [ ] **SC1**: [e.g., A regression test fails before the fix and passes after it]
+- [ ] **SC2**: [e.g., The reproduction steps no longer show the bug]
+- [ ] **SC3**: [e.g., No new failures in the existing test suite]
+` + specFooter
+
+// MigrationSpecTemplate is the built-in "migration" spec template for data,
+// schema and platform migrations
+const MigrationSpecTemplate = specHeader +
+	`## Current State
+
+[Schema, system or version being migrated from]
+
+## Target State
+
+[Schema, system or version being migrated to]
+
+## Migration Strategy
+
+<!-- Ordered steps, including dual-write or backfill phases -->
+
+1. [Step]
+2. [Step]
+
+## Rollback Plan
+
+- **Trigger**: [Conditions that abort the migration]
+- **Procedure**: [How to restore the previous state]
+
+## Data Validation
+
+- [e.g., Row counts match between source and target]
+- [e.g., Checksums of migrated records match]
+
+` + specFunctionalRequirements + specNonFunctionalHeader +
+	`| **Downtime** | [e.g., Zero downtime, or a maintenance window of 30 minutes] |
+| **Data Integrity** | [e.g., No records lost or duplicated] |
+| **Duration** | [e.g., Backfill completes within 2 hours] |
+
+` + specConstraints + specContext + specEdgeCasesHeader +
+	`| [Migration fails midway] | [Rollback restores the previous state] |
+| [Migration is re-run] | [Idempotent; already migrated data is skipped] |
+| [Writes during migration] | [No data is lost] |
+` + specOutOfScope + specSuccessCriteriaHeader +
+	`- [ ] **SC1**: [e.g., Data validation checks pass on production data]
+- [ ] **SC2**: [e.g., Rollback rehearsed successfully in staging]
+- [ ] **SC3**: [e.g., Old code path removed after cut-over]
+` + specFooter